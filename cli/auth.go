@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/abdo-farag/otc-cli/internal/cache"
+
+	"github.com/fatih/color"
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+)
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Inspect cached authentication sessions",
+}
+
+var authSessionsCmd = &cobra.Command{
+	Use:     "sessions",
+	Aliases: []string{"session", "ls"},
+	Short:   "List all cached sessions",
+	Long: `List every cached login. Sessions are kept per profile, domain, region and IdP,
+so several domains can be logged in at the same time.`,
+	Args: cobra.NoArgs,
+	RunE: runAuthSessions,
+}
+
+func init() {
+	authCmd.AddCommand(authSessionsCmd)
+}
+
+func runAuthSessions(cmd *cobra.Command, args []string) error {
+	// Profiles may use different token stores: list all of them
+	var sessions []*cache.TokenCache
+	locked := 0
+	err := cache.InEachStore(func() error {
+		found, n, err := cache.ListSessions()
+		sessions = append(sessions, found...)
+		locked += n
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].Session().Key() < sessions[j].Session().Key()
+	})
+
+	if locked > 0 {
+		defer color.Yellow("⚠ %d encrypted session(s) not shown: set OTC_CLI_PASSPHRASE to list them", locked)
+	}
+	if len(sessions) == 0 {
+		color.Yellow("⚠ No cached sessions")
+		return nil
+	}
+
+	// Mark the session the active config would use
+	var active string
+	if cfg, err := loadConfig(); err == nil {
+		active = cache.SessionFor(cfg).Key()
+	}
+
+	headerFmt := color.New(color.FgCyan, color.Bold).SprintfFunc()
+	tbl := table.New("Active", "Profile", "Domain", "Region", "IdP", "Status", "Expires")
+	tbl.WithHeaderFormatter(headerFmt)
+
+	for _, s := range sessions {
+		marker := ""
+		if s.Session().Key() == active {
+			marker = "*"
+		}
+
+		idp := s.IdpURL
+		if idp == "" {
+			idp = "-"
+		}
+
+		tbl.AddRow(marker, s.Profile, s.Domain, s.Region, idp, sessionStatus(s), s.ExpiresAt.Format("2006-01-02 15:04"))
+	}
+
+	fmt.Printf("\n")
+	color.Cyan("Cached Sessions")
+	tbl.Print()
+	fmt.Printf("\nTotal: %d sessions\n", len(sessions))
+	return nil
+}
+
+// sessionStatus describes whether a cached session can be used without logging in
+func sessionStatus(s *cache.TokenCache) string {
+	if time.Now().Before(s.ExpiresAt) {
+		return "valid"
+	}
+	if s.RefreshToken != "" && (s.RefreshExpiresAt.IsZero() || time.Now().Before(s.RefreshExpiresAt)) {
+		return "refreshable"
+	}
+	return "expired"
+}
@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"encoding/json"
+
+	"github.com/abdo-farag/otc-cli/internal/commands"
+
+	"github.com/spf13/cobra"
+)
+
+// CCE flags
+var (
+	cceTokenDuration int
+	cceYes           bool
+	nodePoolNodes    int
+	nodePoolMin      int
+	nodePoolMax      int
+	nodePoolDisable  bool
+)
+
+var cceCmd = &cobra.Command{
+	Use:     "cce",
+	Aliases: []string{"cluster"},
+	Short:   "Manage CCE Kubernetes clusters",
+}
+
+var cceTokenCmd = &cobra.Command{
+	Use:   "token [cluster-id-or-name]",
+	Short: "Print a Kubernetes ExecCredential for a CCE cluster",
+	Long: `Print a client.authentication.k8s.io/v1 ExecCredential with a short-lived
+client certificate for the cluster, issued with the cached SSO session.
+
+This command is meant to be called by kubectl through a kubeconfig written
+with "otc-cli get kubeconfig --exec". The credential expires with the session.`,
+	Example: `  otc-cli cce token my-cluster
+  otc-cli get kubeconfig my-cluster --exec --file ~/.kube/otc-config`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCceToken,
+}
+
+var cceHibernateCmd = &cobra.Command{
+	Use:   "hibernate <cluster>",
+	Short: "Hibernate a cluster",
+	Long: `Hibernate a cluster, given by name or ID, to save costs while it is idle.
+Its control plane and nodes stop until "otc-cli cce awake" wakes it.`,
+	Example: `  otc-cli cce hibernate dev
+  otc-cli cce hibernate dev --yes --no-wait`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCceHibernate,
+}
+
+var cceAwakeCmd = &cobra.Command{
+	Use:     "awake <cluster>",
+	Aliases: []string{"wake"},
+	Short:   "Wake a hibernated cluster",
+	Example: `  otc-cli cce awake dev
+  otc-cli cce awake dev --timeout 45m`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCceAwake,
+}
+
+var cceNodePoolCmd = &cobra.Command{
+	Use:     "nodepool",
+	Aliases: []string{"nodepools", "pool"},
+	Short:   "Scale cluster node pools",
+	Long: `Scale the node pools of a cluster. Clusters and pools are given by name
+or ID. Use "otc-cli list nodepool --cluster <cluster>" to see them.`,
+}
+
+var cceNodePoolScaleCmd = &cobra.Command{
+	Use:   "scale <cluster> <pool>",
+	Short: "Set the number of nodes of a pool",
+	Long: `Set the number of nodes of a pool and wait until they are up or removed.
+Pods on removed nodes are evicted. Pools with autoscaling only accept counts
+within their range.`,
+	Example: `  otc-cli cce nodepool scale dev pool-a --nodes 5
+  otc-cli cce nodepool scale dev pool-a --nodes 0 --yes`,
+	Args: cobra.ExactArgs(2),
+	RunE: runCceNodePoolScale,
+}
+
+var cceNodePoolAutoscaleCmd = &cobra.Command{
+	Use:   "autoscale <cluster> <pool>",
+	Short: "Turn autoscaling of a pool on or off",
+	Long: `Let the autoscaler scale a pool between --min and --max nodes, or turn
+autoscaling off with --disable. Autoscaling needs the autoscaler add-on.`,
+	Example: `  otc-cli cce nodepool autoscale dev pool-a --min 1 --max 5
+  otc-cli cce nodepool autoscale dev pool-a --disable`,
+	Args: cobra.ExactArgs(2),
+	RunE: runCceNodePoolAutoscale,
+}
+
+func init() {
+	cceCmd.AddCommand(cceTokenCmd)
+	cceCmd.AddCommand(cceHibernateCmd)
+	cceCmd.AddCommand(cceAwakeCmd)
+	cceCmd.AddCommand(cceNodePoolCmd)
+	cceNodePoolCmd.AddCommand(cceNodePoolScaleCmd)
+	cceNodePoolCmd.AddCommand(cceNodePoolAutoscaleCmd)
+
+	cceTokenCmd.Flags().IntVar(&cceTokenDuration, "duration", 1, "Certificate validity in days")
+
+	cceNodePoolScaleCmd.Flags().IntVar(&nodePoolNodes, "nodes", 0, "Number of nodes")
+	cceNodePoolScaleCmd.MarkFlagRequired("nodes")
+
+	f := cceNodePoolAutoscaleCmd.Flags()
+	f.IntVar(&nodePoolMin, "min", 0, "Minimum number of nodes")
+	f.IntVar(&nodePoolMax, "max", 0, "Maximum number of nodes")
+	f.BoolVar(&nodePoolDisable, "disable", false, "Turn autoscaling off")
+	cceNodePoolAutoscaleCmd.MarkFlagsMutuallyExclusive("min", "disable")
+	cceNodePoolAutoscaleCmd.MarkFlagsMutuallyExclusive("max", "disable")
+	cceNodePoolAutoscaleCmd.MarkFlagsOneRequired("max", "disable")
+
+	for _, cmd := range []*cobra.Command{cceHibernateCmd, cceNodePoolScaleCmd} {
+		cmd.Flags().BoolVarP(&cceYes, "yes", "y", false, "Don't ask for confirmation")
+	}
+	for _, cmd := range []*cobra.Command{cceHibernateCmd, cceAwakeCmd, cceNodePoolScaleCmd} {
+		addJobFlags(cmd)
+	}
+}
+
+func runCceToken(cmd *cobra.Command, args []string) error {
+	// kubectl parses stdout: login prompts and status go to stderr
+	stdout, restore := machineOutput()
+	defer restore()
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	tokenCache, err := ensureAuthenticated(cfg)
+	if err != nil {
+		return err
+	}
+
+	otcClient := newClient(cfg, tokenCache)
+
+	selectedProjectID := selectedProject(cfg)
+	if selectedProjectID != "" {
+		selectedProjectID = resolveProject(otcClient, tokenCache.UnscopedToken, selectedProjectID)
+	}
+
+	credential, err := commands.CCEToken(cfg, otcClient, tokenCache, selectedProjectID, args[0], cceTokenDuration)
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(stdout).Encode(credential)
+}
+
+func runCceHibernate(cmd *cobra.Command, args []string) error {
+	s, err := connect()
+	if err != nil {
+		return err
+	}
+
+	return commands.HibernateCluster(s.cfg, s.client, s.unscopedToken, s.projectID, args[0], cceYes, jobOptions())
+}
+
+func runCceAwake(cmd *cobra.Command, args []string) error {
+	s, err := connect()
+	if err != nil {
+		return err
+	}
+
+	return commands.AwakeCluster(s.cfg, s.client, s.unscopedToken, s.projectID, args[0], jobOptions())
+}
+
+func runCceNodePoolScale(cmd *cobra.Command, args []string) error {
+	if nodePoolNodes < 0 {
+		return usageErrorf("--nodes must not be negative")
+	}
+
+	s, err := connect()
+	if err != nil {
+		return err
+	}
+
+	return commands.ScaleNodePool(s.cfg, s.client, s.unscopedToken, s.projectID, args[0], args[1], nodePoolNodes, cceYes, jobOptions())
+}
+
+func runCceNodePoolAutoscale(cmd *cobra.Command, args []string) error {
+	if !nodePoolDisable && (nodePoolMin < 0 || nodePoolMax < 1 || nodePoolMin > nodePoolMax) {
+		return usageErrorf("need 0 <= --min <= --max and --max >= 1")
+	}
+
+	s, err := connect()
+	if err != nil {
+		return err
+	}
+
+	return commands.AutoscaleNodePool(s.cfg, s.client, s.unscopedToken, s.projectID, args[0], args[1], !nodePoolDisable, nodePoolMin, nodePoolMax)
+}
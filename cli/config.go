@@ -0,0 +1,172 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/abdo-farag/otc-cli/internal/config"
+
+	"github.com/fatih/color"
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage configuration profiles",
+	Long: fmt.Sprintf(`Manage named profiles stored in ~/.otc-cli/config.yaml.
+
+A profile holds the IdP and OTC settings for one domain. Values are resolved
+with precedence: flag > environment variable > profile > default.
+
+Valid keys: %v`, config.ProfileKeys),
+	Example: `  # Configure a profile
+  otc-cli config set idp_url https://idp.example.com --profile prod
+  otc-cli config set domain OTC-EU-DE-00000000001000000001 --profile prod
+
+  # Use a private or hybrid endpoint for one service
+  otc-cli config set endpoints.ecs https://ecs.private.example.com --profile prod
+
+  # Switch the current profile
+  otc-cli config use-profile prod
+
+  # Use a profile for a single command
+  otc-cli list ecs --profile staging
+  OTC_PROFILE=staging otc-cli list ecs`,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set [key] [value]",
+	Short: "Set a value in the selected profile",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runConfigSet,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get [key]",
+	Short: "Print a value from the selected profile",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigGet,
+}
+
+var configListProfilesCmd = &cobra.Command{
+	Use:     "list-profiles",
+	Aliases: []string{"profiles"},
+	Short:   "List configured profiles",
+	Args:    cobra.NoArgs,
+	RunE:    runConfigListProfiles,
+}
+
+var configUseProfileCmd = &cobra.Command{
+	Use:   "use-profile [name]",
+	Short: "Set the current profile",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigUseProfile,
+}
+
+func init() {
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configListProfilesCmd)
+	configCmd.AddCommand(configUseProfileCmd)
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	file, err := config.LoadFile()
+	if err != nil {
+		return err
+	}
+
+	name := file.ResolveProfileName(profileFlag)
+	profile, ok := file.Profiles[name]
+	if !ok {
+		profile = &config.Profile{}
+		file.Profiles[name] = profile
+	}
+
+	if err := profile.Set(args[0], args[1]); err != nil {
+		return err
+	}
+
+	// The first profile created becomes the current one
+	if file.CurrentProfile == "" {
+		file.CurrentProfile = name
+	}
+
+	if err := file.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	color.Green("✓ Set %s in profile '%s'", args[0], name)
+	return nil
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	file, err := config.LoadFile()
+	if err != nil {
+		return err
+	}
+
+	name := file.ResolveProfileName(profileFlag)
+	profile, ok := file.Profiles[name]
+	if !ok {
+		return fmt.Errorf("profile %q not found in %s", name, config.FilePath())
+	}
+
+	value, err := profile.Get(args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(value)
+	return nil
+}
+
+func runConfigListProfiles(cmd *cobra.Command, args []string) error {
+	file, err := config.LoadFile()
+	if err != nil {
+		return err
+	}
+
+	if len(file.Profiles) == 0 {
+		color.Yellow("⚠ No profiles configured in %s", config.FilePath())
+		return nil
+	}
+
+	headerFmt := color.New(color.FgCyan, color.Bold).SprintfFunc()
+	tbl := table.New("Current", "Name", "Domain", "Region", "IdP", "Project")
+	tbl.WithHeaderFormatter(headerFmt)
+
+	for _, name := range file.ProfileNames() {
+		p := file.Profiles[name]
+		current := ""
+		if name == file.CurrentProfile {
+			current = "*"
+		}
+		tbl.AddRow(current, name, p.DomainName, p.Region, p.IdpURL, p.Project)
+	}
+
+	fmt.Printf("\n")
+	color.Cyan("Profiles (%s)", config.FilePath())
+	tbl.Print()
+	fmt.Printf("\nTotal: %d profiles\n", len(file.Profiles))
+	return nil
+}
+
+func runConfigUseProfile(cmd *cobra.Command, args []string) error {
+	file, err := config.LoadFile()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := file.Profiles[args[0]]; !ok {
+		return fmt.Errorf("profile %q not found in %s", args[0], config.FilePath())
+	}
+
+	file.CurrentProfile = args[0]
+	if err := file.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	color.Green("✓ Switched to profile '%s'", args[0])
+	return nil
+}
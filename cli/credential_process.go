@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"encoding/json"
+
+	"github.com/abdo-farag/otc-cli/internal/commands"
+
+	"github.com/spf13/cobra"
+)
+
+var credentialDuration int
+
+var credentialProcessCmd = &cobra.Command{
+	Use:   "credential-process",
+	Short: "Print temporary credentials for AWS SDK credential_process",
+	Long: `Print temporary OTC credentials in the AWS credential_process JSON format,
+so S3-compatible tools can use OBS without sourcing a credentials script.
+
+Credentials are cached in the token store and reused until shortly before they
+expire. All status messages go to stderr; stdout only carries the JSON document.`,
+	Example: `  # ~/.aws/config
+  [profile otc]
+  credential_process = otc-cli credential-process --profile prod --project eu-de_myproject
+  endpoint_url = https://obs.eu-de.otc.t-systems.com
+
+  aws s3 ls --profile otc`,
+	Args: cobra.NoArgs,
+	RunE: runCredentialProcess,
+}
+
+func init() {
+	credentialProcessCmd.Flags().IntVar(&credentialDuration, "duration", 3600, "Credential lifetime in seconds (900-86400)")
+}
+
+func runCredentialProcess(cmd *cobra.Command, args []string) error {
+	// Keep stdout clean for the JSON document: login prompts and status go to stderr
+	stdout, restore := machineOutput()
+	defer restore()
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	tokenCache, err := ensureAuthenticated(cfg)
+	if err != nil {
+		return err
+	}
+
+	otcClient := newClient(cfg, tokenCache)
+
+	selectedProjectID := selectedProject(cfg)
+	if selectedProjectID != "" {
+		selectedProjectID = resolveProject(otcClient, tokenCache.UnscopedToken, selectedProjectID)
+	}
+
+	result, err := commands.CredentialProcess(cfg, otcClient, tokenCache.UnscopedToken, selectedProjectID, credentialDuration)
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(stdout).Encode(result)
+}
@@ -17,17 +17,19 @@ var docsCmd = &cobra.Command{
   otc-cli docs
 
   # Generate with custom output file
-  otc-cli docs --output ./documentation/otc-cli.md`,
+  otc-cli docs --file ./documentation/otc-cli.md`,
 	RunE:   runDocs,
 }
 
 var docsOutput string
 
 func init() {
-	docsCmd.Flags().StringVarP(&docsOutput, "output", "o", "otc-cli.md", "Output file path")
+	docsCmd.Flags().StringVarP(&docsOutput, "file", "f", "otc-cli.md", "Output file path")
 }
 
 func runDocs(cmd *cobra.Command, args []string) error {
+	deprecatedOutputPath(cmd, &docsOutput)
+
 	buf := bytes.NewBufferString("")
 	
 	// Generate markdown documentation
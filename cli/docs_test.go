@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestFileFlagKeepsOutputForFormats(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"default", nil, "otc-cli.md"},
+		{"file", []string{"--file", "a.md"}, "a.md"},
+		{"short file", []string{"-f", "b.md"}, "b.md"},
+		{"format", []string{"-o", "yaml"}, "otc-cli.md"},
+		{"deprecated output path", []string{"--output", "c.md"}, "c.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+
+			// Flags keep their state between runs of the same command tree
+			docsOutput = "otc-cli.md"
+			docsCmd.Flags().Lookup("file").Changed = false
+			rootCmd.PersistentFlags().Lookup("output").Changed = false
+
+			if _, err := execCLI(t, append([]string{"docs"}, tt.args...)...); err != nil {
+				t.Fatal(err)
+			}
+			files, _ := filepath.Glob("*")
+			if len(files) != 1 || files[0] != tt.want {
+				t.Errorf("docs %v wrote %v, want %s", tt.args, files, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package cli
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/abdo-farag/otc-cli/internal/commands"
+	"github.com/abdo-farag/otc-cli/internal/commands/resource"
+
+	"github.com/spf13/cobra"
+)
+
+// Server action flags
+var (
+	ecsActionTag     string
+	ecsActionYes     bool
+	ecsActionHard    bool
+	ecsDeleteEIP     bool
+	ecsDeleteVolumes bool
+	ecsResizeFlavor  string
+)
+
+// Server create flags; they override the fields of a --file spec
+var (
+	ecsCreateFile           string
+	ecsCreateSpec           resource.ServerSpec
+	ecsCreateSecurityGroups []string
+	ecsCreateDataDisks      []string
+	ecsCreateTags           []string
+)
+
+var ecsCmd = &cobra.Command{
+	Use:     "ecs",
+	Aliases: []string{"server", "servers"},
+	Short:   "Manage Elastic Cloud Servers",
+}
+
+var ecsStartCmd = &cobra.Command{
+	Use:   "start [server-name-or-id]...",
+	Short: "Start servers",
+	Example: `  otc-cli ecs start web-1 web-2
+  otc-cli ecs start --tag Environment=staging`,
+	RunE: runServerAction(resource.ActionStart),
+}
+
+var ecsStopCmd = &cobra.Command{
+	Use:   "stop [server-name-or-id]...",
+	Short: "Stop servers",
+	Example: `  otc-cli ecs stop web-1
+  otc-cli ecs stop --tag Environment=staging --yes
+  otc-cli ecs stop web-1 --hard`,
+	RunE: runServerAction(resource.ActionStop),
+}
+
+var ecsRebootCmd = &cobra.Command{
+	Use:   "reboot [server-name-or-id]...",
+	Short: "Reboot servers",
+	Example: `  otc-cli ecs reboot web-1
+  otc-cli ecs reboot web-1 web-2 --hard --yes`,
+	RunE: runServerAction(resource.ActionReboot),
+}
+
+var ecsDeleteCmd = &cobra.Command{
+	Use:   "delete [server-name-or-id]...",
+	Short: "Delete servers",
+	Example: `  otc-cli ecs delete web-1
+  otc-cli ecs delete --tag Environment=ci --delete-eip --delete-volumes --yes`,
+	RunE: runServerAction(resource.ActionDelete),
+}
+
+var ecsResizeCmd = &cobra.Command{
+	Use:   "resize <server-name-or-id>",
+	Short: "Change the flavor of a server",
+	Long: `Change the flavor of a server. Most flavor changes require the server
+to be stopped first.`,
+	Example: `  otc-cli ecs resize web-1 --flavor s3.large.2`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runEcsResize,
+}
+
+var ecsCreateCmd = &cobra.Command{
+	Use:   "create [name]",
+	Short: "Create a server",
+	Long: `Create a server from flags or a YAML spec file, wait until it is running
+and print its addresses. Flags override the fields of the spec file.
+
+Spec file fields: name, flavor, image, vpc, subnet, availability_zone,
+keypair, security_groups, system_disk {size, type}, data_disks [{size, type}],
+eip, eip_bandwidth, tags {key: value} and user_data_file.`,
+	Example: `  otc-cli ecs create web-1 --flavor s3.large.2 --image "Standard_Ubuntu_22.04_latest" \
+    --subnet app-subnet --keypair deploy --security-group web --eip
+
+  otc-cli ecs create --file web.yaml
+  otc-cli ecs create --file web.yaml --name web-2 --data-disk SSD:100 --tag env=staging`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runEcsCreate,
+}
+
+func init() {
+	ecsCmd.AddCommand(ecsCreateCmd)
+	ecsCmd.AddCommand(ecsStartCmd)
+	ecsCmd.AddCommand(ecsStopCmd)
+	ecsCmd.AddCommand(ecsRebootCmd)
+	ecsCmd.AddCommand(ecsDeleteCmd)
+	ecsCmd.AddCommand(ecsResizeCmd)
+
+	for _, cmd := range []*cobra.Command{ecsStartCmd, ecsStopCmd, ecsRebootCmd, ecsDeleteCmd} {
+		cmd.Flags().StringVar(&ecsActionTag, "tag", "", "Also act on all servers with this tag (key or key=value)")
+	}
+	for _, cmd := range []*cobra.Command{ecsStopCmd, ecsRebootCmd, ecsDeleteCmd, ecsResizeCmd} {
+		cmd.Flags().BoolVarP(&ecsActionYes, "yes", "y", false, "Don't ask for confirmation")
+	}
+	ecsStopCmd.Flags().BoolVar(&ecsActionHard, "hard", false, "Force stop instead of a graceful shutdown")
+	ecsRebootCmd.Flags().BoolVar(&ecsActionHard, "hard", false, "Force reboot instead of a graceful restart")
+	ecsDeleteCmd.Flags().BoolVar(&ecsDeleteEIP, "delete-eip", false, "Release EIPs bound to the servers")
+	ecsDeleteCmd.Flags().BoolVar(&ecsDeleteVolumes, "delete-volumes", false, "Delete data disks attached to the servers")
+
+	ecsResizeCmd.Flags().StringVar(&ecsResizeFlavor, "flavor", "", "New flavor (e.g., s3.large.2)")
+	ecsResizeCmd.MarkFlagRequired("flavor")
+
+	for _, cmd := range []*cobra.Command{ecsCreateCmd, ecsStartCmd, ecsStopCmd, ecsRebootCmd, ecsDeleteCmd, ecsResizeCmd} {
+		addJobFlags(cmd)
+	}
+
+	f := ecsCreateCmd.Flags()
+	f.StringVarP(&ecsCreateFile, "file", "f", "", "YAML spec file")
+	f.StringVar(&ecsCreateSpec.Name, "name", "", "Server name")
+	f.StringVar(&ecsCreateSpec.Flavor, "flavor", "", "Flavor (see: otc-cli list flavor)")
+	f.StringVar(&ecsCreateSpec.Image, "image", "", "Image name or ID (see: otc-cli list image)")
+	f.StringVar(&ecsCreateSpec.VPC, "vpc", "", "VPC name or ID (default: the subnet's VPC)")
+	f.StringVar(&ecsCreateSpec.Subnet, "subnet", "", "Subnet name or ID")
+	f.StringVar(&ecsCreateSpec.AvailabilityZone, "az", "", "Availability zone (default: the subnet's AZ)")
+	f.StringVar(&ecsCreateSpec.Keypair, "keypair", "", "SSH keypair name")
+	f.StringSliceVar(&ecsCreateSecurityGroups, "security-group", nil, "Security group name or ID (repeatable)")
+	f.IntVar(&ecsCreateSpec.SystemDisk.Size, "system-disk-size", 0, "System disk size in GB (default: 40 or the image minimum)")
+	f.StringVar(&ecsCreateSpec.SystemDisk.Type, "system-disk-type", "", "System disk type: SATA, SAS, SSD, ... (default SSD)")
+	f.StringArrayVar(&ecsCreateDataDisks, "data-disk", nil, "Data disk as [TYPE:]SIZE, e.g. SSD:100 (repeatable)")
+	f.BoolVar(&ecsCreateSpec.EIP, "eip", false, "Allocate and bind a new EIP")
+	f.IntVar(&ecsCreateSpec.EIPBandwidth, "eip-bandwidth", 0, "EIP bandwidth in Mbit/s (default 10)")
+	f.StringArrayVar(&ecsCreateTags, "tag", nil, "Tag as key=value (repeatable)")
+	f.StringVar(&ecsCreateSpec.UserDataFile, "user-data", "", "File with user data (e.g. cloud-init)")
+}
+
+// runServerAction returns the RunE of a batch server action
+func runServerAction(action string) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && ecsActionTag == "" {
+			return usageErrorf("specify servers by name or ID, or select them with --tag")
+		}
+
+		s, err := connect()
+		if err != nil {
+			return err
+		}
+
+		opts := resource.ServerActionOptions{
+			Hard:          ecsActionHard,
+			DeleteEIP:     ecsDeleteEIP,
+			DeleteVolumes: ecsDeleteVolumes,
+		}
+		return commands.ServerAction(s.cfg, s.client, s.unscopedToken, s.projectID, action, args, ecsActionTag, opts, ecsActionYes, jobOptions())
+	}
+}
+
+func runEcsResize(cmd *cobra.Command, args []string) error {
+	s, err := connect()
+	if err != nil {
+		return err
+	}
+
+	return commands.ResizeServer(s.cfg, s.client, s.unscopedToken, s.projectID, args[0], ecsResizeFlavor, ecsActionYes, jobOptions())
+}
+
+func runEcsCreate(cmd *cobra.Command, args []string) error {
+	spec, err := createSpec(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	s, err := connect()
+	if err != nil {
+		return err
+	}
+
+	return commands.CreateServer(s.cfg, s.client, s.unscopedToken, s.projectID, *spec, jobOptions())
+}
+
+// createSpec merges the --file spec with the flags that were set
+func createSpec(cmd *cobra.Command, args []string) (*resource.ServerSpec, error) {
+	spec := &resource.ServerSpec{}
+	if ecsCreateFile != "" {
+		var err error
+		if spec, err = resource.LoadServerSpec(ecsCreateFile); err != nil {
+			return nil, &usageError{err: err}
+		}
+	}
+
+	flags := cmd.Flags()
+	set := func(name string, field *string, value string) {
+		if flags.Changed(name) {
+			*field = value
+		}
+	}
+	set("name", &spec.Name, ecsCreateSpec.Name)
+	set("flavor", &spec.Flavor, ecsCreateSpec.Flavor)
+	set("image", &spec.Image, ecsCreateSpec.Image)
+	set("vpc", &spec.VPC, ecsCreateSpec.VPC)
+	set("subnet", &spec.Subnet, ecsCreateSpec.Subnet)
+	set("az", &spec.AvailabilityZone, ecsCreateSpec.AvailabilityZone)
+	set("keypair", &spec.Keypair, ecsCreateSpec.Keypair)
+	set("system-disk-type", &spec.SystemDisk.Type, ecsCreateSpec.SystemDisk.Type)
+	set("user-data", &spec.UserDataFile, ecsCreateSpec.UserDataFile)
+	if len(args) > 0 {
+		spec.Name = args[0]
+	}
+
+	if flags.Changed("system-disk-size") {
+		spec.SystemDisk.Size = ecsCreateSpec.SystemDisk.Size
+	}
+	if flags.Changed("eip") {
+		spec.EIP = ecsCreateSpec.EIP
+	}
+	if flags.Changed("eip-bandwidth") {
+		spec.EIPBandwidth = ecsCreateSpec.EIPBandwidth
+		spec.EIP = true
+	}
+	if flags.Changed("security-group") {
+		spec.SecurityGroups = ecsCreateSecurityGroups
+	}
+
+	if flags.Changed("data-disk") {
+		spec.DataDisks = nil
+		for _, value := range ecsCreateDataDisks {
+			disk := resource.DiskSpec{}
+			sizeStr := value
+			if volumeType, size, ok := strings.Cut(value, ":"); ok {
+				disk.Type, sizeStr = volumeType, size
+			}
+			size, err := strconv.Atoi(sizeStr)
+			if err != nil || size <= 0 {
+				return nil, usageErrorf("invalid --data-disk %q: expected [TYPE:]SIZE", value)
+			}
+			disk.Size = size
+			spec.DataDisks = append(spec.DataDisks, disk)
+		}
+	}
+
+	if flags.Changed("tag") {
+		if spec.Tags == nil {
+			spec.Tags = map[string]string{}
+		}
+		for _, tag := range ecsCreateTags {
+			key, value, ok := strings.Cut(tag, "=")
+			if !ok || key == "" {
+				return nil, usageErrorf("invalid --tag %q: expected key=value", tag)
+			}
+			spec.Tags[key] = value
+		}
+	}
+
+	if err := spec.Validate(); err != nil {
+		return nil, &usageError{err: err}
+	}
+	return spec, nil
+}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"github.com/abdo-farag/otc-cli/internal/commands"
+	"github.com/abdo-farag/otc-cli/internal/commands/resource"
+
+	"github.com/spf13/cobra"
+)
+
+// EIP flags
+var (
+	eipAllocateSpec resource.EIPSpec
+	eipReleaseForce bool
+	eipReleaseYes   bool
+)
+
+var eipCmd = &cobra.Command{
+	Use:     "eip",
+	Aliases: []string{"eips", "publicip"},
+	Short:   "Manage Elastic IPs",
+	Long: `Allocate, associate and release Elastic IPs. EIPs are given by ID,
+address or name. Use "otc-cli list eip" to see them.`,
+}
+
+var eipAllocateCmd = &cobra.Command{
+	Use:   "allocate",
+	Short: "Allocate an EIP",
+	Example: `  otc-cli eip allocate --bandwidth 100
+  otc-cli eip allocate --bandwidth 10 --name bastion`,
+	Args: cobra.NoArgs,
+	RunE: runEipAllocate,
+}
+
+var eipAssociateCmd = &cobra.Command{
+	Use:     "associate <eip> <server-or-port>",
+	Aliases: []string{"bind"},
+	Short:   "Associate an EIP with a server or port",
+	Long: `Associate an EIP with a server, given by name or ID, or with a port ID.
+Servers with more than one NIC need the port ID.`,
+	Example: `  otc-cli eip associate 80.158.1.2 web-1
+  otc-cli eip associate bastion 5a7b1c2d-3e4f-4a5b-8c9d-0e1f2a3b4c5d`,
+	Args: cobra.ExactArgs(2),
+	RunE: runEipAssociate,
+}
+
+var eipDisassociateCmd = &cobra.Command{
+	Use:     "disassociate <eip>",
+	Aliases: []string{"unbind"},
+	Short:   "Disassociate an EIP from its server or port",
+	Example: `  otc-cli eip disassociate 80.158.1.2`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runEipDisassociate,
+}
+
+var eipReleaseCmd = &cobra.Command{
+	Use:     "release <eip>...",
+	Aliases: []string{"delete"},
+	Short:   "Release EIPs and their bandwidth",
+	Example: `  otc-cli eip release 80.158.1.2
+  otc-cli eip release bastion --force --yes`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runEipRelease,
+}
+
+func init() {
+	eipCmd.AddCommand(eipAllocateCmd)
+	eipCmd.AddCommand(eipAssociateCmd)
+	eipCmd.AddCommand(eipDisassociateCmd)
+	eipCmd.AddCommand(eipReleaseCmd)
+
+	f := eipAllocateCmd.Flags()
+	f.IntVar(&eipAllocateSpec.Bandwidth, "bandwidth", 0, "Bandwidth in Mbit/s")
+	f.StringVar(&eipAllocateSpec.Name, "name", "", "Name of the EIP and its bandwidth (default bandwidth name: bandwidth-<UTC timestamp>)")
+	f.StringVar(&eipAllocateSpec.Type, "type", "5_bgp", "EIP type")
+	eipAllocateCmd.MarkFlagRequired("bandwidth")
+
+	eipReleaseCmd.Flags().BoolVar(&eipReleaseForce, "force", false, "Disassociate EIPs that are still associated, then release them")
+	eipReleaseCmd.Flags().BoolVarP(&eipReleaseYes, "yes", "y", false, "Don't ask for confirmation")
+}
+
+func runEipAllocate(cmd *cobra.Command, args []string) error {
+	if eipAllocateSpec.Bandwidth < 1 || eipAllocateSpec.Bandwidth > 1000 {
+		return usageErrorf("--bandwidth must be between 1 and 1000 Mbit/s")
+	}
+
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+	defer statusToStderr(printer)()
+
+	s, err := connect()
+	if err != nil {
+		return err
+	}
+
+	return commands.AllocateEIP(s.cfg, s.client, s.unscopedToken, s.projectID, eipAllocateSpec, printer)
+}
+
+func runEipAssociate(cmd *cobra.Command, args []string) error {
+	s, err := connect()
+	if err != nil {
+		return err
+	}
+
+	return commands.AssociateEIP(s.cfg, s.client, s.unscopedToken, s.projectID, args[0], args[1])
+}
+
+func runEipDisassociate(cmd *cobra.Command, args []string) error {
+	s, err := connect()
+	if err != nil {
+		return err
+	}
+
+	return commands.DisassociateEIP(s.cfg, s.client, s.unscopedToken, s.projectID, args[0])
+}
+
+func runEipRelease(cmd *cobra.Command, args []string) error {
+	s, err := connect()
+	if err != nil {
+		return err
+	}
+
+	return commands.ReleaseEIPs(s.cfg, s.client, s.unscopedToken, s.projectID, args, eipReleaseForce, eipReleaseYes)
+}
@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/abdo-farag/otc-cli/internal/commands"
+	"github.com/abdo-farag/otc-cli/internal/otc"
+
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of otc-cli. Scripts can rely on them; don't renumber.
+const (
+	ExitOK          = 0   // Success
+	ExitError       = 1   // Any other failure (network, config, local files)
+	ExitUsage       = 2   // Unknown command, invalid flag, argument or output format, or --yes missing without a terminal
+	ExitAuth        = 3   // Login failed or the token was rejected (HTTP 401)
+	ExitNotFound    = 4   // The resource doesn't exist (HTTP 404)
+	ExitPermission  = 5   // Permission denied (HTTP 403)
+	ExitAPI         = 6   // Any other error returned by an OTC service, a failed job or a resource in an error state
+	ExitTimeout     = 7   // Timed out waiting for a job or resource
+	ExitInterrupted = 130 // Interrupted with Ctrl-C
+)
+
+// exitCodesHelp documents the exit codes in the root command's help
+const exitCodesHelp = `Exit codes:
+  0    success
+  1    other failure (network, config, local files)
+  2    usage error (unknown command, invalid flag, argument or output format,
+       or a confirmation that needs --yes without a terminal)
+  3    authentication failure (login failed or token rejected)
+  4    resource not found
+  5    permission denied
+  6    other API error, failed job or resource in an error state
+  7    timed out waiting
+  130  interrupted`
+
+// ExitCode returns the exit code for an error returned by Execute
+func ExitCode(err error) int {
+	var usage *usageError
+	var auth *authError
+	var apiErr *otc.APIError
+
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.As(err, &usage), errors.Is(err, commands.ErrConfirmationRequired):
+		return ExitUsage
+	case errors.Is(err, context.Canceled):
+		return ExitInterrupted
+	case errors.As(err, &auth), errors.Is(err, otc.ErrUnauthorized):
+		return ExitAuth
+	case errors.Is(err, otc.ErrNotFound):
+		return ExitNotFound
+	case errors.Is(err, otc.ErrForbidden):
+		return ExitPermission
+	case errors.Is(err, otc.ErrTimeout):
+		return ExitTimeout
+	case errors.As(err, &apiErr), errors.Is(err, otc.ErrFailed):
+		return ExitAPI
+	}
+	return ExitError
+}
+
+// usageError is an invalid command line. Execute adds a pointer to the
+// command's help.
+type usageError struct {
+	err     error
+	command string
+}
+
+func (e *usageError) Error() string {
+	if e.command == "" {
+		return e.err.Error()
+	}
+	return fmt.Sprintf("%v\nRun '%s --help' for usage.", e.err, e.command)
+}
+
+func (e *usageError) Unwrap() error {
+	return e.err
+}
+
+// usageErrorf reports an invalid flag or argument found by a command itself
+func usageErrorf(format string, args ...interface{}) error {
+	return &usageError{err: fmt.Errorf(format, args...)}
+}
+
+// authError is a failure to log in or to refresh the session
+type authError struct {
+	err error
+}
+
+func (e *authError) Error() string {
+	return e.err.Error()
+}
+
+func (e *authError) Unwrap() error {
+	return e.err
+}
+
+// trackRun wraps the RunE of cmd and its subcommands to record that a command
+// started running. Errors returned before that are cobra's own usage errors.
+func trackRun(cmd *cobra.Command, started *bool) {
+	if runE := cmd.RunE; runE != nil {
+		cmd.RunE = func(cmd *cobra.Command, args []string) error {
+			*started = true
+			return runE(cmd, args)
+		}
+	}
+	for _, sub := range cmd.Commands() {
+		trackRun(sub, started)
+	}
+}
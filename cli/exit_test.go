@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/abdo-farag/otc-cli/internal/commands"
+	"github.com/abdo-farag/otc-cli/internal/otc"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, ExitOK},
+		{errors.New("disk full"), ExitError},
+		{usageErrorf("invalid --tag"), ExitUsage},
+		{commands.ErrConfirmationRequired, ExitUsage},
+		{fmt.Errorf("delete web-1: %w", commands.ErrConfirmationRequired), ExitUsage},
+		{fmt.Errorf("server web-1 %w", otc.ErrNotFound), ExitNotFound},
+		{fmt.Errorf("job: %w", otc.ErrTimeout), ExitTimeout},
+		{context.Canceled, ExitInterrupted},
+	}
+
+	for _, tt := range tests {
+		if got := ExitCode(tt.err); got != tt.want {
+			t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
@@ -2,14 +2,17 @@ package cli
 
 import (
   "github.com/abdo-farag/otc-cli/internal/commands"
-  "github.com/abdo-farag/otc-cli/internal/config"
-  "github.com/abdo-farag/otc-cli/internal/otc"
 
   "github.com/spf13/cobra"
 )
 
 var (
-  getOutputPath string
+  getOutputPath      string
+  getKubeExec        bool
+  getKubeMerge       bool
+  getKubeContextName string
+  getKubeSetCurrent  bool
+  getKubeEndpoint    string
 )
 
 var getCmd = &cobra.Command{
@@ -19,8 +22,11 @@ var getCmd = &cobra.Command{
   Example: `  # Get ECS instance details
   otc-cli get ecs my-server
 
+  # Print a single field
+  otc-cli get ecs my-server -o jsonpath='{.addresses}'
+
   # Get kubeconfig for cluster
-  otc-cli get kubeconfig my-cluster --output ~/.kube/config`,
+  otc-cli get kubeconfig my-cluster --file ~/.kube/config`,
 }
 
 var getEcsCmd = &cobra.Command{
@@ -30,7 +36,8 @@ var getEcsCmd = &cobra.Command{
   Args:    cobra.ExactArgs(1),
   Example: `  otc-cli get ecs abc123
   otc-cli get ecs my-server --project Production
-  otc-cli get ecs abc123 --json`,
+  otc-cli get ecs abc123 -o yaml
+  otc-cli get ecs abc123 -o jsonpath='{.status}'`,
   RunE: runGetEcs,
 }
 
@@ -53,6 +60,26 @@ var getSubnetCmd = &cobra.Command{
   RunE: runGetSubnet,
 }
 
+var getSecgroupCmd = &cobra.Command{
+  Use:     "secgroup [group-id-or-name]",
+  Aliases: []string{"security-group", "sg"},
+  Short:   "Get security group details and rules",
+  Args:    cobra.ExactArgs(1),
+  Example: `  otc-cli get secgroup web
+  otc-cli get secgroup web -o wide`,
+  RunE: runGetSecgroup,
+}
+
+var getEipCmd = &cobra.Command{
+  Use:     "eip [eip-address-id-or-name]",
+  Aliases: []string{"publicip"},
+  Short:   "Get Elastic IP details",
+  Args:    cobra.ExactArgs(1),
+  Example: `  otc-cli get eip 80.158.1.2
+  otc-cli get eip bastion -o yaml`,
+  RunE: runGetEip,
+}
+
 var getVolumeCmd = &cobra.Command{
   Use:     "volume [volume-id-or-name]",
   Aliases: []string{"volumes"},
@@ -62,6 +89,15 @@ var getVolumeCmd = &cobra.Command{
   RunE: runGetVolume,
 }
 
+var getSnapshotCmd = &cobra.Command{
+  Use:     "snapshot [snapshot-id-or-name]",
+  Aliases: []string{"snapshots", "snap"},
+  Short:   "Get volume snapshot details",
+  Args:    cobra.ExactArgs(1),
+  Example: `  otc-cli get snapshot before-upgrade`,
+  RunE: runGetSnapshot,
+}
+
 var getCceCmd = &cobra.Command{
   Use:     "cce [cluster-id-or-name]",
   Aliases: []string{"cluster"},
@@ -83,7 +119,16 @@ var getKubeconfigCmd = &cobra.Command{
   otc-cli get kubeconfig c8198b6d-7633-4afc-9ec5-ab97bcd94ab8
 
   # Save to custom path
-  otc-cli get kubeconfig my-cluster --output ~/.kube/otc-config`,
+  otc-cli get kubeconfig my-cluster --file ~/.kube/otc-config
+
+  # Authenticate through the SSO session instead of embedded certificates
+  otc-cli get kubeconfig my-cluster --exec
+
+  # Merge into ~/.kube/config (or $KUBECONFIG) and switch to the new context
+  otc-cli get kubeconfig my-cluster --exec --merge --set-current
+
+  # Use the public endpoint under a custom context name
+  otc-cli get kubeconfig my-cluster --merge --endpoint external --context-name prod`,
   RunE: runGetKubeconfig,
 }
 
@@ -92,12 +137,20 @@ func init() {
   getCmd.AddCommand(getEcsCmd)
   getCmd.AddCommand(getVpcCmd)
   getCmd.AddCommand(getSubnetCmd)
+  getCmd.AddCommand(getSecgroupCmd)
+  getCmd.AddCommand(getEipCmd)
   getCmd.AddCommand(getVolumeCmd)
+  getCmd.AddCommand(getSnapshotCmd)
   getCmd.AddCommand(getCceCmd)
   getCmd.AddCommand(getKubeconfigCmd)
 
   // Kubeconfig-specific flags
-  getKubeconfigCmd.Flags().StringVarP(&getOutputPath, "output", "o", "./kubeconfig", "Output path for kubeconfig file")
+  getKubeconfigCmd.Flags().StringVarP(&getOutputPath, "file", "f", "./kubeconfig", "Path of the kubeconfig file (with --merge: kubeconfig to merge into)")
+  getKubeconfigCmd.Flags().BoolVar(&getKubeExec, "exec", false, "Use 'otc-cli cce token' as exec credential plugin instead of static certificates")
+  getKubeconfigCmd.Flags().BoolVar(&getKubeMerge, "merge", false, "Merge into $KUBECONFIG or ~/.kube/config instead of writing a new file")
+  getKubeconfigCmd.Flags().StringVar(&getKubeContextName, "context-name", "", "Context name (default: otc-<project>-<cluster>; without it, --endpoint, --exec or --merge the kubeconfig is saved as downloaded)")
+  getKubeconfigCmd.Flags().BoolVar(&getKubeSetCurrent, "set-current", false, "Make the merged context the current context")
+  getKubeconfigCmd.Flags().StringVar(&getKubeEndpoint, "endpoint", "", "Cluster endpoint: internal or external (default: as marked current by CCE, internal with --exec)")
 }
 
 func runGetEcs(cmd *cobra.Command, args []string) error {
@@ -112,37 +165,61 @@ func runGetSubnet(cmd *cobra.Command, args []string) error {
   return runGetResource("subnet", args[0], map[string]interface{}{})
 }
 
+func runGetSecgroup(cmd *cobra.Command, args []string) error {
+  return runGetResource("secgroup", args[0], map[string]interface{}{})
+}
+
+func runGetEip(cmd *cobra.Command, args []string) error {
+  return runGetResource("eip", args[0], map[string]interface{}{})
+}
+
 func runGetVolume(cmd *cobra.Command, args []string) error {
   return runGetResource("volume", args[0], map[string]interface{}{})
 }
 
+func runGetSnapshot(cmd *cobra.Command, args []string) error {
+  return runGetResource("snapshot", args[0], map[string]interface{}{})
+}
+
 func runGetCce(cmd *cobra.Command, args []string) error {
   return runGetResource("cce", args[0], map[string]interface{}{})
 }
 
 func runGetKubeconfig(cmd *cobra.Command, args []string) error {
+  if getKubeEndpoint != "" && getKubeEndpoint != "internal" && getKubeEndpoint != "external" {
+    return usageErrorf("invalid endpoint %q: must be internal or external", getKubeEndpoint)
+  }
+
+  // With --merge the target defaults to $KUBECONFIG or ~/.kube/config
+  outputPath := getOutputPath
+  if getKubeMerge && !cmd.Flags().Changed("file") {
+    outputPath = ""
+  }
+  deprecatedOutputPath(cmd, &outputPath)
+
   options := map[string]interface{}{
-    "output": getOutputPath,
+    "output":       outputPath,
+    "exec":         getKubeExec,
+    "merge":        getKubeMerge,
+    "context-name": getKubeContextName,
+    "set-current":  getKubeSetCurrent,
+    "endpoint":     getKubeEndpoint,
   }
   return runGetResource("kubeconfig", args[0], options)
 }
 
 func runGetResource(resourceType, resourceID string, options map[string]interface{}) error {
-  cfg := config.New()
-
-  // Authenticate
-  tokenCache, err := ensureAuthenticated(cfg)
+  printer, err := newPrinter()
   if err != nil {
     return err
   }
+  defer statusToStderr(printer)()
 
-  // Resolve project
-  selectedProjectID := projectFlag
-  if selectedProjectID != "" {
-    selectedProjectID = resolveProject(cfg, tokenCache.UnscopedToken, selectedProjectID)
+  s, err := connect()
+  if err != nil {
+    return err
   }
 
   // Execute get command
-  otcClient := otc.NewClient(cfg)
-  return commands.GetCommand(cfg, otcClient, tokenCache.UnscopedToken, resourceType, resourceID, selectedProjectID, options, rawFlag)
+  return commands.GetCommand(s.cfg, s.client, s.unscopedToken, resourceType, resourceID, s.projectID, options, printer)
 }
@@ -1,20 +1,83 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/abdo-farag/otc-cli/internal/auth"
 	"github.com/abdo-farag/otc-cli/internal/cache"
+	"github.com/abdo-farag/otc-cli/internal/commands"
 	"github.com/abdo-farag/otc-cli/internal/config"
 	"github.com/abdo-farag/otc-cli/internal/otc"
+	"github.com/abdo-farag/otc-cli/internal/output"
 
 	"github.com/fatih/color"
+	"github.com/spf13/cobra"
 )
 
-// ensureAuthenticated checks for a valid cached token or performs authentication
+// loadConfig loads the config for the selected profile (flag > env > profile > default)
+// and selects the profile's token store
+func loadConfig() (*config.Config, error) {
+	cfg, err := config.Load(profileFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := cache.NewStore(cfg.TokenStore)
+	if err != nil {
+		return nil, err
+	}
+	cache.UseStore(store)
+
+	return cfg, nil
+}
+
+// selectedProject returns the project from --project, falling back to the profile default
+func selectedProject(cfg *config.Config) string {
+	if projectFlag != "" {
+		return projectFlag
+	}
+	return cfg.Project
+}
+
+// machineOutput redirects status messages (including login prompts) to stderr
+// for commands whose stdout is parsed by other tools. It returns the original
+// stdout and a function restoring it.
+func machineOutput() (*os.File, func()) {
+	stdout := os.Stdout
+	output := color.Output
+	os.Stdout = os.Stderr
+	color.Output = color.Error
+	return stdout, func() {
+		os.Stdout = stdout
+		color.Output = output
+	}
+}
+
+// statusToStderr sends status messages to stderr while a machine-readable
+// format is printed, so stdout carries nothing but the result. The printer
+// keeps writing to the real stdout. It returns a function restoring stdout.
+func statusToStderr(printer *output.Printer) func() {
+	if printer.Human() {
+		return func() {}
+	}
+	_, restore := machineOutput()
+	return restore
+}
+
+// ensureAuthenticated checks for a valid cached token, refreshes it silently
+// when possible, or performs interactive authentication
 func ensureAuthenticated(cfg *config.Config) (*cache.TokenCache, error) {
-	tokenCache, err := cache.LoadToken()
+	tokenCache, err := cache.ReadToken(cache.SessionFor(cfg))
+	if errors.Is(err, cache.ErrWrongPassphrase) {
+		return nil, err
+	}
+	if errors.Is(err, cache.ErrSessionMismatch) {
+		color.Yellow("⚠ %v", err)
+	}
 	if err == nil {
 		// Check if token is still valid (with 5 minute buffer)
 		if time.Now().Add(5 * time.Minute).Before(tokenCache.ExpiresAt) {
@@ -22,35 +85,49 @@ func ensureAuthenticated(cfg *config.Config) (*cache.TokenCache, error) {
 			return tokenCache, nil
 		}
 		color.Yellow("⚠ Cached token expired or expiring soon")
+
+		if tokenCache.RefreshToken != "" {
+			color.Yellow("⏳ Refreshing session...")
+			refreshed, err := auth.RefreshSession(cfg, tokenCache)
+			if err == nil {
+				color.Green("✓ Session refreshed (expires: %s)", refreshed.ExpiresAt.Format("2006-01-02 15:04"))
+				return refreshed, nil
+			}
+			if !errors.Is(err, auth.ErrRefreshRejected) {
+				return nil, &authError{fmt.Errorf("failed to refresh session: %w", err)}
+			}
+			color.Yellow("⚠ Refresh token no longer valid")
+		}
 	}
 
 	color.Yellow("⚠ No valid cached token found. Running login...")
 
 	authClient := auth.NewClient(cfg)
-	tokenResp, handler, err := authClient.GetOIDCToken()
+	authClient.SetContext(commandContext())
+	tokenResp, handler, err := authClient.Authenticate()
 	if err != nil {
 		if handler != nil {
 			handler.SetValidationStatus("failed", "Authentication failed: "+err.Error())
 			time.Sleep(2 * time.Second)
 			handler.Close()
 		}
-		return nil, fmt.Errorf("authentication failed: %w", err)
+		return nil, &authError{fmt.Errorf("authentication failed: %w", err)}
 	}
 
 	if handler != nil {
 		handler.SetValidationStatus("success", "Authentication successful! Validating OTC access...")
+		color.Green("✓ Authorization code received")
 	}
-	color.Green("✓ Authorization code received")
 
 	otcClient := otc.NewClient(cfg)
-	unscopedToken, err := otcClient.GetUnscopedToken(tokenResp.IDToken, handler)
+	unscopedToken, expiresAt, err := otcClient.GetUnscopedToken(tokenResp.IDToken, commands.StatusSetter(handler))
 	if err != nil {
 		if handler != nil {
 			handler.SetValidationStatus("failed", "Failed to validate OTC access: "+err.Error())
 			time.Sleep(2 * time.Second)
 			handler.Close()
 		}
-		return nil, fmt.Errorf("failed to get unscoped token: %w", err)
+		return nil, &authError{fmt.Errorf("failed to get unscoped token: %w", err)}
 	}
 
 	if handler != nil {
@@ -60,22 +137,7 @@ func ensureAuthenticated(cfg *config.Config) (*cache.TokenCache, error) {
 		handler.Close()
 	}
 
-	// Calculate proper expiry time
-	// OIDC tokens typically last 1 hour, but OpenStack unscoped tokens last 24 hours
-	// Use the shorter of the two to be safe
-	expiresAt := time.Now().Add(1 * time.Hour) // Conservative: use OIDC token expiry
-	if tokenResp.ExpiresIn > 0 {
-		expiresAt = time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
-	}
-
-	tokenCache = &cache.TokenCache{
-		UnscopedToken: unscopedToken,
-		IDToken:       tokenResp.IDToken,
-		RefreshToken:  tokenResp.RefreshToken,
-		ExpiresAt:     expiresAt,
-		Domain:        cfg.DomainName,
-		Region:        cfg.Region,
-	}
+	tokenCache = auth.NewTokenCache(cfg, tokenResp, unscopedToken, expiresAt)
 	cache.SaveToken(tokenCache)
 	color.Green("✓ Authenticated and cached token")
 
@@ -83,8 +145,7 @@ func ensureAuthenticated(cfg *config.Config) (*cache.TokenCache, error) {
 }
 
 // resolveProject resolves a project name or ID to a project ID
-func resolveProject(cfg *config.Config, unscopedToken, projectID string) string {
-	otcClient := otc.NewClient(cfg)
+func resolveProject(otcClient *otc.Client, unscopedToken, projectID string) string {
 	domainToken, err := otcClient.GetDomainScopedToken(unscopedToken)
 	if err != nil {
 		color.Yellow("⚠ Failed to get domain token for project resolution")
@@ -119,4 +180,90 @@ func resolveProject(cfg *config.Config, unscopedToken, projectID string) string
 
 	color.Yellow("⚠ No projects found")
 	return ""
-}
\ No newline at end of file
+}
+
+// newClient returns an API client that caches scoped tokens in the session and
+// stops when the command is interrupted
+func newClient(cfg *config.Config, tokenCache *cache.TokenCache) *otc.Client {
+	otcClient := otc.NewClient(cfg)
+	otcClient.UseSession(tokenCache)
+	otcClient.SetContext(commandContext())
+	return otcClient
+}
+
+// commandContext returns the context cancelled by Ctrl-C, or context.Background()
+// outside of Execute
+func commandContext() context.Context {
+	if ctx := rootCmd.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
+
+// newPrinter returns the printer for -o/--output; --raw and --json select json
+func newPrinter() (*output.Printer, error) {
+	format := outputFlag
+	if rawFlag && !rootCmd.PersistentFlags().Changed("output") {
+		format = output.FormatJSON
+	}
+	printer, err := output.New(format)
+	if err != nil {
+		return nil, &usageError{err: err}
+	}
+	return printer, nil
+}
+
+// deprecatedOutputPath keeps `--output <path>` working on commands whose file
+// flag is now --file: a value that isn't an output format is taken as the path
+func deprecatedOutputPath(cmd *cobra.Command, path *string) {
+	if !cmd.Flags().Changed("output") || cmd.Flags().Changed("file") {
+		return
+	}
+	if _, err := output.New(outputFlag); err == nil {
+		return
+	}
+	fmt.Fprintln(os.Stderr, "Flag --output for the file path has been deprecated, use --file instead")
+	*path = outputFlag
+	outputFlag = output.FormatTable
+}
+
+// apiSession is an authenticated client with the selected project resolved
+type apiSession struct {
+	cfg           *config.Config
+	client        *otc.Client
+	unscopedToken string
+	projectID     string
+}
+
+// connect loads the config, authenticates and resolves the selected project
+func connect() (*apiSession, error) {
+	// Login and project messages must not end up in machine-readable output
+	if printer, err := newPrinter(); err == nil {
+		defer statusToStderr(printer)()
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	tokenCache, err := ensureAuthenticated(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Scoped tokens are cached in the session and reused across commands
+	otcClient := newClient(cfg, tokenCache)
+
+	projectID := selectedProject(cfg)
+	if projectID != "" {
+		projectID = resolveProject(otcClient, tokenCache.UnscopedToken, projectID)
+	}
+
+	return &apiSession{
+		cfg:           cfg,
+		client:        otcClient,
+		unscopedToken: tokenCache.UnscopedToken,
+		projectID:     projectID,
+	}, nil
+}
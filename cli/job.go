@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/abdo-farag/otc-cli/internal/commands"
+	"github.com/abdo-farag/otc-cli/internal/otc/jobs"
+
+	"github.com/spf13/cobra"
+)
+
+// Flags of commands that start jobs
+var (
+	jobWaitFlag    bool
+	jobNoWaitFlag  bool
+	jobTimeoutFlag time.Duration
+)
+
+// Flags of the job commands
+var (
+	jobServiceFlag string
+)
+
+var jobCmd = &cobra.Command{
+	Use:     "job",
+	Aliases: []string{"jobs"},
+	Short:   "Follow asynchronous ECS, EVS and CCE jobs",
+	Long: `Follow the asynchronous jobs that ECS, EVS and CCE start for changes,
+e.g. jobs started with --no-wait or from the console.`,
+}
+
+var jobGetCmd = &cobra.Command{
+	Use:   "get <job-id>",
+	Short: "Show the status of a job and its sub-jobs",
+	Example: `  otc-cli job get 2c9eb2c1725d3d0f01725d5e6a5c0075
+  otc-cli job get 2c9eb2c1725d3d0f01725d5e6a5c0075 --service evs -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runJobGet,
+}
+
+var jobWaitCmd = &cobra.Command{
+	Use:   "wait <job-id>",
+	Short: "Wait until a job finishes",
+	Long: `Wait until a job finishes. Exits non-zero if the job fails or the
+timeout passes.`,
+	Example: `  otc-cli job wait 2c9eb2c1725d3d0f01725d5e6a5c0075
+  otc-cli job wait 5b1e0c4a-0fa4-11ee-9f5c-0255ac100b03 --service cce --timeout 1h`,
+	Args: cobra.ExactArgs(1),
+	RunE: runJobWait,
+}
+
+func init() {
+	jobCmd.AddCommand(jobGetCmd)
+	jobCmd.AddCommand(jobWaitCmd)
+
+	for _, cmd := range []*cobra.Command{jobGetCmd, jobWaitCmd} {
+		cmd.Flags().StringVar(&jobServiceFlag, "service", string(jobs.ECS), "Service that started the job (ecs, evs, cce)")
+	}
+	jobWaitCmd.Flags().DurationVar(&jobTimeoutFlag, "timeout", jobs.DefaultTimeout, "How long to wait")
+}
+
+// addJobFlags adds --wait, --no-wait and --timeout to a command that starts a job
+func addJobFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&jobWaitFlag, "wait", true, "Wait for the job to finish")
+	cmd.Flags().BoolVar(&jobNoWaitFlag, "no-wait", false, "Return once the job is started (same as --wait=false)")
+	cmd.Flags().DurationVar(&jobTimeoutFlag, "timeout", jobs.DefaultTimeout, "How long to wait for the job")
+}
+
+// jobOptions returns the options set with addJobFlags
+func jobOptions() commands.JobOptions {
+	return commands.JobOptions{
+		NoWait:  !jobWaitFlag || jobNoWaitFlag,
+		Timeout: jobTimeoutFlag,
+	}
+}
+
+func runJobGet(cmd *cobra.Command, args []string) error {
+	service, err := jobs.ParseService(jobServiceFlag)
+	if err != nil {
+		return &usageError{err: err}
+	}
+
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+	defer statusToStderr(printer)()
+
+	s, err := connect()
+	if err != nil {
+		return err
+	}
+
+	return commands.GetJob(s.cfg, s.client, s.unscopedToken, s.projectID, service, args[0], printer)
+}
+
+func runJobWait(cmd *cobra.Command, args []string) error {
+	service, err := jobs.ParseService(jobServiceFlag)
+	if err != nil {
+		return &usageError{err: err}
+	}
+
+	s, err := connect()
+	if err != nil {
+		return err
+	}
+
+	return commands.WaitJob(s.cfg, s.client, s.unscopedToken, s.projectID, service, args[0], jobTimeoutFlag)
+}
@@ -1,8 +1,9 @@
 package cli
 
 import (
+  "fmt"
+
   "github.com/abdo-farag/otc-cli/internal/commands"
-  "github.com/abdo-farag/otc-cli/internal/config"
   "github.com/abdo-farag/otc-cli/internal/otc"
 
   "github.com/spf13/cobra"
@@ -19,7 +20,16 @@ var listCmd = &cobra.Command{
   # List specific resources
   otc-cli list ecs --az eu-de-01
   otc-cli list images --visibility private
-  otc-cli list projects`,
+  otc-cli list projects
+
+  # Extract fields for scripts
+  otc-cli list ecs -o jsonpath='{range [*]}{.name}{"\t"}{.status}{"\n"}{end}'
+  otc-cli list volume -o custom-columns=NAME:.name,SIZE:.size,SERVER:.attachments[0].server_id
+  otc-cli list vpc -o go-template='{{range .}}{{.name}} {{.cidr}}{{"\n"}}{{end}}'
+
+  # Page through large result sets
+  otc-cli list ecs --limit 50
+  otc-cli list image --page-size 500 -o name`,
   SuggestionsMinimumDistance: 2,
 }
 
@@ -40,12 +50,45 @@ var listSubnetCmd = &cobra.Command{
   RunE:    runListSubnet,
 }
 
+var listSecgroupCmd = &cobra.Command{
+  Use:     "secgroup",
+  Aliases: []string{"secgroups", "security-group", "security-groups", "sg"},
+  Short:   "List security groups",
+  Args:    cobra.NoArgs,
+  Example: `  otc-cli list secgroup
+  otc-cli list secgroup --vpc my-vpc -o wide`,
+  RunE: runListSecgroup,
+}
+
+var listEipCmd = &cobra.Command{
+  Use:     "eip",
+  Aliases: []string{"eips", "publicip", "publicips"},
+  Short:   "List Elastic IPs",
+  Args:    cobra.NoArgs,
+  Example: `  otc-cli list eip
+  otc-cli list eip -o wide`,
+  RunE: runListEip,
+}
+
 var listVolumeCmd = &cobra.Command{
   Use:     "volume",
   Aliases: []string{"volumes"},
   Short:   "List volumes",
   Args:    cobra.NoArgs,
-  RunE:    runListVolume,
+  Example: `  otc-cli list volume
+  otc-cli list volume --orphaned`,
+  RunE: runListVolume,
+}
+
+var listSnapshotCmd = &cobra.Command{
+  Use:     "snapshot",
+  Aliases: []string{"snapshots", "snap"},
+  Short:   "List volume snapshots",
+  Args:    cobra.NoArgs,
+  Example: `  otc-cli list snapshot
+  otc-cli list snapshot --volume data-1
+  otc-cli list snapshot --tag purpose=maintenance -o wide`,
+  RunE: runListSnapshot,
 }
 
 var listCceCmd = &cobra.Command{
@@ -56,6 +99,26 @@ var listCceCmd = &cobra.Command{
   RunE:    runListCce,
 }
 
+var listNodePoolCmd = &cobra.Command{
+  Use:     "nodepool",
+  Aliases: []string{"nodepools", "pool", "pools"},
+  Short:   "List the node pools of a Kubernetes cluster",
+  Args:    cobra.NoArgs,
+  Example: `  otc-cli list nodepool --cluster my-cluster
+  otc-cli list nodepool --cluster my-cluster -o wide`,
+  RunE: runListNodePool,
+}
+
+var listNodeCmd = &cobra.Command{
+  Use:     "node",
+  Aliases: []string{"nodes"},
+  Short:   "List the nodes of a Kubernetes cluster",
+  Args:    cobra.NoArgs,
+  Example: `  otc-cli list node --cluster my-cluster
+  otc-cli list node --cluster c8198b6d-7633-4afc-9ec5-ab97bcd94ab8 -o wide`,
+  RunE: runListNode,
+}
+
 var listFlavorCmd = &cobra.Command{
   Use:   "flavor",
   Aliases: []string{"flavors"},
@@ -92,7 +155,9 @@ var listEcsCmd = &cobra.Command{
   Args:    cobra.NoArgs,
   Example: `  otc-cli list ecs
   otc-cli list ecs --az eu-de-01
-  otc-cli list ecs --status ACTIVE --tag Environment=production`,
+  otc-cli list ecs --status ACTIVE --tag Environment=production
+  otc-cli list ecs -o wide
+  otc-cli list ecs -o name`,
   RunE: runListEcs,
 }
 
@@ -102,7 +167,7 @@ var listKeypairCmd = &cobra.Command{
   Short:   "List SSH keypairs",
   Args:    cobra.NoArgs,
   Example: `  otc-cli list keypair
-  otc-cli list keypair --raw`,
+  otc-cli list keypair -o json`,
   RunE: runListKeypair,
 }
 
@@ -122,19 +187,54 @@ var (
   imageStatus     string
 )
 
+// Security group flags
+var (
+  secgroupVPC string
+)
+
+// Volume flags
+var (
+  volumeOrphaned bool
+)
+
+// Snapshot flags
+var (
+  snapshotListVolume string
+  snapshotListTag    string
+)
+
+// CCE flags
+var (
+  cceCluster string
+)
+
 // Flavor-specific flags
 var (
   flavorOS string
 )
 
+// Pagination flags
+var (
+  listLimit    int
+  listPageSize int
+)
+
+// maxPageSize is the largest page size accepted by all paginated OTC APIs
+const maxPageSize = 1000
+
 func init() {
   // Add subcommands
   listCmd.AddCommand(listProjectsCmd)
   listCmd.AddCommand(listEcsCmd)
   listCmd.AddCommand(listVpcCmd)
   listCmd.AddCommand(listSubnetCmd)
+  listCmd.AddCommand(listSecgroupCmd)
+  listCmd.AddCommand(listEipCmd)
   listCmd.AddCommand(listVolumeCmd)
+  listCmd.AddCommand(listSnapshotCmd)
   listCmd.AddCommand(listCceCmd)
+  listCmd.AddCommand(listNodePoolCmd)
+  listCmd.AddCommand(listNodeCmd)
   listCmd.AddCommand(listFlavorCmd)
   listCmd.AddCommand(listImageCmd)
   listCmd.AddCommand(listKeypairCmd)
@@ -151,8 +251,30 @@ func init() {
   listImageCmd.Flags().StringVar(&imageName, "name", "", "Filter by image name (partial match)")
   listImageCmd.Flags().StringVar(&imageStatus, "status", "", "Filter by status (active, queued, etc.)")
 
+  // Security group flags
+  listSecgroupCmd.Flags().StringVar(&secgroupVPC, "vpc", "", "Only list security groups of this VPC (name or ID)")
+
+  // Volume flags
+  listVolumeCmd.Flags().BoolVar(&volumeOrphaned, "orphaned", false, "Only list available volumes that aren't attached to any server")
+
+  // Snapshot flags
+  listSnapshotCmd.Flags().StringVar(&snapshotListVolume, "volume", "", "Only list snapshots of this volume (name or ID)")
+  listSnapshotCmd.Flags().StringVar(&snapshotListTag, "tag", "", "Filter by tag (key or key=value)")
+
+  // CCE flags
+  for _, cmd := range []*cobra.Command{listNodePoolCmd, listNodeCmd} {
+    cmd.Flags().StringVar(&cceCluster, "cluster", "", "Cluster name or ID")
+    cmd.MarkFlagRequired("cluster")
+  }
+
   // Flavor flags
-  listFlavorCmd.Flags().StringVarP(&flavorOS, "os", "o", "openlinux", "OS type for pricing (openlinux, redhat, oracle, windows)")
+  listFlavorCmd.Flags().StringVar(&flavorOS, "os", "openlinux", "OS type for pricing (openlinux, redhat, oracle, windows)")
+
+  // Pagination flags for resources listed page by page
+  for _, cmd := range []*cobra.Command{listEcsCmd, listVpcCmd, listSubnetCmd, listSecgroupCmd, listEipCmd, listVolumeCmd, listSnapshotCmd, listImageCmd} {
+    cmd.Flags().IntVar(&listLimit, "limit", 0, "Maximum number of items to list (0 = all)")
+    cmd.Flags().IntVar(&listPageSize, "page-size", 0, fmt.Sprintf("Items fetched per API request (default %d, max %d)", otc.DefaultPageSize, maxPageSize))
+  }
 }
 
 // RunE functions for each resource
@@ -178,14 +300,44 @@ func runListSubnet(cmd *cobra.Command, args []string) error {
   return runListResource("subnet", map[string]interface{}{})
 }
 
+func runListSecgroup(cmd *cobra.Command, args []string) error {
+  options := map[string]interface{}{
+    "vpc": secgroupVPC,
+  }
+  return runListResource("secgroup", options)
+}
+
+func runListEip(cmd *cobra.Command, args []string) error {
+  return runListResource("eip", map[string]interface{}{})
+}
+
 func runListVolume(cmd *cobra.Command, args []string) error {
-  return runListResource("volume", map[string]interface{}{})
+  options := map[string]interface{}{
+    "orphaned": volumeOrphaned,
+  }
+  return runListResource("volume", options)
+}
+
+func runListSnapshot(cmd *cobra.Command, args []string) error {
+  options := map[string]interface{}{
+    "volume": snapshotListVolume,
+    "tag":    snapshotListTag,
+  }
+  return runListResource("snapshot", options)
 }
 
 func runListCce(cmd *cobra.Command, args []string) error {
   return runListResource("cce", map[string]interface{}{})
 }
 
+func runListNodePool(cmd *cobra.Command, args []string) error {
+  return runListResource("nodepool", map[string]interface{}{"cluster": cceCluster})
+}
+
+func runListNode(cmd *cobra.Command, args []string) error {
+  return runListResource("node", map[string]interface{}{"cluster": cceCluster})
+}
+
 func runListFlavor(cmd *cobra.Command, args []string) error {
   options := map[string]interface{}{
     "os": flavorOS,
@@ -209,21 +361,26 @@ func runListKeypair(cmd *cobra.Command, args []string) error {
 
 // Common list logic
 func runListResource(resourceType string, options map[string]interface{}) error {
-  cfg := config.New()
-
-  // Authenticate
-  tokenCache, err := ensureAuthenticated(cfg)
+  printer, err := newPrinter()
   if err != nil {
     return err
   }
 
-  // Resolve project
-  selectedProjectID := projectFlag
-  if selectedProjectID != "" && resourceType != "projects" {
-    selectedProjectID = resolveProject(cfg, tokenCache.UnscopedToken, selectedProjectID)
+  if listLimit < 0 {
+    return usageErrorf("--limit must not be negative")
+  }
+  if listPageSize < 0 || listPageSize > maxPageSize {
+    return usageErrorf("--page-size must be between 1 and %d", maxPageSize)
+  }
+  options["limit"] = listLimit
+  options["page-size"] = listPageSize
+  defer statusToStderr(printer)()
+
+  s, err := connect()
+  if err != nil {
+    return err
   }
 
   // Execute list command
-  otcClient := otc.NewClient(cfg)
-  return commands.ListCommand(cfg, otcClient, tokenCache.UnscopedToken, resourceType, selectedProjectID, options, rawFlag)
+  return commands.ListCommand(s.cfg, s.client, s.unscopedToken, resourceType, s.projectID, options, printer)
 }
@@ -1,7 +1,6 @@
 package cli
 
 import (
-	"fmt"
 	"os"
 	"strconv"
 
@@ -23,6 +22,7 @@ var (
 	redirectPort        int
 	outputFile          string
 	noBrowser           bool
+	deviceFlow          bool
 	codeChallengeMethod string
 	scope               string
 	iamMode             bool
@@ -37,12 +37,17 @@ var loginCmd = &cobra.Command{
 
 OIDC Authentication (default):
   Uses OAuth2/OIDC flow with your identity provider.
+  With --device, uses the device authorization grant: a code is printed
+  and approved from any browser, no local callback port is required.
   
 IAM Authentication (--iam flag):
   Uses direct username/password authentication with OTC IAM.`,
 	Example: `  # OIDC authentication
   otc-cli login --idp-url https://idp.example.com --idp-client-id myclient
 
+  # OIDC on a headless host (jump host, CI runner)
+  otc-cli login --device
+
   # IAM authentication
   otc-cli login --iam --username myuser
 
@@ -62,8 +67,9 @@ func init() {
   loginCmd.Flags().StringVar(&idpProviderName, "idp-provider", "", "IDP provider name")
   loginCmd.Flags().StringVar(&region, "region", "", "Region")
   loginCmd.Flags().IntVar(&redirectPort, "port", 9197, "Callback port")
-  loginCmd.Flags().StringVar(&outputFile, "output", "", "Output file")
+  loginCmd.Flags().StringVarP(&outputFile, "file", "f", "", "Credentials file (written as <file>.sh)")
   loginCmd.Flags().BoolVar(&noBrowser, "no-browser", false, "Don't open browser automatically")
+  loginCmd.Flags().BoolVar(&deviceFlow, "device", false, "Use device authorization flow (headless hosts, no callback port needed)")
   loginCmd.Flags().StringVar(&codeChallengeMethod, "code-challenge-method", "S256", "PKCE method (S256 or plain)")
   loginCmd.Flags().StringVar(&scope, "scope", "openid email profile roles groups organization", "OIDC scopes")
 
@@ -75,7 +81,12 @@ func init() {
 
 
 func runLogin(cmd *cobra.Command, args []string) error {
-	cfg := buildConfig()
+	deprecatedOutputPath(cmd, &outputFile)
+
+	cfg, err := buildConfig()
+	if err != nil {
+		return err
+	}
 
 	// Handle IAM authentication
 	if iamMode {
@@ -86,9 +97,13 @@ func runLogin(cmd *cobra.Command, args []string) error {
 	return handleOIDCLogin(cfg)
 }
 
-func buildConfig() *config.Config {
-  cfg := config.New()
-  
+func buildConfig() (*config.Config, error) {
+  cfg, err := loadConfig()
+  if err != nil {
+    return nil, err
+  }
+
+
   // Priority: flag > env var > config default
   if idpURL != "" {
     cfg.IdpURL = idpURL
@@ -152,7 +167,11 @@ func buildConfig() *config.Config {
   
   cfg.NoBrowser = noBrowser
 
-  return cfg
+  if deviceFlow {
+    cfg.DeviceFlow = true
+  }
+
+  return cfg, nil
 }
 
 func handleIAMLogin(cfg *config.Config) error {
@@ -174,11 +193,11 @@ func handleIAMLogin(cfg *config.Config) error {
   }
 
   if user == "" || pass == "" {
-    return fmt.Errorf("username and password are required")
+    return usageErrorf("username and password are required")
   }
 
   if err := commands.LoginIAM(cfg, user, pass); err != nil {
-    return err
+    return &authError{err}
   }
 
   color.Green("✓ Successfully authenticated with IAM")
@@ -194,8 +213,8 @@ func handleOIDCLogin(cfg *config.Config) error {
 		return err
 	}
 
-	if err := commands.Login(cfg); err != nil {
-		return err
+	if err := commands.Login(commandContext(), cfg); err != nil {
+		return &authError{err}
 	}
 
 	color.Green("✓ Successfully authenticated with OIDC")
@@ -218,7 +237,7 @@ func validateOIDCConfig(cfg *config.Config) error {
 	}
 
 	if len(missing) > 0 {
-		return fmt.Errorf("missing required configuration: %v", missing)
+		return usageErrorf("missing required configuration: %v", missing)
 	}
 	return nil
 }
\ No newline at end of file
@@ -1,23 +1,80 @@
 package cli
 
 import (
+	"errors"
+	"fmt"
+	"os"
+
 	"github.com/abdo-farag/otc-cli/internal/cache"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
+var logoutAll bool
+
 var logoutCmd = &cobra.Command{
 	Use:   "logout",
 	Short: "Clear cached authentication token",
-	Long:  "Remove the cached authentication token from local storage.",
-	RunE:  runLogout,
+	Long: `Remove cached authentication tokens from local storage.
+
+Without flags, only the session of the active profile, domain and region is removed.
+With --profile, every cached session of that profile is removed.`,
+	Example: `  otc-cli logout
+  otc-cli logout --profile staging
+  otc-cli logout --all`,
+	RunE: runLogout,
+}
+
+func init() {
+	logoutCmd.Flags().BoolVar(&logoutAll, "all", false, "Remove all cached sessions")
 }
 
 func runLogout(cmd *cobra.Command, args []string) error {
-	if err := cache.ClearToken(); err != nil {
+	// Profiles may use different token stores, and may have been deleted or
+	// switched to another store since logging in: clear every store
+	if logoutAll {
+		removed := 0
+		err := cache.InEachStore(func() error {
+			n, err := cache.ClearAllTokens()
+			removed += n
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		color.Green("✓ Logged out of %d session(s)", removed)
+		return nil
+	}
+
+	if profileFlag != "" {
+		removed := 0
+		err := cache.InEachStore(func() error {
+			n, err := cache.ClearProfile(profileFlag)
+			removed += n
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if removed == 0 {
+			return fmt.Errorf("no cached sessions for profile '%s'", profileFlag)
+		}
+		color.Green("✓ Logged out of %d session(s) for profile '%s'", removed, profileFlag)
+		return nil
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	if err := cache.ClearToken(cache.SessionFor(cfg)); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("not logged in (profile '%s', domain '%s', region '%s')", cfg.Profile, cfg.DomainName, cfg.Region)
+		}
 		return err
 	}
 	color.Green("✓ Logged out successfully")
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abdo-farag/otc-cli/internal/cache"
+	"github.com/abdo-farag/otc-cli/internal/config"
+
+	"github.com/fatih/color"
+)
+
+// newFakeOTC serves IAM and a VPC list, and caches a valid session for it in
+// a temporary home directory so commands run without logging in
+func newFakeOTC(t *testing.T) {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v3/auth/tokens":
+			w.Header().Set("X-Subject-Token", "scoped-token")
+			w.WriteHeader(http.StatusCreated)
+			io.WriteString(w, `{"token":{"expires_at":"2099-01-01T00:00:00Z"}}`)
+		case r.URL.Path == "/v3/auth/projects":
+			io.WriteString(w, `{"projects":[{"id":"p1","name":"eu-de_test"}]}`)
+		case strings.HasSuffix(r.URL.Path, "/vpcs/v1"):
+			io.WriteString(w, `{"vpc":{"id":"v1","name":"main","cidr":"10.0.0.0/16","status":"OK"}}`)
+		case strings.HasSuffix(r.URL.Path, "/vpcs"):
+			io.WriteString(w, `{"vpcs":[{"id":"v1","name":"main","cidr":"10.0.0.0/16","status":"OK"}]}`)
+		case strings.HasSuffix(r.URL.Path, "/snapshots/detail"):
+			io.WriteString(w, `{"snapshots":[{"id":"s1","name":"nightly","status":"available","volume_id":"vol-1","created_at":"2020-01-01T00:00:00.000000"}]}`)
+		case r.Method == "DELETE" && strings.HasSuffix(r.URL.Path, "/cloudsnapshots/s1"):
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("OS_DOMAIN_NAME", "dom")
+	t.Setenv("OS_AUTH_URL", srv.URL)
+	t.Setenv("OTC_ENDPOINT_VPC", srv.URL)
+	t.Setenv("OTC_ENDPOINT_EVS", srv.URL)
+	t.Setenv("OTC_PROJECT", "eu-de_test")
+	t.Setenv("OTC_TOKEN_STORE", "file")
+
+	cfg, err := config.Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.UseStore(cache.NewFileStore(cache.GetCacheDir()))
+	err = cache.SaveToken(&cache.TokenCache{
+		UnscopedToken: "unscoped-token",
+		ExpiresAt:     time.Now().Add(time.Hour),
+		Profile:       cfg.Profile,
+		Domain:        cfg.DomainName,
+		Region:        cfg.Region,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// runCLI runs otc-cli with args and returns what it wrote to stdout
+func runCLI(t *testing.T, args ...string) string {
+	t.Helper()
+
+	out, err := execCLI(t, args...)
+	if err != nil {
+		t.Fatalf("otc-cli %s: %v\nstdout:\n%s", strings.Join(args, " "), err, out)
+	}
+	return out
+}
+
+// execCLI runs otc-cli with args and returns its stdout and error
+func execCLI(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout, colorOutput := os.Stdout, color.Output
+	os.Stdout, color.Output = w, w
+	defer func() { os.Stdout, color.Output = stdout, colorOutput }()
+
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+
+	outputFlag = "table"
+	rootCmd.SetArgs(args)
+	err = Execute()
+	w.Close()
+	return <-done, err
+}
+
+func TestMachineOutputHasNoStatusMessages(t *testing.T) {
+	newFakeOTC(t)
+
+	out := runCLI(t, "list", "vpc", "-o", "json")
+
+	var vpcs []map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &vpcs); err != nil {
+		t.Fatalf("stdout of -o json isn't JSON: %v\n%s", err, out)
+	}
+	if len(vpcs) != 1 || vpcs[0]["id"] != "v1" {
+		t.Errorf("got %v, want the one VPC", vpcs)
+	}
+}
+
+func TestSnapshotPruneMachineOutput(t *testing.T) {
+	newFakeOTC(t)
+
+	out := runCLI(t, "snapshot", "prune", "--older-than", "1d", "--yes", "-o", "json")
+
+	var deleted []map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &deleted); err != nil {
+		t.Fatalf("stdout of -o json isn't JSON: %v\n%s", err, out)
+	}
+	if len(deleted) != 1 || deleted[0]["id"] != "s1" {
+		t.Errorf("got %v, want the deleted snapshot", deleted)
+	}
+}
+
+func TestTableOutputKeepsStatusMessages(t *testing.T) {
+	newFakeOTC(t)
+
+	out := runCLI(t, "list", "vpc")
+
+	for _, want := range []string{"Using cached token", "main"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("table output lacks %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestTemplateOutputIsOnlyTheResult(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"list", "vpc", "-o", "jsonpath={[*].id}"}, "v1"},
+		{[]string{"list", "vpc", "-o", "go-template={{range .}}{{.name}} {{.cidr}}{{end}}"}, "main 10.0.0.0/16"},
+		{[]string{"get", "vpc", "v1", "-o", "jsonpath={.cidr}"}, "10.0.0.0/16"},
+		{[]string{"get", "vpc", "v1", "-o", "go-template={{.name}}"}, "main"},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			newFakeOTC(t)
+
+			out := runCLI(t, tt.args...)
+			if strings.TrimSpace(out) != tt.want {
+				t.Errorf("stdout = %q, want %q", out, tt.want)
+			}
+		})
+	}
+}
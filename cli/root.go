@@ -1,7 +1,14 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/abdo-farag/otc-cli/internal/output"
 
 	"github.com/spf13/cobra"
 )
@@ -13,7 +20,9 @@ var (
 // Global flags
 var (
 	projectFlag string
+	profileFlag string
 	rawFlag     bool
+	outputFlag  string
 )
 
 // rootCmd represents the base command
@@ -21,27 +30,64 @@ var rootCmd = &cobra.Command{
 	Use:   "otc-cli",
 	Short: "OTC CLI - Manage your Open Telekom Cloud resources",
 	Long: `A command-line tool for managing Open Telekom Cloud (OTC) resources.
-Supports authentication, resource management, and automation.`,
+Supports authentication, resource management, and automation.
+
+` + exitCodesHelp,
 	Version: version,
+
+	// Errors are printed once by main, with an exit code from ExitCode
+	SilenceErrors: true,
+	SilenceUsage:  true,
 }
 
-// Execute runs the root command
+// Execute runs the root command. Ctrl-C cancels in-flight API requests; paths
+// that don't watch the context (login, prompts) are killed by a second Ctrl-C.
 func Execute() error {
-	return rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Restore the default SIGINT exit once the first signal was received
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	started := false
+	trackRun(rootCmd, &started)
+
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+	var usage *usageError
+	if err != nil && (!started || errors.As(err, &usage)) {
+		return &usageError{err: err, command: cmd.CommandPath()}
+	}
+	return err
 }
 
 func init() {
 	// Persistent flags available to all subcommands
 	rootCmd.PersistentFlags().StringVarP(&projectFlag, "project", "p", "", "Project ID or name")
-	rootCmd.PersistentFlags().BoolVar(&rawFlag, "raw", false, "Output raw JSON response")
-	rootCmd.PersistentFlags().BoolVar(&rawFlag, "json", false, "Output raw JSON response (alias)")
+	rootCmd.PersistentFlags().StringVar(&profileFlag, "profile", "", "Config profile to use (default: $OTC_PROFILE or current profile)")
+	rootCmd.PersistentFlags().StringVarP(&outputFlag, "output", "o", output.FormatTable, "Output format: "+strings.Join(output.Formats, ", "))
+	rootCmd.PersistentFlags().BoolVar(&rawFlag, "raw", false, "Output JSON (same as -o json)")
+	rootCmd.PersistentFlags().BoolVar(&rawFlag, "json", false, "Output JSON (same as -o json)")
 
 	// Add subcommands
 	rootCmd.AddCommand(loginCmd)
 	rootCmd.AddCommand(logoutCmd)
+	rootCmd.AddCommand(authCmd)
+	rootCmd.AddCommand(credentialProcessCmd)
 	rootCmd.AddCommand(docsCmd)
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(getCmd)
+	rootCmd.AddCommand(cceCmd)
+	rootCmd.AddCommand(ecsCmd)
+	rootCmd.AddCommand(secgroupCmd)
+	rootCmd.AddCommand(eipCmd)
+	rootCmd.AddCommand(volumeCmd)
+	rootCmd.AddCommand(snapshotCmd)
+	rootCmd.AddCommand(jobCmd)
+	rootCmd.AddCommand(waitCmd)
+	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(versionCmd)
 }
 
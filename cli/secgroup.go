@@ -0,0 +1,207 @@
+package cli
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/abdo-farag/otc-cli/internal/commands"
+	"github.com/abdo-farag/otc-cli/internal/commands/resource"
+
+	"github.com/spf13/cobra"
+)
+
+// Security group flags
+var (
+	sgCreateVPC         string
+	sgCreateDescription string
+	sgRuleSpec          resource.RuleSpec
+	sgRulePort          string
+	sgRuleYes           bool
+)
+
+var secgroupCmd = &cobra.Command{
+	Use:     "secgroup",
+	Aliases: []string{"security-group", "sg"},
+	Short:   "Manage security groups and their rules",
+	Long: `Manage security groups and their rules. Use "otc-cli list secgroup" and
+"otc-cli get secgroup" to inspect them.`,
+}
+
+var secgroupCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a security group",
+	Long: `Create a security group. OTC adds default rules allowing all egress
+traffic and all traffic between members of the group.`,
+	Example: `  otc-cli secgroup create web --description "Public web servers"`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runSecgroupCreate,
+}
+
+var secgroupRuleCmd = &cobra.Command{
+	Use:     "rule",
+	Aliases: []string{"rules"},
+	Short:   "Add or delete security group rules",
+}
+
+var secgroupRuleAddCmd = &cobra.Command{
+	Use:   "add <group-name-or-id>",
+	Short: "Add a rule to a security group",
+	Example: `  otc-cli secgroup rule add web --protocol tcp --port 443 --remote-ip 0.0.0.0/0
+  otc-cli secgroup rule add web --protocol tcp --port 8000-8100 --remote-group lb
+  otc-cli secgroup rule add web --direction egress --protocol any --remote-ip 10.0.0.0/8`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSecgroupRuleAdd,
+}
+
+var secgroupRuleDeleteCmd = &cobra.Command{
+	Use:     "delete <rule-id>...",
+	Aliases: []string{"remove", "rm"},
+	Short:   "Delete security group rules",
+	Long: `Delete security group rules by ID. "otc-cli get secgroup <group> -o wide"
+shows the rule IDs.`,
+	Example: `  otc-cli secgroup rule delete 2f6b0a3c-1d7e-4b8a-9c5f-3e2d1a0b9c8d --yes`,
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    runSecgroupRuleDelete,
+}
+
+var secgroupAuditCmd = &cobra.Command{
+	Use:   "audit [group-name-or-id]...",
+	Short: "Find sensitive ports open to the internet",
+	Long: `Check security groups, or all of them, for ingress rules that open
+sensitive ports (SSH, RDP, databases, Docker, Kubernetes, ...) to 0.0.0.0/0
+or ::/0.`,
+	Example: `  otc-cli secgroup audit
+  otc-cli secgroup audit web db -o wide`,
+	RunE: runSecgroupAudit,
+}
+
+func init() {
+	secgroupCmd.AddCommand(secgroupCreateCmd)
+	secgroupCmd.AddCommand(secgroupRuleCmd)
+	secgroupCmd.AddCommand(secgroupAuditCmd)
+	secgroupRuleCmd.AddCommand(secgroupRuleAddCmd)
+	secgroupRuleCmd.AddCommand(secgroupRuleDeleteCmd)
+
+	secgroupCreateCmd.Flags().StringVar(&sgCreateVPC, "vpc", "", "VPC name or ID the group belongs to")
+	secgroupCreateCmd.Flags().StringVar(&sgCreateDescription, "description", "", "Description")
+
+	f := secgroupRuleAddCmd.Flags()
+	f.StringVar(&sgRuleSpec.Direction, "direction", "ingress", "Traffic direction: ingress or egress")
+	f.StringVar(&sgRuleSpec.Protocol, "protocol", "tcp", "Protocol: tcp, udp, icmp or any")
+	f.StringVar(&sgRulePort, "port", "", "Port or port range, e.g. 22 or 8000-8100 (default: all ports)")
+	f.StringVar(&sgRuleSpec.RemoteIP, "remote-ip", "", "Remote CIDR (default: any address)")
+	f.StringVar(&sgRuleSpec.RemoteGroup, "remote-group", "", "Remote security group name or ID instead of a CIDR")
+	f.StringVar(&sgRuleSpec.Ethertype, "ethertype", "IPv4", "IPv4 or IPv6 (default: from --remote-ip)")
+	f.StringVar(&sgRuleSpec.Description, "description", "", "Description")
+
+	secgroupRuleDeleteCmd.Flags().BoolVarP(&sgRuleYes, "yes", "y", false, "Don't ask for confirmation")
+}
+
+func runSecgroupCreate(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+	defer statusToStderr(printer)()
+
+	s, err := connect()
+	if err != nil {
+		return err
+	}
+
+	return commands.CreateSecurityGroup(s.cfg, s.client, s.unscopedToken, s.projectID, args[0], sgCreateVPC, sgCreateDescription, printer)
+}
+
+func runSecgroupRuleAdd(cmd *cobra.Command, args []string) error {
+	spec, err := ruleSpec(cmd)
+	if err != nil {
+		return err
+	}
+
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+	defer statusToStderr(printer)()
+
+	s, err := connect()
+	if err != nil {
+		return err
+	}
+
+	return commands.AddSecurityGroupRule(s.cfg, s.client, s.unscopedToken, s.projectID, args[0], spec, printer)
+}
+
+// ruleSpec validates the rule add flags
+func ruleSpec(cmd *cobra.Command) (resource.RuleSpec, error) {
+	spec := sgRuleSpec
+	spec.Direction = strings.ToLower(spec.Direction)
+	spec.Protocol = strings.ToLower(spec.Protocol)
+
+	if spec.Direction != "ingress" && spec.Direction != "egress" {
+		return spec, usageErrorf("invalid --direction %q: must be ingress or egress", sgRuleSpec.Direction)
+	}
+	switch spec.Protocol {
+	case "tcp", "udp", "icmp":
+	case "any", "all", "":
+		spec.Protocol = ""
+	default:
+		return spec, usageErrorf("invalid --protocol %q: must be tcp, udp, icmp or any", sgRuleSpec.Protocol)
+	}
+	if spec.RemoteIP != "" && spec.RemoteGroup != "" {
+		return spec, usageErrorf("--remote-ip and --remote-group are mutually exclusive")
+	}
+
+	if !cmd.Flags().Changed("ethertype") && strings.Contains(spec.RemoteIP, ":") {
+		spec.Ethertype = "IPv6"
+	}
+	switch strings.ToLower(spec.Ethertype) {
+	case "ipv4":
+		spec.Ethertype = "IPv4"
+	case "ipv6":
+		spec.Ethertype = "IPv6"
+	default:
+		return spec, usageErrorf("invalid --ethertype %q: must be IPv4 or IPv6", sgRuleSpec.Ethertype)
+	}
+
+	if sgRulePort != "" {
+		if spec.Protocol != "tcp" && spec.Protocol != "udp" {
+			return spec, usageErrorf("--port requires --protocol tcp or udp")
+		}
+		low, high, isRange := strings.Cut(sgRulePort, "-")
+		if !isRange {
+			high = low
+		}
+		var errLow, errHigh error
+		spec.PortMin, errLow = strconv.Atoi(low)
+		spec.PortMax, errHigh = strconv.Atoi(high)
+		if errLow != nil || errHigh != nil || spec.PortMin < 1 || spec.PortMax > 65535 || spec.PortMin > spec.PortMax {
+			return spec, usageErrorf("invalid --port %q: expected PORT or FROM-TO between 1 and 65535", sgRulePort)
+		}
+	}
+	return spec, nil
+}
+
+func runSecgroupRuleDelete(cmd *cobra.Command, args []string) error {
+	s, err := connect()
+	if err != nil {
+		return err
+	}
+
+	return commands.DeleteSecurityGroupRules(s.cfg, s.client, s.unscopedToken, s.projectID, args, sgRuleYes)
+}
+
+func runSecgroupAudit(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+	defer statusToStderr(printer)()
+
+	s, err := connect()
+	if err != nil {
+		return err
+	}
+
+	return commands.AuditSecurityGroups(s.cfg, s.client, s.unscopedToken, s.projectID, args, printer)
+}
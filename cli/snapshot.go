@@ -0,0 +1,204 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abdo-farag/otc-cli/internal/commands"
+	"github.com/abdo-farag/otc-cli/internal/commands/resource"
+
+	"github.com/spf13/cobra"
+)
+
+// Snapshot flags
+var (
+	snapshotCreateSpec resource.SnapshotSpec
+	snapshotTags       []string
+	snapshotVolume     string
+	snapshotYes        bool
+	pruneOlderThan     string
+	pruneKeep          int
+	pruneTag           string
+	pruneDryRun        bool
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:     "snapshot",
+	Aliases: []string{"snapshots", "snap"},
+	Short:   "Manage EVS volume snapshots",
+	Long: `Create, restore, delete and prune point-in-time snapshots of EVS volumes.
+Use "otc-cli list snapshot" to see them.`,
+}
+
+var snapshotCreateCmd = &cobra.Command{
+	Use:   "create <volume>",
+	Short: "Snapshot a volume",
+	Long: `Snapshot a volume, given by name or ID, and wait until the snapshot is
+available. Tags are stored as snapshot metadata.`,
+	Example: `  otc-cli snapshot create data-1
+  otc-cli snapshot create data-1 --name before-upgrade --tag purpose=maintenance`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSnapshotCreate,
+}
+
+var snapshotRestoreCmd = &cobra.Command{
+	Use:     "restore <snapshot>",
+	Aliases: []string{"rollback"},
+	Short:   "Roll a volume back to a snapshot",
+	Long: `Roll a volume back to a snapshot, overwriting its data. The volume
+defaults to the one the snapshot was taken of and must be detached.`,
+	Example: `  otc-cli snapshot restore before-upgrade
+  otc-cli snapshot restore before-upgrade --volume data-1-copy --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSnapshotRestore,
+}
+
+var snapshotDeleteCmd = &cobra.Command{
+	Use:   "delete <snapshot>...",
+	Short: "Delete snapshots",
+	Example: `  otc-cli snapshot delete before-upgrade
+  otc-cli snapshot delete 3c4d5e6f-7a8b-4c9d-8e0f-1a2b3c4d5e6f --yes`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSnapshotDelete,
+}
+
+var snapshotPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete old snapshots, keeping the newest of each volume",
+	Long: `Delete available snapshots older than --older-than, always keeping the
+--keep newest snapshots of each volume. Use --dry-run to list exactly what
+would be deleted.`,
+	Example: `  otc-cli snapshot prune --older-than 30d --keep 5 --dry-run
+  otc-cli snapshot prune --older-than 2w --tag purpose=maintenance --yes
+  otc-cli snapshot prune --volume data-1 --keep 3`,
+	Args: cobra.NoArgs,
+	RunE: runSnapshotPrune,
+}
+
+func init() {
+	snapshotCmd.AddCommand(snapshotCreateCmd)
+	snapshotCmd.AddCommand(snapshotRestoreCmd)
+	snapshotCmd.AddCommand(snapshotDeleteCmd)
+	snapshotCmd.AddCommand(snapshotPruneCmd)
+
+	f := snapshotCreateCmd.Flags()
+	f.StringVar(&snapshotCreateSpec.Name, "name", "", "Snapshot name (default: <volume>-<UTC timestamp>)")
+	f.StringVar(&snapshotCreateSpec.Description, "description", "", "Description")
+	f.StringArrayVar(&snapshotTags, "tag", nil, "Tag as key=value (repeatable)")
+	addJobFlags(snapshotCreateCmd)
+
+	snapshotRestoreCmd.Flags().StringVar(&snapshotVolume, "volume", "", "Volume to overwrite (default: the snapshot's volume)")
+
+	f = snapshotPruneCmd.Flags()
+	f.StringVar(&pruneOlderThan, "older-than", "", "Only delete snapshots older than this, e.g. 30d, 2w or 12h")
+	f.IntVar(&pruneKeep, "keep", 0, "Always keep this many of the newest snapshots per volume")
+	f.StringVar(&pruneTag, "tag", "", "Only prune snapshots with this tag (key or key=value)")
+	f.StringVar(&snapshotVolume, "volume", "", "Only prune snapshots of this volume")
+	f.BoolVar(&pruneDryRun, "dry-run", false, "Only list the snapshots that would be deleted")
+
+	for _, cmd := range []*cobra.Command{snapshotRestoreCmd, snapshotDeleteCmd, snapshotPruneCmd} {
+		cmd.Flags().BoolVarP(&snapshotYes, "yes", "y", false, "Don't ask for confirmation")
+	}
+}
+
+func runSnapshotCreate(cmd *cobra.Command, args []string) error {
+	spec := snapshotCreateSpec
+	for _, tag := range snapshotTags {
+		key, value, ok := strings.Cut(tag, "=")
+		if !ok || key == "" {
+			return usageErrorf("invalid --tag %q: expected key=value", tag)
+		}
+		if spec.Tags == nil {
+			spec.Tags = map[string]string{}
+		}
+		spec.Tags[key] = value
+	}
+
+	s, err := connect()
+	if err != nil {
+		return err
+	}
+
+	return commands.CreateSnapshot(s.cfg, s.client, s.unscopedToken, s.projectID, args[0], spec, jobOptions())
+}
+
+func runSnapshotRestore(cmd *cobra.Command, args []string) error {
+	s, err := connect()
+	if err != nil {
+		return err
+	}
+
+	return commands.RestoreSnapshot(s.cfg, s.client, s.unscopedToken, s.projectID, args[0], snapshotVolume, snapshotYes)
+}
+
+func runSnapshotDelete(cmd *cobra.Command, args []string) error {
+	s, err := connect()
+	if err != nil {
+		return err
+	}
+
+	return commands.DeleteSnapshots(s.cfg, s.client, s.unscopedToken, s.projectID, args, snapshotYes)
+}
+
+func runSnapshotPrune(cmd *cobra.Command, args []string) error {
+	if pruneOlderThan == "" && pruneKeep == 0 {
+		return usageErrorf("set --older-than and/or --keep; pruning without either would delete every snapshot")
+	}
+	if pruneKeep < 0 {
+		return usageErrorf("--keep must not be negative")
+	}
+
+	opts := commands.PruneOptions{Volume: snapshotVolume, Tag: pruneTag, DryRun: pruneDryRun}
+	opts.Keep = pruneKeep
+	if pruneOlderThan != "" {
+		age, err := parseAge(pruneOlderThan)
+		if err != nil {
+			return usageErrorf("invalid --older-than %q: %v", pruneOlderThan, err)
+		}
+		opts.OlderThan = age
+	}
+
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+	defer statusToStderr(printer)()
+
+	s, err := connect()
+	if err != nil {
+		return err
+	}
+
+	return commands.PruneSnapshots(s.cfg, s.client, s.unscopedToken, s.projectID, opts, snapshotYes, printer)
+}
+
+// parseAge parses a duration that may also be given in days (30d) or weeks (2w)
+func parseAge(s string) (time.Duration, error) {
+	unit := time.Duration(0)
+	switch {
+	case strings.HasSuffix(s, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(s, "w"):
+		unit = 7 * 24 * time.Hour
+	}
+
+	var age time.Duration
+	if unit > 0 {
+		n, err := strconv.Atoi(s[:len(s)-1])
+		if err != nil {
+			return 0, fmt.Errorf("expected e.g. 30d, 2w or 12h")
+		}
+		age = time.Duration(n) * unit
+	} else {
+		var err error
+		if age, err = time.ParseDuration(s); err != nil {
+			return 0, fmt.Errorf("expected e.g. 30d, 2w or 12h")
+		}
+	}
+	if age <= 0 {
+		return 0, fmt.Errorf("must be positive")
+	}
+	return age, nil
+}
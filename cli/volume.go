@@ -0,0 +1,172 @@
+package cli
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/abdo-farag/otc-cli/internal/commands"
+	"github.com/abdo-farag/otc-cli/internal/commands/resource"
+
+	"github.com/spf13/cobra"
+)
+
+// Volume types accepted by volume create
+var volumeTypes = []string{"SATA", "SAS", "SSD", "GPSSD", "ESSD"}
+
+// Volume flags
+var (
+	volumeCreateSpec   resource.VolumeSpec
+	volumeAttachDevice string
+	volumeExtendSize   int
+	volumeYes          bool
+)
+
+var volumeCmd = &cobra.Command{
+	Use:     "volume",
+	Aliases: []string{"volumes", "evs", "disk"},
+	Short:   "Manage EVS volumes",
+	Long: `Create, attach, detach, extend and delete EVS volumes. Volumes and servers
+are given by name or ID. Use "otc-cli list volume" to see them.`,
+}
+
+var volumeCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a volume",
+	Example: `  otc-cli volume create data-1 --size 100 --az eu-de-01
+  otc-cli volume create logs --size 500 --type SAS --az eu-de-02 --no-wait`,
+	Args: cobra.ExactArgs(1),
+	RunE: runVolumeCreate,
+}
+
+var volumeAttachCmd = &cobra.Command{
+	Use:   "attach <volume> <server>",
+	Short: "Attach a volume to a server",
+	Example: `  otc-cli volume attach data-1 web-1
+  otc-cli volume attach data-1 web-1 --device /dev/vdb`,
+	Args: cobra.ExactArgs(2),
+	RunE: runVolumeAttach,
+}
+
+var volumeDetachCmd = &cobra.Command{
+	Use:   "detach <volume> [server]",
+	Short: "Detach a volume from a server",
+	Long: `Detach a volume from the server it is attached to. Shared volumes
+attached to several servers need the server. Unmount the volume first.`,
+	Example: `  otc-cli volume detach data-1
+  otc-cli volume detach shared-1 web-2 --yes`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runVolumeDetach,
+}
+
+var volumeExtendCmd = &cobra.Command{
+	Use:     "extend <volume>",
+	Aliases: []string{"resize"},
+	Short:   "Grow a volume",
+	Long: `Grow a volume to a new size. Volumes can't shrink. Grow the partition
+and file system on the server afterwards.`,
+	Example: `  otc-cli volume extend data-1 --size 200`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runVolumeExtend,
+}
+
+var volumeDeleteCmd = &cobra.Command{
+	Use:   "delete <volume>...",
+	Short: "Delete volumes",
+	Long:  `Delete volumes. Attached volumes must be detached first.`,
+	Example: `  otc-cli volume delete data-1
+  otc-cli volume delete $(otc-cli list volume --orphaned -o jsonpath='{[*].id}') --yes`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runVolumeDelete,
+}
+
+func init() {
+	volumeCmd.AddCommand(volumeCreateCmd)
+	volumeCmd.AddCommand(volumeAttachCmd)
+	volumeCmd.AddCommand(volumeDetachCmd)
+	volumeCmd.AddCommand(volumeExtendCmd)
+	volumeCmd.AddCommand(volumeDeleteCmd)
+
+	f := volumeCreateCmd.Flags()
+	f.IntVar(&volumeCreateSpec.Size, "size", 0, "Size in GB")
+	f.StringVar(&volumeCreateSpec.Type, "type", "SSD", "Volume type: "+strings.Join(volumeTypes, ", "))
+	f.StringVar(&volumeCreateSpec.AvailabilityZone, "az", "", "Availability zone (e.g., eu-de-01)")
+	f.StringVar(&volumeCreateSpec.Description, "description", "", "Description")
+	volumeCreateCmd.MarkFlagRequired("size")
+	volumeCreateCmd.MarkFlagRequired("az")
+
+	volumeAttachCmd.Flags().StringVar(&volumeAttachDevice, "device", "", "Device name, e.g. /dev/vdb (default: next free device)")
+
+	volumeExtendCmd.Flags().IntVar(&volumeExtendSize, "size", 0, "New size in GB")
+	volumeExtendCmd.MarkFlagRequired("size")
+
+	for _, cmd := range []*cobra.Command{volumeDetachCmd, volumeDeleteCmd} {
+		cmd.Flags().BoolVarP(&volumeYes, "yes", "y", false, "Don't ask for confirmation")
+	}
+	for _, cmd := range []*cobra.Command{volumeCreateCmd, volumeAttachCmd, volumeDetachCmd, volumeExtendCmd, volumeDeleteCmd} {
+		addJobFlags(cmd)
+	}
+}
+
+func runVolumeCreate(cmd *cobra.Command, args []string) error {
+	spec := volumeCreateSpec
+	spec.Name = args[0]
+	if spec.Size <= 0 {
+		return usageErrorf("--size must be a positive number of GB")
+	}
+	spec.Type = strings.ToUpper(spec.Type)
+	if !slices.Contains(volumeTypes, spec.Type) {
+		return usageErrorf("invalid --type %q (valid: %s)", volumeCreateSpec.Type, strings.Join(volumeTypes, ", "))
+	}
+
+	s, err := connect()
+	if err != nil {
+		return err
+	}
+
+	return commands.CreateVolume(s.cfg, s.client, s.unscopedToken, s.projectID, spec, jobOptions())
+}
+
+func runVolumeAttach(cmd *cobra.Command, args []string) error {
+	s, err := connect()
+	if err != nil {
+		return err
+	}
+
+	return commands.AttachVolume(s.cfg, s.client, s.unscopedToken, s.projectID, args[0], args[1], volumeAttachDevice, jobOptions())
+}
+
+func runVolumeDetach(cmd *cobra.Command, args []string) error {
+	server := ""
+	if len(args) > 1 {
+		server = args[1]
+	}
+
+	s, err := connect()
+	if err != nil {
+		return err
+	}
+
+	return commands.DetachVolume(s.cfg, s.client, s.unscopedToken, s.projectID, args[0], server, volumeYes, jobOptions())
+}
+
+func runVolumeExtend(cmd *cobra.Command, args []string) error {
+	if volumeExtendSize <= 0 {
+		return usageErrorf("--size must be a positive number of GB")
+	}
+
+	s, err := connect()
+	if err != nil {
+		return err
+	}
+
+	return commands.ExtendVolume(s.cfg, s.client, s.unscopedToken, s.projectID, args[0], volumeExtendSize, jobOptions())
+}
+
+func runVolumeDelete(cmd *cobra.Command, args []string) error {
+	s, err := connect()
+	if err != nil {
+		return err
+	}
+
+	return commands.DeleteVolumes(s.cfg, s.client, s.unscopedToken, s.projectID, args, volumeYes, jobOptions())
+}
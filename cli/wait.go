@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/abdo-farag/otc-cli/internal/commands"
+
+	"github.com/spf13/cobra"
+)
+
+// Resource types wait accepts, as for get
+var waitResources = []string{"ecs", "server", "vpc", "subnet", "eip", "volume", "snapshot", "cce", "cluster"}
+
+var (
+	waitForFlag     string
+	waitTimeoutFlag time.Duration
+)
+
+var waitCmd = &cobra.Command{
+	Use:   "wait <resource> <name-or-id>",
+	Short: "Wait until a resource reaches a status or is deleted",
+	Long: `Wait until a resource reaches a status or is deleted, polling it with
+backoff. Exits non-zero if the timeout passes or the resource enters an
+error state (e.g. ERROR, error_extending or Unavailable). A name is resolved
+to the resource's ID once, before polling starts.
+
+Resources: ` + strings.Join(waitResources, ", "),
+	Example: `  otc-cli wait ecs 5ba4a5b5-5bb2-4a2a-9f7e-5bd2fd8c3e35 --for status=ACTIVE
+  otc-cli wait volume 0f2a1c3e-8a4b-4c7d-9e1f-2a3b4c5d6e7f --for status=available --timeout 5m
+  otc-cli wait cce c8198b6d-7633-4afc-9ec5-ab97bcd94ab8 --for status=Available --timeout 30m
+  otc-cli wait ecs 5ba4a5b5-5bb2-4a2a-9f7e-5bd2fd8c3e35 --for delete
+  otc-cli wait ecs web-1 --for delete`,
+	Args: cobra.ExactArgs(2),
+	RunE: runWait,
+}
+
+func init() {
+	waitCmd.Flags().StringVar(&waitForFlag, "for", "", "Condition: status=VALUE or delete")
+	waitCmd.Flags().DurationVar(&waitTimeoutFlag, "timeout", commands.DefaultWaitTimeout, "How long to wait")
+	waitCmd.MarkFlagRequired("for")
+}
+
+func runWait(cmd *cobra.Command, args []string) error {
+	resourceType := strings.ToLower(args[0])
+	if !slices.Contains(waitResources, resourceType) {
+		return usageErrorf("unknown resource type %q (valid: %s)", args[0], strings.Join(waitResources, ", "))
+	}
+
+	cond, err := commands.ParseWaitCondition(waitForFlag)
+	if err != nil {
+		return &usageError{err: err}
+	}
+
+	s, err := connect()
+	if err != nil {
+		return err
+	}
+
+	return commands.WaitCommand(s.cfg, s.client, s.unscopedToken, resourceType, args[1], s.projectID, cond, waitTimeoutFlag)
+}
@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/abdo-farag/otc-cli/internal/otc"
+)
+
+func TestWaitResolvesName(t *testing.T) {
+	newFakeOTC(t)
+
+	out := runCLI(t, "wait", "vpc", "main", "--for", "status=OK", "--timeout", "10s")
+
+	if !strings.Contains(out, "vpc v1 is OK") {
+		t.Errorf("didn't wait for the VPC named main by its ID:\n%s", out)
+	}
+}
+
+func TestWaitForDeleteOfUnknownName(t *testing.T) {
+	newFakeOTC(t)
+
+	out, err := execCLI(t, "wait", "vpc", "no-such-vpc", "--for", "delete", "--timeout", "10s")
+
+	if !errors.Is(err, otc.ErrNotFound) {
+		t.Errorf("got error %v, want not found rather than deleted:\n%s", err, out)
+	}
+}
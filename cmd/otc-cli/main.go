@@ -13,9 +13,9 @@ func main() {
 	// Load environment variables
 	godotenv.Load()
 
-	// Execute root command
+	// Execute root command; see cli.ExitCode for the exit codes
 	if err := cli.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(cli.ExitCode(err))
 	}
 }
\ No newline at end of file
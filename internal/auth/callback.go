@@ -127,6 +127,9 @@ func (h *CallbackHandler) handleClose(w http.ResponseWriter, r *http.Request) {
 // SetValidationStatus updates the validation status shown in the browser
 // This should be called from the CLI after token exchange succeeds/fails
 func (h *CallbackHandler) SetValidationStatus(status, message string) {
+	if h == nil {
+		return
+	}
 	h.mu.Lock()
 	defer h.mu.Unlock()
 	h.validationStatus = status
@@ -147,7 +150,7 @@ func (h *CallbackHandler) WaitForCode(timeout time.Duration) (string, error) {
 
 // Close gracefully shuts down the callback server
 func (h *CallbackHandler) Close() error {
-	if h.server != nil {
+	if h != nil && h.server != nil {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 		return h.server.Shutdown(ctx)
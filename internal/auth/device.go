@@ -0,0 +1,174 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// DeviceAuthResponse is the IdP response to a device authorization request (RFC 8628)
+type DeviceAuthResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+const deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// GetDeviceToken authenticates using the OAuth 2.0 Device Authorization Grant.
+// It needs neither a browser nor a reachable callback port on this machine:
+// the user completes the login on any other device.
+func (c *Client) GetDeviceToken() (*TokenResponse, error) {
+	deviceAuth, err := c.requestDeviceCode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start device authorization: %w", err)
+	}
+
+	color.Cyan("🔑 To sign in, visit:")
+	fmt.Printf("  %s\n", deviceAuth.VerificationURI)
+	color.Cyan("and enter the code:")
+	fmt.Printf("  %s\n", deviceAuth.UserCode)
+	if deviceAuth.VerificationURIComplete != "" {
+		color.Cyan("Or open this link directly:")
+		fmt.Printf("  %s\n", deviceAuth.VerificationURIComplete)
+	}
+
+	color.Yellow("⏳ Waiting for authentication...")
+
+	tokenResp, err := c.pollDeviceToken(deviceAuth)
+	if err != nil {
+		return nil, err
+	}
+
+	color.Green("✓ Device authorized")
+	return tokenResp, nil
+}
+
+func (c *Client) requestDeviceCode() (*DeviceAuthResponse, error) {
+	deviceURL := fmt.Sprintf("%s/protocol/openid-connect/auth/device", c.cfg.IdpURL)
+
+	data := url.Values{}
+	data.Set("client_id", c.cfg.IdpClientID)
+	if c.cfg.Scope != "" {
+		data.Set("scope", c.cfg.Scope)
+	}
+
+	body, statusCode, err := postForm(c.context(), deviceURL, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, fmt.Errorf("device authorization failed: %s", string(body))
+	}
+
+	var deviceAuth DeviceAuthResponse
+	if err := json.Unmarshal(body, &deviceAuth); err != nil {
+		return nil, err
+	}
+
+	if deviceAuth.DeviceCode == "" {
+		return nil, fmt.Errorf("no device_code in response")
+	}
+
+	return &deviceAuth, nil
+}
+
+// pollDeviceToken polls the token endpoint until the user approves or denies
+// the request, honoring authorization_pending and slow_down (RFC 8628 §3.5)
+func (c *Client) pollDeviceToken(deviceAuth *DeviceAuthResponse) (*TokenResponse, error) {
+	tokenURL := fmt.Sprintf("%s/protocol/openid-connect/token", c.cfg.IdpURL)
+
+	interval := time.Duration(deviceAuth.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	expiresIn := time.Duration(deviceAuth.ExpiresIn) * time.Second
+	if expiresIn <= 0 {
+		expiresIn = 300 * time.Second
+	}
+	deadline := time.Now().Add(expiresIn)
+
+	data := url.Values{}
+	data.Set("grant_type", deviceCodeGrantType)
+	data.Set("client_id", c.cfg.IdpClientID)
+	data.Set("device_code", deviceAuth.DeviceCode)
+
+	ctx := c.context()
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		body, statusCode, err := postForm(ctx, tokenURL, data)
+		if err != nil {
+			return nil, err
+		}
+
+		if statusCode == http.StatusOK {
+			var tokenResp TokenResponse
+			if err := json.Unmarshal(body, &tokenResp); err != nil {
+				return nil, err
+			}
+			return &tokenResp, nil
+		}
+
+		var errResp struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		json.Unmarshal(body, &errResp)
+
+		switch errResp.Error {
+		case "authorization_pending":
+			continue
+		case "slow_down":
+			interval += 5 * time.Second
+			continue
+		case "access_denied":
+			return nil, fmt.Errorf("authorization denied by user")
+		case "expired_token":
+			return nil, fmt.Errorf("device code expired, please run login again")
+		default:
+			return nil, fmt.Errorf("device token request failed: %s", string(body))
+		}
+	}
+
+	return nil, fmt.Errorf("timeout waiting for device authorization")
+}
+
+func postForm(ctx context.Context, endpoint string, data url.Values) ([]byte, int, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return body, resp.StatusCode, nil
+}
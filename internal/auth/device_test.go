@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/abdo-farag/otc-cli/internal/config"
+)
+
+func TestPollDeviceTokenStopsWhenCancelled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"error":"authorization_pending"}`)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client := NewClient(&config.Config{IdpURL: srv.URL})
+	client.SetContext(ctx)
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := client.pollDeviceToken(&DeviceAuthResponse{DeviceCode: "code", Interval: 30, ExpiresIn: 600})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("pollDeviceToken() = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("pollDeviceToken() returned after %s, want it to stop waiting on cancel", elapsed)
+	}
+}
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -17,21 +18,46 @@ import (
 )
 
 type TokenResponse struct {
-	AccessToken  string `json:"access_token"`
-	IDToken      string `json:"id_token"`
-	RefreshToken string `json:"refresh_token"`
-	TokenType    string `json:"token_type"`
-	ExpiresIn    int    `json:"expires_in"`
+	AccessToken      string `json:"access_token"`
+	IDToken          string `json:"id_token"`
+	RefreshToken     string `json:"refresh_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int    `json:"expires_in"`
+	RefreshExpiresIn int    `json:"refresh_expires_in"`
 }
 
 type Client struct {
 	cfg *config.Config
+	ctx context.Context
 }
 
 func NewClient(cfg *config.Config) *Client {
 	return &Client{cfg: cfg}
 }
 
+// SetContext sets the context that cancels device flow polling
+func (c *Client) SetContext(ctx context.Context) {
+	c.ctx = ctx
+}
+
+// context returns the context set with SetContext, or context.Background()
+func (c *Client) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// Authenticate runs the configured interactive flow. The device flow has no
+// callback handler, so the returned handler may be nil (its methods are nil-safe).
+func (c *Client) Authenticate() (*TokenResponse, *CallbackHandler, error) {
+	if c.cfg.DeviceFlow {
+		tokenResp, err := c.GetDeviceToken()
+		return tokenResp, nil, err
+	}
+	return c.GetOIDCToken()
+}
+
 func (c *Client) GetOIDCToken() (*TokenResponse, *CallbackHandler, error) {
 	// Validate code challenge method
 	if err := c.cfg.ValidateCodeChallengeMethod(); err != nil {
@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/abdo-farag/otc-cli/internal/cache"
+	"github.com/abdo-farag/otc-cli/internal/config"
+	"github.com/abdo-farag/otc-cli/internal/otc"
+)
+
+// ErrRefreshRejected is returned when the refresh token is expired or no longer
+// accepted by the IdP. Callers should fall back to interactive login.
+var ErrRefreshRejected = errors.New("refresh token rejected")
+
+// RefreshOIDCToken exchanges a refresh token for a new set of tokens
+func (c *Client) RefreshOIDCToken(refreshToken string) (*TokenResponse, error) {
+	tokenURL := fmt.Sprintf("%s/protocol/openid-connect/token", c.cfg.IdpURL)
+
+	data := url.Values{}
+	data.Set("grant_type", "refresh_token")
+	data.Set("client_id", c.cfg.IdpClientID)
+	data.Set("refresh_token", refreshToken)
+
+	req, err := http.NewRequest("POST", tokenURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		json.Unmarshal(body, &errResp)
+
+		// invalid_grant covers expired, revoked and already-rotated refresh tokens
+		if errResp.Error == "invalid_grant" || resp.StatusCode == http.StatusUnauthorized {
+			return nil, fmt.Errorf("%w: %s", ErrRefreshRejected, errResp.Description)
+		}
+		return nil, fmt.Errorf("token refresh failed: %s", string(body))
+	}
+
+	var tokenResp TokenResponse
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return nil, err
+	}
+
+	return &tokenResp, nil
+}
+
+// RefreshSession renews a cached session without opening a browser: it redeems
+// the refresh token at the IdP, re-federates the new ID token with OTC and
+// rewrites the token cache.
+func RefreshSession(cfg *config.Config, tokenCache *cache.TokenCache) (*cache.TokenCache, error) {
+	if tokenCache.RefreshToken == "" {
+		return nil, fmt.Errorf("%w: no refresh token cached", ErrRefreshRejected)
+	}
+
+	if !tokenCache.RefreshExpiresAt.IsZero() && time.Now().After(tokenCache.RefreshExpiresAt) {
+		return nil, fmt.Errorf("%w: refresh token expired", ErrRefreshRejected)
+	}
+
+	tokenResp, err := NewClient(cfg).RefreshOIDCToken(tokenCache.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// Some IdPs don't rotate refresh tokens - keep using the current one
+	if tokenResp.RefreshToken == "" {
+		tokenResp.RefreshToken = tokenCache.RefreshToken
+		if !tokenCache.RefreshExpiresAt.IsZero() {
+			tokenResp.RefreshExpiresIn = int(time.Until(tokenCache.RefreshExpiresAt).Seconds())
+		}
+	}
+
+	unscopedToken, expiresAt, err := otc.NewClient(cfg).GetUnscopedToken(tokenResp.IDToken, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unscoped token: %w", err)
+	}
+
+	refreshed := NewTokenCache(cfg, tokenResp, unscopedToken, expiresAt)
+	if err := cache.SaveToken(refreshed); err != nil {
+		return nil, fmt.Errorf("failed to save token cache: %w", err)
+	}
+
+	return refreshed, nil
+}
+
+// SessionLifetime is assumed for the unscoped token when OTC doesn't report
+// its expiry. OTC issues them for 24 hours.
+const SessionLifetime = 23 * time.Hour
+
+// NewTokenCache builds the cache entry for a freshly federated OIDC session.
+// The session lasts as long as the unscoped token, which expires at
+// expiresAt; the ID token's own, much shorter lifetime doesn't matter once
+// it has been exchanged.
+func NewTokenCache(cfg *config.Config, tokenResp *TokenResponse, unscopedToken string, expiresAt time.Time) *cache.TokenCache {
+	if expiresAt.IsZero() {
+		expiresAt = time.Now().Add(SessionLifetime)
+	}
+
+	var refreshExpiresAt time.Time
+	if tokenResp.RefreshExpiresIn > 0 {
+		refreshExpiresAt = time.Now().Add(time.Duration(tokenResp.RefreshExpiresIn) * time.Second)
+	}
+
+	return &cache.TokenCache{
+		UnscopedToken:    unscopedToken,
+		IDToken:          tokenResp.IDToken,
+		RefreshToken:     tokenResp.RefreshToken,
+		ExpiresAt:        expiresAt,
+		RefreshExpiresAt: refreshExpiresAt,
+		Domain:           cfg.DomainName,
+		Region:           cfg.Region,
+		Profile:          cfg.Profile,
+		IdpURL:           cfg.IdpURL,
+	}
+}
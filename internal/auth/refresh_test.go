@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/abdo-farag/otc-cli/internal/cache"
+	"github.com/abdo-farag/otc-cli/internal/config"
+)
+
+func TestRefreshSessionUsesUnscopedTokenExpiry(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/protocol/openid-connect/token":
+			// A Keycloak-like ID token that lives for five minutes
+			io.WriteString(w, `{"id_token":"id","refresh_token":"refresh-2","expires_in":300,"refresh_expires_in":1800}`)
+		case "/v3/OS-FEDERATION/identity_providers/idp/protocols/oidc/auth":
+			w.Header().Set("X-Subject-Token", "unscoped")
+			w.WriteHeader(http.StatusCreated)
+			io.WriteString(w, `{"token":{"expires_at":"2099-01-02T03:04:05.000000Z"}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	cache.UseStore(cache.NewFileStore(t.TempDir()))
+	cfg := &config.Config{IdpURL: srv.URL, AUTHURL: srv.URL, IDPProviderName: "idp", Profile: "default"}
+
+	refreshed, err := RefreshSession(cfg, &cache.TokenCache{RefreshToken: "refresh-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := time.Date(2099, 1, 2, 3, 4, 5, 0, time.UTC); !refreshed.ExpiresAt.Equal(want) {
+		t.Errorf("ExpiresAt = %s, want the unscoped token's %s", refreshed.ExpiresAt, want)
+	}
+	if refreshed.UnscopedToken != "unscoped" || refreshed.RefreshToken != "refresh-2" {
+		t.Errorf("got unscoped token %q, refresh token %q", refreshed.UnscopedToken, refreshed.RefreshToken)
+	}
+}
+
+func TestNewTokenCacheWithoutExpiry(t *testing.T) {
+	tokenCache := NewTokenCache(&config.Config{}, &TokenResponse{ExpiresIn: 300}, "unscoped", time.Time{})
+
+	if lifetime := time.Until(tokenCache.ExpiresAt); lifetime < SessionLifetime-time.Minute {
+		t.Errorf("session lasts %s, want about %s and not the ID token's 5 minutes", lifetime, SessionLifetime)
+	}
+}
@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const credentialsPrefix = "credentials/"
+
+// ErrCredentialsExpired is returned by LoadCredentials for expired credentials
+var ErrCredentialsExpired = errors.New("cached credentials expired")
+
+// CredentialCache holds temporary AK/SK credentials issued for a project
+type CredentialCache struct {
+	Access        string    `json:"access"`
+	Secret        string    `json:"secret"`
+	SecurityToken string    `json:"securitytoken"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	ProjectID     string    `json:"project_id"`
+}
+
+func credentialsKey(session Session, name string) string {
+	return credentialsPrefix + session.Key() + "__" + name
+}
+
+// SaveSessionSecret stores a JSON-encoded value derived from a session.
+// It is removed together with the session on logout.
+func SaveSessionSecret(session Session, name string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return store.Set(credentialsKey(session, name), data)
+}
+
+// LoadSessionSecret decodes a value stored with SaveSessionSecret into v
+func LoadSessionSecret(session Session, name string, v interface{}) error {
+	data, err := store.Get(credentialsKey(session, name))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// SaveCredentials stores temporary credentials for a session and project
+func SaveCredentials(session Session, creds *CredentialCache) error {
+	return SaveSessionSecret(session, creds.ProjectID, creds)
+}
+
+// LoadCredentials returns cached credentials, or an error if missing or expired
+func LoadCredentials(session Session, projectID string) (*CredentialCache, error) {
+	var creds CredentialCache
+	if err := LoadSessionSecret(session, projectID, &creds); err != nil {
+		return nil, err
+	}
+
+	if time.Now().After(creds.ExpiresAt) {
+		return nil, ErrCredentialsExpired
+	}
+
+	return &creds, nil
+}
+
+// clearCredentials removes all credentials cached for a session
+func clearCredentials(session Session) {
+	keys, err := store.List(credentialsPrefix + session.Key() + "__")
+	if err != nil {
+		return
+	}
+	for _, key := range keys {
+		store.Delete(key)
+	}
+}
@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Secret store backends, selectable per profile with the token_store key
+const (
+	StoreFile          = "file"
+	StoreEncryptedFile = "encrypted-file"
+	StoreKeyring       = "keyring"
+)
+
+// SecretStore persists session tokens and temporary credentials.
+// Keys are slash-separated, e.g. "sessions/<session-key>".
+// Get returns an error wrapping os.ErrNotExist when the key is missing.
+type SecretStore interface {
+	Get(key string) ([]byte, error)
+	Set(key string, data []byte) error
+	Delete(key string) error
+	List(prefix string) ([]string, error)
+
+	// Location describes where a key is stored, for user-facing messages
+	Location(key string) string
+}
+
+var store SecretStore = NewFileStore(GetCacheDir())
+
+// UseStore selects the backend used by the token and credential cache
+func UseStore(s SecretStore) {
+	store = s
+}
+
+// NewStore creates the named backend
+func NewStore(backend string) (SecretStore, error) {
+	switch backend {
+	case "", StoreFile:
+		return NewFileStore(GetCacheDir()), nil
+	case StoreEncryptedFile:
+		return NewEncryptedFileStore(filepath.Join(GetCacheDir(), "encrypted"), promptPassphrase), nil
+	case StoreKeyring:
+		return NewKeyringStore()
+	default:
+		return nil, fmt.Errorf("unknown token store: %s (must be %s, %s or %s)", backend, StoreFile, StoreEncryptedFile, StoreKeyring)
+	}
+}
+
+// AllStores returns every backend that holds sessions on this machine: the
+// file store, the encrypted store once its salt was written, and the keyring
+// once its index has entries. It never prompts: the encrypted store can only
+// be read with OTC_CLI_PASSPHRASE set, but listing and deleting keys needs none.
+func AllStores() []SecretStore {
+	stores := []SecretStore{NewFileStore(GetCacheDir())}
+
+	encryptedDir := filepath.Join(GetCacheDir(), "encrypted")
+	if _, err := os.Stat(filepath.Join(encryptedDir, "salt")); err == nil {
+		stores = append(stores, NewEncryptedFileStore(encryptedDir, envPassphrase))
+	}
+
+	if keyring, err := NewKeyringStore(); err == nil {
+		if keys, err := keyring.List(""); err == nil && len(keys) > 0 {
+			stores = append(stores, keyring)
+		}
+	}
+
+	return stores
+}
+
+// InEachStore calls fn with each of AllStores selected in turn and restores
+// the active store afterwards
+func InEachStore(fn func() error) error {
+	active := store
+	defer func() { store = active }()
+
+	for _, s := range AllStores() {
+		store = s
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
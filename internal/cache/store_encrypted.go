@@ -0,0 +1,230 @@
+package cache
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+const (
+	passphraseEnv    = "OTC_CLI_PASSPHRASE"
+	pbkdf2Iterations = 600000
+
+	// verifierValue is sealed into <dir>/verifier to check the passphrase
+	verifierValue = "otc-cli token store"
+)
+
+// ErrWrongPassphrase is returned when the passphrase doesn't match the one
+// the encrypted store was created with
+var ErrWrongPassphrase = errors.New("wrong passphrase for encrypted token store")
+
+// encryptedFileStore keeps each secret in an AES-256-GCM encrypted file.
+// The key is derived once per process from a passphrase and a per-store salt.
+type encryptedFileStore struct {
+	dir        string
+	passphrase func() (string, error)
+	gcm        cipher.AEAD
+}
+
+// NewEncryptedFileStore returns a store writing <dir>/<key>.enc.
+// passphrase is only called when a secret is first read or written.
+func NewEncryptedFileStore(dir string, passphrase func() (string, error)) SecretStore {
+	return &encryptedFileStore{dir: dir, passphrase: passphrase}
+}
+
+func (s *encryptedFileStore) path(key string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(key)+".enc")
+}
+
+func (s *encryptedFileStore) aead() (cipher.AEAD, error) {
+	if s.gcm != nil {
+		return s.gcm, nil
+	}
+
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return nil, err
+	}
+
+	saltPath := filepath.Join(s.dir, "salt")
+	salt, err := os.ReadFile(saltPath)
+	if os.IsNotExist(err) {
+		salt = make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(saltPath, salt, 0600); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	passphrase, err := s.passphrase()
+	if err != nil {
+		return nil, err
+	}
+	if passphrase == "" {
+		return nil, fmt.Errorf("empty passphrase for encrypted token store")
+	}
+
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, pbkdf2Iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the key before any entry is read or written with it
+	if err := s.verify(gcm); err != nil {
+		return nil, err
+	}
+
+	s.gcm = gcm
+	return s.gcm, nil
+}
+
+// verify checks gcm against the sealed verifier, creating it on first use.
+// Stores written before the verifier existed are checked against an entry.
+func (s *encryptedFileStore) verify(gcm cipher.AEAD) error {
+	verifierPath := filepath.Join(s.dir, "verifier")
+	data, err := os.ReadFile(verifierPath)
+	if err == nil {
+		if len(data) < gcm.NonceSize() {
+			return fmt.Errorf("corrupt passphrase verifier: %s", verifierPath)
+		}
+		plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], []byte("verifier"))
+		if err != nil || string(plaintext) != verifierValue {
+			return ErrWrongPassphrase
+		}
+		return nil
+	}
+	if !os.IsNotExist(err) {
+		return err
+	}
+
+	keys, err := s.List("")
+	if err != nil {
+		return err
+	}
+	if len(keys) > 0 {
+		entry, err := os.ReadFile(s.path(keys[0]))
+		if err != nil {
+			return err
+		}
+		if len(entry) < gcm.NonceSize() {
+			return fmt.Errorf("corrupt encrypted entry: %s", s.path(keys[0]))
+		}
+		if _, err := gcm.Open(nil, entry[:gcm.NonceSize()], entry[gcm.NonceSize():], []byte(keys[0])); err != nil {
+			return ErrWrongPassphrase
+		}
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	return os.WriteFile(verifierPath, gcm.Seal(nonce, nonce, []byte(verifierValue), []byte("verifier")), 0600)
+}
+
+func (s *encryptedFileStore) Get(key string) ([]byte, error) {
+	// Check existence first so a missing entry doesn't prompt for the passphrase
+	data, err := os.ReadFile(s.path(key))
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := s.aead()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return nil, fmt.Errorf("corrupt encrypted entry: %s", s.path(key))
+	}
+
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	// The key is authenticated as additional data so entries can't be swapped
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, []byte(key))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s", s.path(key))
+	}
+
+	return plaintext, nil
+}
+
+func (s *encryptedFileStore) Set(key string, data []byte) error {
+	gcm, err := s.aead()
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	path := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, gcm.Seal(nonce, nonce, data, []byte(key)), 0600)
+}
+
+func (s *encryptedFileStore) Delete(key string) error {
+	return os.Remove(s.path(key))
+}
+
+func (s *encryptedFileStore) List(prefix string) ([]string, error) {
+	return listFiles(s.dir, prefix, ".enc")
+}
+
+func (s *encryptedFileStore) Location(key string) string {
+	return s.path(key) + " (encrypted)"
+}
+
+// ErrPassphraseRequired is returned when the encrypted store is read without
+// a passphrase and may not prompt for one
+var ErrPassphraseRequired = fmt.Errorf("encrypted token store needs a passphrase: set %s", passphraseEnv)
+
+// envPassphrase reads the passphrase from OTC_CLI_PASSPHRASE only
+func envPassphrase() (string, error) {
+	if passphrase := os.Getenv(passphraseEnv); passphrase != "" {
+		return passphrase, nil
+	}
+	return "", ErrPassphraseRequired
+}
+
+// promptPassphrase reads the passphrase from OTC_CLI_PASSPHRASE or the terminal
+func promptPassphrase() (string, error) {
+	if passphrase, err := envPassphrase(); err == nil || !term.IsTerminal(int(os.Stdin.Fd())) {
+		return passphrase, err
+	}
+
+	// Prompt on stderr so stdout stays clean for machine-readable output
+	fmt.Fprint(os.Stderr, "Token store passphrase: ")
+	bytePwd, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(bytePwd)), nil
+}
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fileStore keeps each secret in a plaintext JSON file readable only by the user
+type fileStore struct {
+	dir string
+}
+
+// NewFileStore returns a store writing <dir>/<key>.json with 0600 permissions
+func NewFileStore(dir string) SecretStore {
+	return &fileStore{dir: dir}
+}
+
+func (s *fileStore) path(key string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(key)+".json")
+}
+
+func (s *fileStore) Get(key string) ([]byte, error) {
+	return os.ReadFile(s.path(key))
+}
+
+func (s *fileStore) Set(key string, data []byte) error {
+	path := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+	// WriteFile keeps the mode of existing files; tighten files from older versions
+	return os.Chmod(path, 0600)
+}
+
+func (s *fileStore) Delete(key string) error {
+	return os.Remove(s.path(key))
+}
+
+func (s *fileStore) List(prefix string) ([]string, error) {
+	return listFiles(s.dir, prefix, ".json")
+}
+
+func (s *fileStore) Location(key string) string {
+	return s.path(key)
+}
+
+// listFiles returns the keys starting with prefix of all files with ext below dir
+func listFiles(dir, prefix, ext string) ([]string, error) {
+	var keys []string
+	// Only walk the directory part of the prefix
+	root := dir
+	if i := strings.LastIndex(prefix, "/"); i >= 0 {
+		root = filepath.Join(dir, filepath.FromSlash(prefix[:i]))
+	}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ext) {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(strings.TrimSuffix(rel, ext))
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+
+	return keys, err
+}
@@ -0,0 +1,129 @@
+//go:build linux
+
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+const (
+	keyringService  = "otc-cli"
+	keyringIndexKey = "index"
+)
+
+// keyringStore keeps secrets in the Secret Service (GNOME Keyring, KWallet, ...) over D-Bus.
+// The Secret Service API can't enumerate our entries by prefix, so an index entry
+// tracks the stored keys.
+type keyringStore struct{}
+
+// NewKeyringStore returns a store backed by the desktop keyring
+func NewKeyringStore() (SecretStore, error) {
+	return &keyringStore{}, nil
+}
+
+func (s *keyringStore) Get(key string) ([]byte, error) {
+	secret, err := keyring.Get(keyringService, key)
+	if err != nil {
+		if errors.Is(err, keyring.ErrNotFound) {
+			return nil, fmt.Errorf("%s: %w", key, os.ErrNotExist)
+		}
+		return nil, fmt.Errorf("keyring: %w", err)
+	}
+	return []byte(secret), nil
+}
+
+func (s *keyringStore) Set(key string, data []byte) error {
+	if err := keyring.Set(keyringService, key, string(data)); err != nil {
+		return fmt.Errorf("keyring: %w", err)
+	}
+	return s.updateIndex(key, true)
+}
+
+func (s *keyringStore) Delete(key string) error {
+	if err := keyring.Delete(keyringService, key); err != nil {
+		if errors.Is(err, keyring.ErrNotFound) {
+			s.updateIndex(key, false)
+			return fmt.Errorf("%s: %w", key, os.ErrNotExist)
+		}
+		return fmt.Errorf("keyring: %w", err)
+	}
+	return s.updateIndex(key, false)
+}
+
+func (s *keyringStore) List(prefix string) ([]string, error) {
+	index, err := s.index()
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, key := range index {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (s *keyringStore) Location(key string) string {
+	return fmt.Sprintf("keyring (service %s, key %s)", keyringService, key)
+}
+
+func (s *keyringStore) index() ([]string, error) {
+	data, err := keyring.Get(keyringService, keyringIndexKey)
+	if err != nil {
+		if errors.Is(err, keyring.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("keyring: %w", err)
+	}
+
+	var keys []string
+	if err := json.Unmarshal([]byte(data), &keys); err != nil {
+		return nil, fmt.Errorf("keyring: corrupt index: %w", err)
+	}
+	return keys, nil
+}
+
+func (s *keyringStore) updateIndex(key string, present bool) error {
+	keys, err := s.index()
+	if err != nil {
+		return err
+	}
+
+	set := map[string]bool{}
+	for _, k := range keys {
+		set[k] = true
+	}
+	if set[key] == present {
+		return nil
+	}
+	if present {
+		set[key] = true
+	} else {
+		delete(set, key)
+	}
+
+	keys = keys[:0]
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	data, err := json.Marshal(keys)
+	if err != nil {
+		return err
+	}
+
+	if err := keyring.Set(keyringService, keyringIndexKey, string(data)); err != nil {
+		return fmt.Errorf("keyring: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package cache
+
+import "fmt"
+
+// NewKeyringStore is only available on Linux (Secret Service over D-Bus)
+func NewKeyringStore() (SecretStore, error) {
+	return nil, fmt.Errorf("the %s token store is only supported on Linux", StoreKeyring)
+}
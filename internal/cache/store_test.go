@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// useHome points the cache at an empty home directory
+func useHome(t *testing.T) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(passphraseEnv, "")
+
+	active := store
+	t.Cleanup(func() { store = active })
+}
+
+func hasEncryptedStore(stores []SecretStore) bool {
+	for _, s := range stores {
+		if _, ok := s.(*encryptedFileStore); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func TestAllStoresSkipsUnusedEncryptedStore(t *testing.T) {
+	useHome(t)
+
+	// The directory alone doesn't mean the store was ever used
+	os.MkdirAll(filepath.Join(GetCacheDir(), "encrypted"), 0700)
+	if hasEncryptedStore(AllStores()) {
+		t.Error("AllStores() opened the encrypted store without a salt")
+	}
+
+	os.WriteFile(filepath.Join(GetCacheDir(), "encrypted", "salt"), make([]byte, 16), 0600)
+	if !hasEncryptedStore(AllStores()) {
+		t.Error("AllStores() skipped the encrypted store with a salt")
+	}
+}
+
+func TestListSessionsWithoutPassphrase(t *testing.T) {
+	useHome(t)
+
+	t.Setenv(passphraseEnv, "secret")
+	UseStore(NewFileStore(GetCacheDir()))
+	SaveToken(&TokenCache{Profile: "plain", Domain: "d", Region: "eu-de", ExpiresAt: time.Now().Add(time.Hour)})
+	UseStore(NewEncryptedFileStore(filepath.Join(GetCacheDir(), "encrypted"), envPassphrase))
+	SaveToken(&TokenCache{Profile: "secure", Domain: "d", Region: "eu-de", ExpiresAt: time.Now().Add(time.Hour)})
+
+	list := func() ([]string, int) {
+		var profiles []string
+		locked := 0
+		err := InEachStore(func() error {
+			found, n, err := ListSessions()
+			for _, s := range found {
+				profiles = append(profiles, s.Profile)
+			}
+			locked += n
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return profiles, locked
+	}
+
+	// The encrypted session is counted instead of prompting for the passphrase
+	t.Setenv(passphraseEnv, "")
+	if profiles, locked := list(); len(profiles) != 1 || profiles[0] != "plain" || locked != 1 {
+		t.Errorf("got sessions %v with %d locked, want [plain] with 1 locked", profiles, locked)
+	}
+
+	t.Setenv(passphraseEnv, "secret")
+	if profiles, locked := list(); len(profiles) != 2 || locked != 0 {
+		t.Errorf("got sessions %v with %d locked, want both", profiles, locked)
+	}
+}
+
+func TestEncryptedStoreRejectsWrongPassphrase(t *testing.T) {
+	useHome(t)
+	dir := filepath.Join(GetCacheDir(), "encrypted")
+
+	t.Setenv(passphraseEnv, "secret")
+	if err := NewEncryptedFileStore(dir, envPassphrase).Set("sessions/a", []byte("token")); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(passphraseEnv, "typo")
+	wrong := NewEncryptedFileStore(dir, envPassphrase)
+	if _, err := wrong.Get("sessions/a"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("Get() = %v, want ErrWrongPassphrase", err)
+	}
+	if err := wrong.Set("sessions/b", []byte("token")); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("Set() = %v, want ErrWrongPassphrase", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "sessions", "b.enc")); !os.IsNotExist(err) {
+		t.Error("Set() with a wrong passphrase wrote an entry")
+	}
+
+	t.Setenv(passphraseEnv, "secret")
+	if data, err := NewEncryptedFileStore(dir, envPassphrase).Get("sessions/a"); err != nil || string(data) != "token" {
+		t.Errorf("Get() = %q, %v, want the stored token", data, err)
+	}
+}
+
+func TestEncryptedStoreChecksEntriesWithoutVerifier(t *testing.T) {
+	useHome(t)
+	dir := filepath.Join(GetCacheDir(), "encrypted")
+
+	t.Setenv(passphraseEnv, "secret")
+	NewEncryptedFileStore(dir, envPassphrase).Set("sessions/a", []byte("token"))
+	// Stores written by earlier versions have no verifier
+	os.Remove(filepath.Join(dir, "verifier"))
+
+	t.Setenv(passphraseEnv, "typo")
+	if err := NewEncryptedFileStore(dir, envPassphrase).Set("sessions/b", []byte("token")); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("Set() = %v, want ErrWrongPassphrase", err)
+	}
+}
@@ -1,13 +1,25 @@
 package cache
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
+
+	"github.com/abdo-farag/otc-cli/internal/config"
 )
 
+// ErrSessionMismatch is returned when a cached token belongs to another profile, domain or region
+var ErrSessionMismatch = errors.New("cached token does not match active config")
+
 type TokenCache struct {
 	UnscopedToken string    `json:"unscoped_token"`
 	IDToken       string    `json:"id_token"`
@@ -15,6 +27,94 @@ type TokenCache struct {
 	ExpiresAt     time.Time `json:"expires_at"`
 	Domain        string    `json:"domain"`
 	Region        string    `json:"region"`
+	Profile       string    `json:"profile,omitempty"`
+	IdpURL        string    `json:"idp_url,omitempty"`
+
+	// RefreshExpiresAt is when the IdP stops accepting RefreshToken (zero if unknown)
+	RefreshExpiresAt time.Time `json:"refresh_expires_at,omitempty"`
+
+	// Scoped tokens issued from UnscopedToken, reused until near expiry
+	DomainToken   *ScopedToken            `json:"domain_token,omitempty"`
+	Projects      []CachedProject         `json:"projects,omitempty"`
+	ProjectTokens map[string]*ScopedToken `json:"project_tokens,omitempty"`
+}
+
+// ScopedToken is a domain- or project-scoped IAM token with its expiry
+// as reported in the IAM response body
+type ScopedToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// Catalog maps service types to the base URLs issued with the token
+	Catalog map[string]string `json:"catalog,omitempty"`
+}
+
+// Valid reports whether the token can still be used (with 5 minute buffer)
+func (t *ScopedToken) Valid() bool {
+	return t != nil && t.Token != "" && time.Now().Add(5*time.Minute).Before(t.ExpiresAt)
+}
+
+// CachedProject is a project listed with the cached domain token
+type CachedProject struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Session identifies one cached login. Sessions for different profiles,
+// domains, regions or IdPs are cached side by side.
+type Session struct {
+	Profile string
+	Domain  string
+	Region  string
+	IdpURL  string
+}
+
+// SessionFor returns the session matching the active config
+func SessionFor(cfg *config.Config) Session {
+	return Session{
+		Profile: cfg.Profile,
+		Domain:  cfg.DomainName,
+		Region:  cfg.Region,
+		IdpURL:  cfg.IdpURL,
+	}
+}
+
+// Session returns the session the token was cached for
+func (c *TokenCache) Session() Session {
+	return Session{
+		Profile: c.Profile,
+		Domain:  c.Domain,
+		Region:  c.Region,
+		IdpURL:  c.IdpURL,
+	}
+}
+
+var unsafeKeyChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Key returns a filesystem-safe identifier for the session
+func (s Session) Key() string {
+	idp := "iam"
+	if s.IdpURL != "" {
+		if u, err := url.Parse(s.IdpURL); err == nil && u.Host != "" {
+			idp = u.Host
+		}
+		// Several realms can share one IdP host
+		sum := sha256.Sum256([]byte(s.IdpURL))
+		idp += "-" + hex.EncodeToString(sum[:4])
+	}
+
+	parts := []string{s.Profile, s.Domain, s.Region, idp}
+	for i, p := range parts {
+		parts[i] = keyPart(p)
+	}
+	return strings.Join(parts, "__")
+}
+
+func keyPart(p string) string {
+	if p == "" {
+		p = "none"
+	}
+	return unsafeKeyChars.ReplaceAllString(p, "_")
 }
 
 func GetCacheDir() string {
@@ -24,8 +124,15 @@ func GetCacheDir() string {
 	return cacheDir
 }
 
-func GetTokenPath() string {
-	return filepath.Join(GetCacheDir(), "token.json")
+const sessionsPrefix = "sessions/"
+
+func sessionKey(session Session) string {
+	return sessionsPrefix + session.Key()
+}
+
+// TokenLocation describes where the session's token is stored
+func TokenLocation(session Session) string {
+	return store.Location(sessionKey(session))
 }
 
 func SaveToken(cache *TokenCache) error {
@@ -33,11 +140,43 @@ func SaveToken(cache *TokenCache) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(GetTokenPath(), data, 0600)
+	return store.Set(sessionKey(cache.Session()), data)
 }
 
-func LoadToken() (*TokenCache, error) {
-	data, err := os.ReadFile(GetTokenPath())
+// LoadToken returns the cached token, or an error if it is missing or expired
+func LoadToken(session Session) (*TokenCache, error) {
+	cache, err := ReadToken(session)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if expired
+	if time.Now().After(cache.ExpiresAt) {
+		return nil, fmt.Errorf("token expired")
+	}
+
+	return cache, nil
+}
+
+// ReadToken returns the cached token without checking its expiry.
+// Used by the refresh path, which only needs the refresh token.
+func ReadToken(session Session) (*TokenCache, error) {
+	cache, err := readToken(sessionKey(session))
+	if err != nil {
+		return nil, err
+	}
+
+	// Different values can map to the same key, since keyPart replaces unsafe characters
+	if cache.Profile != session.Profile || cache.Domain != session.Domain || cache.Region != session.Region {
+		return nil, fmt.Errorf("%w: token is for %s/%s/%s, config is %s/%s/%s", ErrSessionMismatch,
+			cache.Profile, cache.Domain, cache.Region, session.Profile, session.Domain, session.Region)
+	}
+
+	return cache, nil
+}
+
+func readToken(key string) (*TokenCache, error) {
+	data, err := store.Get(key)
 	if err != nil {
 		return nil, err
 	}
@@ -47,14 +186,103 @@ func LoadToken() (*TokenCache, error) {
 		return nil, err
 	}
 
-	// Check if expired
-	if time.Now().After(cache.ExpiresAt) {
-		return nil, fmt.Errorf("token expired")
+	return &cache, nil
+}
+
+// ListSessions returns all cached sessions, sorted by profile, domain and region.
+// locked counts the encrypted sessions skipped for lack of a passphrase.
+func ListSessions() (sessions []*TokenCache, locked int, err error) {
+	keys, err := store.List(sessionsPrefix)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	return &cache, nil
+	for _, key := range keys {
+		cache, err := readToken(key)
+		if errors.Is(err, ErrPassphraseRequired) {
+			locked++
+			continue
+		}
+		if errors.Is(err, ErrWrongPassphrase) {
+			return nil, 0, err
+		}
+		if err != nil {
+			continue
+		}
+		sessions = append(sessions, cache)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].Session().Key() < sessions[j].Session().Key()
+	})
+
+	return sessions, locked, nil
 }
 
-func ClearToken() error {
-	return os.Remove(GetTokenPath())
+// ClearToken removes the session's token and any credentials cached for it
+func ClearToken(session Session) error {
+	clearCredentials(session)
+	return store.Delete(sessionKey(session))
+}
+
+// ClearProfile removes every cached session of a profile and returns how many were removed.
+// The key prefix also matches profiles named "<profile>__...", so each entry is
+// decoded and compared. Entries that can't be decrypted are removed when their
+// key has the shape of a session of the profile.
+func ClearProfile(profile string) (int, error) {
+	prefix := sessionsPrefix + keyPart(profile) + "__"
+	keys, err := store.List(prefix)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, key := range keys {
+		if cache, err := readToken(key); err == nil {
+			if cache.Profile != profile {
+				continue
+			}
+		} else if strings.Count(strings.TrimPrefix(key, prefix), "__") != 2 {
+			// Domain, region and IdP follow the profile
+			continue
+		}
+
+		credentialKeys, _ := store.List(credentialsPrefix + strings.TrimPrefix(key, sessionsPrefix) + "__")
+		for _, k := range credentialKeys {
+			store.Delete(k)
+		}
+		if err := store.Delete(key); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return removed, err
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
+// ClearAllTokens removes every cached session and returns how many were removed
+func ClearAllTokens() (int, error) {
+	keys, err := store.List(sessionsPrefix)
+	if err != nil {
+		return 0, err
+	}
+
+	// Single-session cache used by earlier versions
+	os.Remove(filepath.Join(GetCacheDir(), "token.json"))
+
+	credentialKeys, err := store.List(credentialsPrefix)
+	if err != nil {
+		return 0, err
+	}
+	for _, key := range credentialKeys {
+		store.Delete(key)
+	}
+
+	for i, key := range keys {
+		if err := store.Delete(key); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return i, err
+		}
+	}
+
+	return len(keys), nil
 }
@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLoadTokenRejectsMismatchedSession(t *testing.T) {
+	useHome(t)
+	UseStore(NewFileStore(GetCacheDir()))
+
+	// "eu de" and "eu/de" share the key part "eu_de"
+	SaveToken(&TokenCache{Profile: "work", Domain: "d", Region: "eu de", ExpiresAt: time.Now().Add(time.Hour)})
+
+	if _, err := LoadToken(Session{Profile: "work", Domain: "d", Region: "eu de"}); err != nil {
+		t.Fatalf("LoadToken() for the cached session: %v", err)
+	}
+	if _, err := LoadToken(Session{Profile: "work", Domain: "d", Region: "eu/de"}); !errors.Is(err, ErrSessionMismatch) {
+		t.Errorf("LoadToken() for another region with the same key = %v, want ErrSessionMismatch", err)
+	}
+}
+
+func TestClearProfileKeepsProfilesSharingThePrefix(t *testing.T) {
+	useHome(t)
+	UseStore(NewFileStore(GetCacheDir()))
+
+	SaveToken(&TokenCache{Profile: "a", Domain: "d", Region: "eu-de", ExpiresAt: time.Now().Add(time.Hour)})
+	SaveToken(&TokenCache{Profile: "a__x", Domain: "d", Region: "eu-de", ExpiresAt: time.Now().Add(time.Hour)})
+	other := Session{Profile: "a__x", Domain: "d", Region: "eu-de"}
+	SaveSessionSecret(other, "creds", CredentialCache{Access: "ak"})
+
+	n, err := ClearProfile("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("ClearProfile() removed %d sessions, want 1", n)
+	}
+	if _, err := ReadToken(other); err != nil {
+		t.Errorf("session of profile a__x was removed: %v", err)
+	}
+	var creds CredentialCache
+	if err := LoadSessionSecret(other, "creds", &creds); err != nil {
+		t.Errorf("credentials of profile a__x were removed: %v", err)
+	}
+}
@@ -0,0 +1,285 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/abdo-farag/otc-cli/internal/cache"
+	"github.com/abdo-farag/otc-cli/internal/commands/resource"
+	"github.com/abdo-farag/otc-cli/internal/config"
+	"github.com/abdo-farag/otc-cli/internal/otc"
+	"github.com/abdo-farag/otc-cli/internal/otc/jobs"
+
+	"github.com/fatih/color"
+)
+
+// ExecCredential is the client.authentication.k8s.io/v1 object printed for kubectl
+type ExecCredential struct {
+	APIVersion string               `json:"apiVersion"`
+	Kind       string               `json:"kind"`
+	Status     ExecCredentialStatus `json:"status"`
+}
+
+type ExecCredentialStatus struct {
+	ExpirationTimestamp   string `json:"expirationTimestamp"`
+	ClientCertificateData string `json:"clientCertificateData"`
+	ClientKeyData         string `json:"clientKeyData"`
+}
+
+// CCEToken returns an ExecCredential for a CCE cluster. Certificates are cached
+// with the session (and removed on logout); the credential never outlives the
+// SSO session, so kubectl calls back once the session has to be renewed.
+func CCEToken(cfg *config.Config, client *otc.Client, tokenCache *cache.TokenCache, projectID, cluster string, durationDays int) (*ExecCredential, error) {
+	cert, err := clusterCert(cfg, client, tokenCache.Session(), tokenCache.UnscopedToken, projectID, cluster, durationDays)
+	if err != nil {
+		return nil, err
+	}
+
+	certPEM, keyPEM, err := cert.PEM()
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := cert.ExpiresAt
+	if tokenCache.ExpiresAt.Before(expiresAt) {
+		expiresAt = tokenCache.ExpiresAt
+	}
+
+	return &ExecCredential{
+		APIVersion: "client.authentication.k8s.io/v1",
+		Kind:       "ExecCredential",
+		Status: ExecCredentialStatus{
+			ExpirationTimestamp:   expiresAt.UTC().Format(time.RFC3339),
+			ClientCertificateData: certPEM,
+			ClientKeyData:         keyPEM,
+		},
+	}, nil
+}
+
+// clusterCert returns the session's cached certificate for a cluster, issuing
+// a new one when it is missing or about to expire
+func clusterCert(cfg *config.Config, client *otc.Client, session cache.Session, unscopedToken, projectID, cluster string, durationDays int) (*resource.ClusterCert, error) {
+	name := "cce__" + projectID + "__" + cluster
+
+	var cert resource.ClusterCert
+	err := cache.LoadSessionSecret(session, name, &cert)
+	if err == nil && time.Now().Add(5*time.Minute).Before(cert.ExpiresAt) {
+		return &cert, nil
+	}
+
+	issued, err := resource.GetClusterCert(cfg, client, unscopedToken, projectID, cluster, durationDays)
+	if err != nil {
+		return nil, err
+	}
+
+	// Not fatal: the next call simply requests a new certificate
+	cache.SaveSessionSecret(session, name, issued)
+	return issued, nil
+}
+
+// HibernateCluster hibernates a cluster given by name or ID after asking for
+// confirmation unless yes is set, and waits until it is hibernated
+func HibernateCluster(cfg *config.Config, client *otc.Client, unscopedToken, projectID, clusterNameOrID string, yes bool, jobOpts JobOptions) error {
+	cluster, err := resource.ResolveCluster(cfg, client, unscopedToken, projectID, clusterNameOrID, false)
+	if err != nil {
+		return err
+	}
+
+	switch cluster.Status.Phase {
+	case resource.PhaseHibernation:
+		color.Green("✓ Cluster %s is already hibernated", cluster.Metadata.Name)
+		return nil
+	case resource.PhaseAvailable:
+	default:
+		return fmt.Errorf("cluster %s is %s, only available clusters can hibernate", cluster.Metadata.Name, cluster.Status.Phase)
+	}
+
+	if !yes {
+		question := fmt.Sprintf("Hibernate cluster %s? Its nodes stop and workloads are unavailable until it is awoken.", cluster.Metadata.Name)
+		if err := confirm(question); err != nil {
+			return err
+		}
+	}
+
+	jobID, err := resource.HibernateCluster(cfg, client, unscopedToken, projectID, cluster.Metadata.UID)
+	if err != nil {
+		return err
+	}
+
+	return followClusterOperation(cfg, client, unscopedToken, projectID, cluster, jobID, resource.PhaseHibernation, jobOpts)
+}
+
+// AwakeCluster wakes a hibernated cluster given by name or ID and waits
+// until it is available
+func AwakeCluster(cfg *config.Config, client *otc.Client, unscopedToken, projectID, clusterNameOrID string, jobOpts JobOptions) error {
+	cluster, err := resource.ResolveCluster(cfg, client, unscopedToken, projectID, clusterNameOrID, false)
+	if err != nil {
+		return err
+	}
+
+	switch cluster.Status.Phase {
+	case resource.PhaseAvailable:
+		color.Green("✓ Cluster %s is already awake", cluster.Metadata.Name)
+		return nil
+	case resource.PhaseHibernation:
+	default:
+		return fmt.Errorf("cluster %s is %s, only hibernated clusters can be awoken", cluster.Metadata.Name, cluster.Status.Phase)
+	}
+
+	jobID, err := resource.AwakeCluster(cfg, client, unscopedToken, projectID, cluster.Metadata.UID)
+	if err != nil {
+		return err
+	}
+
+	return followClusterOperation(cfg, client, unscopedToken, projectID, cluster, jobID, resource.PhaseAvailable, jobOpts)
+}
+
+// followClusterOperation waits for the CCE job of a cluster operation, or for
+// the cluster phase if CCE didn't report a job
+func followClusterOperation(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, cluster *resource.Cluster, jobID, phase string, jobOpts JobOptions) error {
+	name, id := cluster.Metadata.Name, cluster.Metadata.UID
+
+	if jobID != "" {
+		job, err := followJob(cfg, client, unscopedToken, projectID, jobs.CCE, jobID, jobOpts)
+		if err != nil || job == nil {
+			return err
+		}
+		color.Green("✓ Cluster %s is %s", name, strings.ToLower(phase))
+		return nil
+	}
+
+	if jobOpts.NoWait {
+		color.Cyan("✓ Cluster %s is changing to %s; follow it with: otc-cli wait cce %s --for status=%s", name, phase, id, phase)
+		return nil
+	}
+
+	return WaitCommand(cfg, client, unscopedToken, "cce", id, projectID, WaitCondition{Status: phase}, jobOpts.Timeout)
+}
+
+// ScaleNodePool sets the node count of a pool and waits until the nodes are
+// up or removed. Scaling in asks for confirmation unless yes is set.
+func ScaleNodePool(cfg *config.Config, client *otc.Client, unscopedToken, projectID, clusterNameOrID, poolNameOrID string, nodes int, yes bool, jobOpts JobOptions) error {
+	cluster, pool, err := resolveNodePool(cfg, client, unscopedToken, projectID, clusterNameOrID, poolNameOrID)
+	if err != nil {
+		return err
+	}
+
+	if a := pool.Spec.Autoscaling; a.Enable && (nodes < a.MinNodeCount || nodes > a.MaxNodeCount) {
+		return fmt.Errorf("node pool %s autoscales between %d and %d nodes; change the range or disable autoscaling first", pool.Metadata.Name, a.MinNodeCount, a.MaxNodeCount)
+	}
+	if pool.Spec.InitialNodeCount == nodes && pool.Status.CurrentNode == nodes {
+		color.Green("✓ Node pool %s already has %d nodes", pool.Metadata.Name, nodes)
+		return nil
+	}
+
+	if nodes < pool.Status.CurrentNode && !yes {
+		question := fmt.Sprintf("Scale node pool %s in from %d to %d nodes? Pods on removed nodes are evicted.", pool.Metadata.Name, pool.Status.CurrentNode, nodes)
+		if err := confirm(question); err != nil {
+			return err
+		}
+	}
+
+	pool.Spec.InitialNodeCount = nodes
+	if err := resource.UpdateNodePool(cfg, client, unscopedToken, projectID, cluster.Metadata.UID, pool); err != nil {
+		return err
+	}
+
+	if jobOpts.NoWait {
+		color.Cyan("✓ Node pool %s is scaling to %d nodes; follow it with: otc-cli list nodepool --cluster %s", pool.Metadata.Name, nodes, cluster.Metadata.Name)
+		return nil
+	}
+	return waitNodePool(cfg, client, unscopedToken, projectID, cluster.Metadata.UID, pool, nodes, jobOpts.Timeout)
+}
+
+// AutoscaleNodePool enables autoscaling of a pool between min and max nodes,
+// or disables it if enable is false. The node count is moved into the range.
+func AutoscaleNodePool(cfg *config.Config, client *otc.Client, unscopedToken, projectID, clusterNameOrID, poolNameOrID string, enable bool, minNodes, maxNodes int) error {
+	cluster, pool, err := resolveNodePool(cfg, client, unscopedToken, projectID, clusterNameOrID, poolNameOrID)
+	if err != nil {
+		return err
+	}
+
+	a := &pool.Spec.Autoscaling
+	if !enable {
+		if !a.Enable {
+			color.Green("✓ Autoscaling of node pool %s is already off", pool.Metadata.Name)
+			return nil
+		}
+		a.Enable = false
+	} else {
+		if a.Enable && a.MinNodeCount == minNodes && a.MaxNodeCount == maxNodes {
+			color.Green("✓ Node pool %s already autoscales between %d and %d nodes", pool.Metadata.Name, minNodes, maxNodes)
+			return nil
+		}
+		a.Enable, a.MinNodeCount, a.MaxNodeCount = true, minNodes, maxNodes
+		pool.Spec.InitialNodeCount = max(minNodes, min(pool.Spec.InitialNodeCount, maxNodes))
+	}
+
+	if err := resource.UpdateNodePool(cfg, client, unscopedToken, projectID, cluster.Metadata.UID, pool); err != nil {
+		return err
+	}
+
+	if enable {
+		color.Green("✓ Node pool %s autoscales between %d and %d nodes", pool.Metadata.Name, minNodes, maxNodes)
+	} else {
+		color.Green("✓ Autoscaling of node pool %s is off; it keeps %d nodes", pool.Metadata.Name, pool.Spec.InitialNodeCount)
+	}
+	return nil
+}
+
+func resolveNodePool(cfg *config.Config, client *otc.Client, unscopedToken, projectID, clusterNameOrID, poolNameOrID string) (*resource.Cluster, *resource.NodePool, error) {
+	cluster, err := resource.ResolveCluster(cfg, client, unscopedToken, projectID, clusterNameOrID, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pool, err := resource.GetNodePool(cfg, client, unscopedToken, projectID, cluster.Metadata.UID, poolNameOrID, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cluster, pool, nil
+}
+
+// waitNodePool polls a node pool until it has settled with the given number
+// of nodes. CCE reports no job for node pool updates.
+func waitNodePool(cfg *config.Config, client *otc.Client, unscopedToken, projectID, clusterID string, pool *resource.NodePool, nodes int, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = jobs.DefaultTimeout
+	}
+	deadline := time.Now().Add(timeout)
+	interval := waitInterval
+	name := pool.Metadata.Name
+
+	color.Yellow("⏳ Waiting for node pool %s to reach %d nodes...", name, nodes)
+
+	state := ""
+	for {
+		if err := client.Sleep(min(interval, time.Until(deadline)+time.Second)); err != nil {
+			return err
+		}
+		interval = min(interval*3/2, maxWaitInterval)
+
+		current, err := resource.GetNodePool(cfg, client, unscopedToken, projectID, clusterID, pool.Metadata.UID, true)
+		if err != nil {
+			return err
+		}
+
+		phase := current.Phase()
+		if s := fmt.Sprintf("%d/%d nodes, %s", current.Status.CurrentNode, nodes, phase); s != state {
+			state = s
+			fmt.Printf("  node pool %s: %s\n", name, state)
+		}
+
+		if current.Status.CurrentNode == nodes && phase == "Active" {
+			color.Green("✓ Node pool %s has %d nodes", name, nodes)
+			return nil
+		}
+		if isErrorStatus(phase) {
+			return fmt.Errorf("node pool %s %w: status %s", name, otc.ErrFailed, phase)
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("waiting for node pool %s %w after %s (%s)", name, otc.ErrTimeout, timeout, state)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/abdo-farag/otc-cli/internal/cache"
+	"github.com/abdo-farag/otc-cli/internal/commands/resource"
+	"github.com/abdo-farag/otc-cli/internal/config"
+	"github.com/abdo-farag/otc-cli/internal/otc"
+)
+
+// CredentialProcessOutput is the JSON document expected by the AWS SDKs from a
+// credential_process command (Version 1)
+type CredentialProcessOutput struct {
+	Version         int    `json:"Version"`
+	AccessKeyId     string `json:"AccessKeyId"`
+	SecretAccessKey string `json:"SecretAccessKey"`
+	SessionToken    string `json:"SessionToken"`
+	Expiration      string `json:"Expiration"`
+}
+
+// CredentialProcess returns temporary AK/SK credentials for the project, reusing
+// cached credentials until they are close to expiry
+func CredentialProcess(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, durationSeconds int) (*CredentialProcessOutput, error) {
+	if durationSeconds < 900 || durationSeconds > 86400 {
+		return nil, fmt.Errorf("duration must be between 900 and 86400 seconds")
+	}
+
+	projectID, projectToken, err := resource.GetProjectToken(cfg, client, unscopedToken, projectID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	session := cache.SessionFor(cfg)
+	creds, err := cache.LoadCredentials(session, projectID)
+	if err != nil || time.Now().Add(5*time.Minute).After(creds.ExpiresAt) {
+		issued, err := client.CreateTemporaryCredentials(projectToken, durationSeconds)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create credentials: %w", err)
+		}
+
+		expiresAt, err := issued.Expiry()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse credential expiry: %w", err)
+		}
+
+		creds = &cache.CredentialCache{
+			Access:        issued.Access,
+			Secret:        issued.Secret,
+			SecurityToken: issued.SecurityToken,
+			ExpiresAt:     expiresAt,
+			ProjectID:     projectID,
+		}
+
+		// Not fatal: the next call simply issues new credentials
+		cache.SaveCredentials(session, creds)
+	}
+
+	return &CredentialProcessOutput{
+		Version:         1,
+		AccessKeyId:     creds.Access,
+		SecretAccessKey: creds.Secret,
+		SessionToken:    creds.SecurityToken,
+		Expiration:      creds.ExpiresAt.UTC().Format(time.RFC3339),
+	}, nil
+}
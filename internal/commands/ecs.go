@@ -0,0 +1,173 @@
+package commands
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/abdo-farag/otc-cli/internal/commands/resource"
+	"github.com/abdo-farag/otc-cli/internal/config"
+	"github.com/abdo-farag/otc-cli/internal/otc"
+	"github.com/abdo-farag/otc-cli/internal/otc/jobs"
+
+	"github.com/fatih/color"
+	"golang.org/x/term"
+)
+
+// How each server action is worded in questions and status messages
+var actionWords = map[string]struct{ verb, done string }{
+	resource.ActionStart:  {"Start", "Started"},
+	resource.ActionStop:   {"Stop", "Stopped"},
+	resource.ActionReboot: {"Reboot", "Rebooted"},
+	resource.ActionDelete: {"Delete", "Deleted"},
+}
+
+// ServerAction runs a lifecycle action on servers given by name or ID and/or
+// by tag, and waits for the ECS job. All actions but start ask for
+// confirmation unless yes is set.
+func ServerAction(cfg *config.Config, client *otc.Client, unscopedToken, projectID, action string, namesOrIDs []string, tag string, opts resource.ServerActionOptions, yes bool, jobOpts JobOptions) error {
+	servers, err := resource.ResolveServers(cfg, client, unscopedToken, projectID, namesOrIDs, tag, false)
+	if err != nil {
+		return err
+	}
+
+	color.Cyan("Servers to %s:", action)
+	ids := make([]string, len(servers))
+	for i, s := range servers {
+		ids[i] = s.ID
+		fmt.Printf("  %s (%s) %s\n", s.Name, s.ID, s.Status)
+	}
+
+	if action != resource.ActionStart && !yes {
+		question := fmt.Sprintf("%s %d server(s)?", actionWords[action].verb, len(servers))
+		if action == resource.ActionDelete {
+			question = fmt.Sprintf("Permanently delete %d server(s)?", len(servers))
+		}
+		if err := confirm(question); err != nil {
+			return err
+		}
+	}
+
+	jobID, err := resource.ServerAction(cfg, client, unscopedToken, projectID, action, ids, opts)
+	if err != nil {
+		return err
+	}
+
+	job, err := followJob(cfg, client, unscopedToken, projectID, jobs.ECS, jobID, jobOpts)
+	if err != nil || job == nil {
+		return err
+	}
+
+	color.Green("✓ %s %d server(s)", actionWords[action].done, len(servers))
+	return nil
+}
+
+// ResizeServer changes the flavor of a server and waits for the ECS job
+func ResizeServer(cfg *config.Config, client *otc.Client, unscopedToken, projectID, nameOrID, flavor string, yes bool, jobOpts JobOptions) error {
+	servers, err := resource.ResolveServers(cfg, client, unscopedToken, projectID, []string{nameOrID}, "", false)
+	if err != nil {
+		return err
+	}
+	server := servers[0]
+
+	if server.Flavor.ID == flavor {
+		color.Green("✓ Server %s already has flavor %s", server.Name, flavor)
+		return nil
+	}
+
+	if !yes {
+		question := fmt.Sprintf("Resize %s from %s to %s?", server.Name, server.Flavor.ID, flavor)
+		if err := confirm(question); err != nil {
+			return err
+		}
+	}
+
+	jobID, err := resource.ResizeServer(cfg, client, unscopedToken, projectID, server.ID, flavor)
+	if err != nil {
+		return err
+	}
+
+	job, err := followJob(cfg, client, unscopedToken, projectID, jobs.ECS, jobID, jobOpts)
+	if err != nil || job == nil {
+		return err
+	}
+
+	color.Green("✓ Resized %s to %s", server.Name, flavor)
+	return nil
+}
+
+// CreateServer creates a server from spec, waits for the ECS job and prints
+// the addresses of the new server
+func CreateServer(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, spec resource.ServerSpec, jobOpts JobOptions) error {
+	color.Yellow("⏳ Creating server %s (%s)...", spec.Name, spec.Flavor)
+
+	jobID, serverID, err := resource.CreateServer(cfg, client, unscopedToken, projectID, spec, false)
+	if err != nil {
+		return err
+	}
+
+	job, err := followJob(cfg, client, unscopedToken, projectID, jobs.ECS, jobID, jobOpts)
+	if err != nil {
+		return err
+	}
+	if job == nil {
+		if serverID != "" {
+			color.Cyan("  Server ID: %s", serverID)
+		}
+		return nil
+	}
+
+	if serverID == "" {
+		for _, sub := range job.SubJobs {
+			if sub.Resource != "" {
+				serverID = sub.Resource
+				break
+			}
+		}
+	}
+	if serverID == "" {
+		color.Green("✓ Server %s created", spec.Name)
+		return nil
+	}
+
+	server, err := resource.GetECS(cfg, client, unscopedToken, projectID, serverID, true)
+	if err != nil {
+		return err
+	}
+
+	color.Green("✓ Server %s created (%s)", server.Name, server.ID)
+	for _, addrs := range server.Addresses {
+		for _, addr := range addrs {
+			if addr.Type == "floating" {
+				fmt.Printf("  EIP:        %s\n", addr.Addr)
+			} else {
+				fmt.Printf("  Private IP: %s\n", addr.Addr)
+			}
+		}
+	}
+	return nil
+}
+
+// ErrConfirmationRequired is returned when a command needs confirmation but
+// can't ask for it. Passing --yes avoids it.
+var ErrConfirmationRequired = errors.New("confirmation required but stdin is not a terminal (use --yes)")
+
+// confirm asks a yes/no question and returns an error unless it is answered
+// with yes. Without a terminal nobody can answer, so the caller needs --yes.
+func confirm(question string) error {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return ErrConfirmationRequired
+	}
+
+	fmt.Printf("%s [y/N]: ", question)
+	reader := bufio.NewReader(os.Stdin)
+	input, _ := reader.ReadString('\n')
+
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "y", "yes":
+		return nil
+	}
+	return fmt.Errorf("aborted")
+}
@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/abdo-farag/otc-cli/internal/commands/resource"
+	"github.com/abdo-farag/otc-cli/internal/config"
+	"github.com/abdo-farag/otc-cli/internal/otc"
+	"github.com/abdo-farag/otc-cli/internal/output"
+
+	"github.com/fatih/color"
+)
+
+// AllocateEIP allocates an EIP and prints it
+func AllocateEIP(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, spec resource.EIPSpec, printer *output.Printer) error {
+	eip, err := resource.AllocateEIP(cfg, client, unscopedToken, projectID, spec)
+	if err != nil {
+		return err
+	}
+
+	if printer.Human() {
+		color.Green("✓ Allocated EIP %s (%d Mbit/s)", eip.PublicIPAddress, eip.BandwidthSize)
+	}
+	return printResult(printer, eip, nil)
+}
+
+// AssociateEIP binds an EIP to a server or port
+func AssociateEIP(cfg *config.Config, client *otc.Client, unscopedToken, projectID, eip, target string) error {
+	address, err := resource.AssociateEIP(cfg, client, unscopedToken, projectID, eip, target)
+	if err != nil {
+		return err
+	}
+
+	color.Green("✓ Associated EIP %s with %s (port %s)", address.PublicIPAddress, target, address.PortID)
+	return nil
+}
+
+// DisassociateEIP unbinds an EIP
+func DisassociateEIP(cfg *config.Config, client *otc.Client, unscopedToken, projectID, eip string) error {
+	address, err := resource.DisassociateEIP(cfg, client, unscopedToken, projectID, eip)
+	if err != nil {
+		return err
+	}
+
+	color.Green("✓ EIP %s is not associated", address.PublicIPAddress)
+	return nil
+}
+
+// ReleaseEIPs releases EIPs after asking for confirmation unless yes is set.
+// EIPs still bound to a port are only released with force, which disassociates
+// them first: the API refuses to delete a bound EIP.
+func ReleaseEIPs(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, eips []string, force, yes bool) error {
+	var addresses []*resource.EIP
+	for _, eip := range eips {
+		address, err := resource.GetEIP(cfg, client, unscopedToken, projectID, eip, true)
+		if err != nil {
+			return err
+		}
+		if address.PortID != "" && !force {
+			return fmt.Errorf("EIP %s is associated with port %s; disassociate it first or use --force", address.PublicIPAddress, address.PortID)
+		}
+		addresses = append(addresses, address)
+	}
+
+	color.Cyan("EIPs to release:")
+	for _, address := range addresses {
+		fmt.Printf("  %s (%s) %s\n", address.PublicIPAddress, address.ID, address.Status)
+	}
+
+	if !yes {
+		if err := confirm(fmt.Sprintf("Release %d EIP(s)? The addresses can't be recovered.", len(addresses))); err != nil {
+			return err
+		}
+	}
+
+	for _, address := range addresses {
+		if address.PortID != "" {
+			if _, err := resource.DisassociateEIP(cfg, client, unscopedToken, projectID, address.ID); err != nil {
+				return fmt.Errorf("failed to disassociate %s: %w", address.PublicIPAddress, err)
+			}
+			// Unbound EIPs are DOWN
+			if err := WaitCommand(cfg, client, unscopedToken, "eip", address.ID, projectID, WaitCondition{Status: "DOWN"}, DefaultWaitTimeout); err != nil {
+				return err
+			}
+		}
+		if err := resource.ReleaseEIP(cfg, client, unscopedToken, projectID, address.ID); err != nil {
+			return fmt.Errorf("failed to release %s: %w", address.PublicIPAddress, err)
+		}
+		color.Green("✓ Released %s", address.PublicIPAddress)
+	}
+	return nil
+}
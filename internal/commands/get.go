@@ -2,33 +2,67 @@ package commands
 
 import (
 	"fmt"
+
+	"github.com/abdo-farag/otc-cli/internal/cache"
 	"github.com/abdo-farag/otc-cli/internal/commands/resource"
 	"github.com/abdo-farag/otc-cli/internal/config"
 	"github.com/abdo-farag/otc-cli/internal/otc"
+	"github.com/abdo-farag/otc-cli/internal/output"
 )
 
 // GetCommand handles all get operations
-func GetCommand(cfg *config.Config, client *otc.Client, unscopedToken, resourceType, resourceID, projectID string, options map[string]interface{}, raw bool) error {
+func GetCommand(cfg *config.Config, client *otc.Client, unscopedToken, resourceType, resourceID, projectID string, options map[string]interface{}, printer *output.Printer) error {
+	// Status messages would corrupt machine-readable output
+	quiet := !printer.Human()
+
+	switch resourceType {
+	case "secgroup", "secgroups", "security-group", "security-groups":
+		return GetSecurityGroup(cfg, client, unscopedToken, projectID, resourceID, printer)
+	case "kubeconfig":
+		// Get output path from options
+		opts := resource.KubeconfigOptions{Profile: cfg.Profile}
+		opts.OutputPath, _ = options["output"].(string)
+		opts.Exec, _ = options["exec"].(bool)
+		opts.Merge, _ = options["merge"].(bool)
+		opts.ContextName, _ = options["context-name"].(string)
+		opts.SetCurrent, _ = options["set-current"].(bool)
+		opts.Endpoint, _ = options["endpoint"].(string)
+		if opts.OutputPath == "" && !opts.Merge {
+			opts.OutputPath = "./kubeconfig"
+		}
+		// CCE only hands out the CA with a certificate: take it from the one
+		// "cce token" caches, so kubectl reuses it instead of requesting another
+		opts.ClusterCA = func(projectID, clusterID string) (string, error) {
+			cert, err := clusterCert(cfg, client, cache.SessionFor(cfg), unscopedToken, projectID, clusterID, 1)
+			if err != nil {
+				return "", err
+			}
+			return cert.CertificateAuthorityData, nil
+		}
+		return resource.GetKubeconfig(cfg, client, unscopedToken, projectID, resourceID, opts)
+	}
+
+	result, err := getResource(cfg, client, unscopedToken, resourceType, resourceID, projectID, quiet)
+	return printResult(printer, result, err)
+}
+
+// getResource fetches a single resource of any type shown by get
+func getResource(cfg *config.Config, client *otc.Client, unscopedToken, resourceType, resourceID, projectID string, quiet bool) (interface{}, error) {
 	switch resourceType {
 	case "ecs", "server", "instance", "servers", "instances":
-		resource.GetECS(cfg, client, unscopedToken, projectID, resourceID, raw)
+		return resource.GetECS(cfg, client, unscopedToken, projectID, resourceID, quiet)
 	case "vpc", "vpcs":
-		resource.GetVPC(cfg, client, unscopedToken, projectID, resourceID, raw)
+		return resource.GetVPC(cfg, client, unscopedToken, projectID, resourceID, quiet)
 	case "subnet", "subnets":
-		resource.GetSubnet(cfg, client, unscopedToken, projectID, resourceID, raw)
+		return resource.GetSubnet(cfg, client, unscopedToken, projectID, resourceID, quiet)
+	case "eip", "eips", "publicip", "publicips":
+		return resource.GetEIP(cfg, client, unscopedToken, projectID, resourceID, quiet)
 	case "volume", "volumes":
-		resource.GetVolume(cfg, client, unscopedToken, projectID, resourceID, raw)
+		return resource.GetVolume(cfg, client, unscopedToken, projectID, resourceID, quiet)
+	case "snapshot", "snapshots":
+		return resource.GetSnapshot(cfg, client, unscopedToken, projectID, resourceID, quiet)
 	case "cce", "cluster", "clusters":
-		resource.GetCCE(cfg, client, unscopedToken, projectID, resourceID, raw)
- case "kubeconfig":
-    // Get output path from options
-    outputPath := "~/.kube"
-    if path, ok := options["output"].(string); ok && path != "" {
-      outputPath = path
-    }
-    resource.GetKubeconfig(cfg, client, unscopedToken, projectID, resourceID, outputPath)
-	default:
-		return fmt.Errorf("unknown resource type: %s", resourceType)
+		return resource.GetCCE(cfg, client, unscopedToken, projectID, resourceID, quiet)
 	}
-	return nil
+	return nil, fmt.Errorf("unknown resource type: %s", resourceType)
 }
@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/abdo-farag/otc-cli/internal/commands/resource"
+	"github.com/abdo-farag/otc-cli/internal/config"
+	"github.com/abdo-farag/otc-cli/internal/otc"
+	"github.com/abdo-farag/otc-cli/internal/otc/jobs"
+	"github.com/abdo-farag/otc-cli/internal/output"
+
+	"github.com/fatih/color"
+)
+
+// JobOptions controls how commands follow the jobs they start
+type JobOptions struct {
+	NoWait  bool          // Return as soon as the job was started
+	Timeout time.Duration // How long to wait for the job (default jobs.DefaultTimeout)
+}
+
+// followJob waits for a job, printing its progress. With NoWait it only tells
+// how to follow the job and returns nil.
+func followJob(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, service jobs.Service, jobID string, opts JobOptions) (*jobs.Job, error) {
+	if opts.NoWait {
+		follow := "otc-cli job wait " + jobID
+		if service != jobs.ECS {
+			follow += " --service " + string(service)
+		}
+		color.Cyan("✓ Job %s started; follow it with: %s", jobID, follow)
+		return nil, nil
+	}
+
+	color.Yellow("⏳ Waiting for job %s...", jobID)
+	return resource.WaitJob(cfg, client, unscopedToken, projectID, service, jobID, jobs.WaitOptions{
+		Timeout:  opts.Timeout,
+		Progress: jobs.Progress(),
+	})
+}
+
+// GetJob prints the current state of a job and its sub-jobs
+func GetJob(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, service jobs.Service, jobID string, printer *output.Printer) error {
+	job, err := resource.GetJob(cfg, client, unscopedToken, projectID, service, jobID, !printer.Human())
+	if err != nil {
+		return err
+	}
+
+	if err := printer.Print(job); err != nil {
+		return err
+	}
+
+	if printer.Human() && len(job.SubJobs) > 0 {
+		subJobs := output.NewList(job.SubJobs, "sub-jobs")
+		subJobs.Title = "Sub-jobs"
+		return printer.Print(subJobs)
+	}
+	return nil
+}
+
+// WaitJob waits for a job started elsewhere and fails if the job fails
+func WaitJob(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, service jobs.Service, jobID string, timeout time.Duration) error {
+	job, err := followJob(cfg, client, unscopedToken, projectID, service, jobID, JobOptions{Timeout: timeout})
+	if err != nil {
+		return err
+	}
+
+	color.Green("✓ %s", job.Summary())
+	return nil
+}
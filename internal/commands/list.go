@@ -2,38 +2,75 @@ package commands
 
 import (
 	"fmt"
+
 	"github.com/abdo-farag/otc-cli/internal/commands/resource"
 	"github.com/abdo-farag/otc-cli/internal/config"
 	"github.com/abdo-farag/otc-cli/internal/otc"
+	"github.com/abdo-farag/otc-cli/internal/output"
 )
 
 // ListCommand handles all list operations
-func ListCommand(cfg *config.Config, client *otc.Client, unscopedToken, resourceType, projectID string, options map[string]interface{}, raw bool) error {
+func ListCommand(cfg *config.Config, client *otc.Client, unscopedToken, resourceType, projectID string, options map[string]interface{}, printer *output.Printer) error {
 	osType, _ := options["os"].(string)
 	if osType == "" {
 		osType = "openlinux"
 	}
+
+	// Status messages would corrupt machine-readable output
+	quiet := !printer.Human()
+
+	// Paginated lists print each page as it arrives when the format allows it
+	if stream := printer.Stream(); stream != nil {
+		options["onPage"] = stream
+	}
+	options["wide"] = printer.Wide()
+
+	var result interface{}
+	var err error
 	switch resourceType {
 	case "project", "projects", "p":
-		resource.ListProjects(cfg, client, unscopedToken, raw)
+		result, err = resource.ListProjects(cfg, client, unscopedToken)
 	case "ecs", "server", "instance", "servers", "instances":
-		resource.ListECS(cfg, client, unscopedToken, projectID, options, raw)
+		result, err = resource.ListECS(cfg, client, unscopedToken, projectID, options, quiet)
 	case "vpc", "vpcs":
-		resource.ListVPC(cfg, client, unscopedToken, projectID, raw)
+		result, err = resource.ListVPC(cfg, client, unscopedToken, projectID, options, quiet)
 	case "subnet", "subnets":
-		resource.ListSubnet(cfg, client, unscopedToken, projectID, raw)
+		result, err = resource.ListSubnet(cfg, client, unscopedToken, projectID, options, quiet)
+	case "secgroup", "secgroups", "security-group", "security-groups":
+		result, err = resource.ListSecurityGroups(cfg, client, unscopedToken, projectID, options, quiet)
+	case "eip", "eips", "publicip", "publicips":
+		result, err = resource.ListEIPs(cfg, client, unscopedToken, projectID, options, quiet)
 	case "volume", "volumes":
-		resource.ListVolume(cfg, client, unscopedToken, projectID, raw)
+		result, err = resource.ListVolume(cfg, client, unscopedToken, projectID, options, quiet)
+	case "snapshot", "snapshots":
+		result, err = resource.ListSnapshots(cfg, client, unscopedToken, projectID, options, quiet)
 	case "cce", "cluster", "clusters":
-		resource.ListCCE(cfg, client, unscopedToken, projectID, raw)
+		result, err = resource.ListCCE(cfg, client, unscopedToken, projectID, quiet)
+	case "nodepool", "nodepools", "pool", "pools":
+		result, err = resource.ListNodePools(cfg, client, unscopedToken, projectID, options, quiet)
+	case "node", "nodes":
+		result, err = resource.ListNodes(cfg, client, unscopedToken, projectID, options, quiet)
 	case "image", "images":
-		resource.ListImages(cfg, client, unscopedToken, projectID, options, raw)
+		result, err = resource.ListImages(cfg, client, unscopedToken, projectID, options, quiet)
 	case "keypair", "keypairs":
-		resource.ListKeypairs(cfg, client, unscopedToken, projectID, raw)
+		result, err = resource.ListKeypairs(cfg, client, unscopedToken, projectID, quiet)
 	case "flavor", "flavors":
-		resource.ListFlavors(cfg, client, unscopedToken, projectID, raw, osType)
+		result, err = resource.ListFlavors(cfg, client, unscopedToken, projectID, osType)
 	default:
 		return fmt.Errorf("unknown resource type: %s", resourceType)
 	}
+
+	return printResult(printer, result, err)
+}
+
+// printResult prints a resource result, or returns the error that prevented it
+func printResult(printer *output.Printer, result interface{}, err error) error {
+	if err != nil {
+		return err
+	}
+
+	if err := printer.Print(result); err != nil {
+		return fmt.Errorf("failed to format output: %w", err)
+	}
 	return nil
 }
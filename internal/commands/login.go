@@ -2,6 +2,7 @@ package commands
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -16,10 +17,11 @@ import (
 	"golang.org/x/term"
 )
 
-func Login(cfg *config.Config) error {
+func Login(ctx context.Context, cfg *config.Config) error {
 	// Step 1: Auth
 	authClient := auth.NewClient(cfg)
-	tokenResp, handler, err := authClient.GetOIDCToken()
+	authClient.SetContext(ctx)
+	tokenResp, handler, err := authClient.Authenticate()
 	if err != nil {
 		return err
 	}
@@ -28,27 +30,21 @@ func Login(cfg *config.Config) error {
 
 	// Step 2: Unscoped Token
 	otcClient := otc.NewClient(cfg)
-	unscopedToken, err := otcClient.GetUnscopedToken(tokenResp.IDToken, handler)
+	unscopedToken, expiresAt, err := otcClient.GetUnscopedToken(tokenResp.IDToken, StatusSetter(handler))
 	if err != nil {
 		return err
 	}
 	color.Green("✓ OTC validated")
 
 	// Save token cache
-	tokenCache := &cache.TokenCache{
-		UnscopedToken: unscopedToken,
-		IDToken:       tokenResp.IDToken,
-		RefreshToken:  tokenResp.RefreshToken,
-		ExpiresAt:     time.Now().Add(23 * time.Hour),
-		Domain:        cfg.DomainName,
-		Region:        cfg.Region,
-	}
+	tokenCache := auth.NewTokenCache(cfg, tokenResp, unscopedToken, expiresAt)
 
 	if err := cache.SaveToken(tokenCache); err != nil {
 		color.Yellow("⚠ Warning: Failed to save token cache: %v", err)
 	} else {
-		color.Green("✓ Token cached at %s", cache.GetTokenPath())
+		color.Green("✓ Token cached at %s", cache.TokenLocation(tokenCache.Session()))
 	}
+	otcClient.UseSession(tokenCache)
 
 	// Step 3: Domain Token
 	domainToken, err := otcClient.GetDomainScopedToken(unscopedToken)
@@ -68,10 +64,11 @@ func Login(cfg *config.Config) error {
 		return fmt.Errorf("no projects found for domain %s", cfg.DomainName)
 	}
 
+	project := pickProject(projects, cfg.Project)
 	color.Green("✓ Found %d project(s)", len(projects))
-	color.Cyan("  Using project: %s (%s)", projects[0].Name, projects[0].ID)
+	color.Cyan("  Using project: %s (%s)", project.Name, project.ID)
 
-	projectToken, err := otcClient.GetProjectScopedToken(unscopedToken, projects[0].ID)
+	projectToken, err := otcClient.GetProjectScopedToken(unscopedToken, project.ID)
 	if err != nil {
 		return fmt.Errorf("failed to get project token: %w", err)
 	}
@@ -83,6 +80,8 @@ func Login(cfg *config.Config) error {
 		return fmt.Errorf("failed to create credentials: %w", err)
 	}
 
+	cacheCredentials(tokenCache.Session(), project.ID, creds)
+
 	// Save
 	if err := creds.SaveShellScript(cfg.OutputFile+".sh", cfg.Region); err != nil {
 		return fmt.Errorf("failed to save credentials: %w", err)
@@ -95,6 +94,49 @@ func Login(cfg *config.Config) error {
 	return nil
 }
 
+// cacheCredentials keeps temporary credentials in the token store for reuse.
+// The shell script stays the primary output, so failures only warn.
+func cacheCredentials(session cache.Session, projectID string, creds *otc.Credentials) {
+	expiresAt, err := creds.Expiry()
+	if err != nil {
+		color.Yellow("⚠ Warning: Failed to cache credentials: %v", err)
+		return
+	}
+
+	err = cache.SaveCredentials(session, &cache.CredentialCache{
+		Access:        creds.Access,
+		Secret:        creds.Secret,
+		SecurityToken: creds.SecurityToken,
+		ExpiresAt:     expiresAt,
+		ProjectID:     projectID,
+	})
+	if err != nil {
+		color.Yellow("⚠ Warning: Failed to cache credentials: %v", err)
+	}
+}
+
+// pickProject returns the project matching the configured default (name or ID),
+// or the first project if none is configured or it isn't found
+func pickProject(projects []otc.Project, defaultProject string) otc.Project {
+	for _, p := range projects {
+		if defaultProject != "" && (p.ID == defaultProject || p.Name == defaultProject) {
+			return p
+		}
+	}
+	if defaultProject != "" {
+		color.Yellow("⚠ Default project '%s' not found, using first project", defaultProject)
+	}
+	return projects[0]
+}
+
+// StatusSetter avoids passing a typed nil handler (device flow) as a non-nil interface
+func StatusSetter(handler *auth.CallbackHandler) otc.ValidationStatusSetter {
+	if handler == nil {
+		return nil
+	}
+	return handler
+}
+
 // LoginIAM handles IAM username/password authentication
 func LoginIAM(cfg *config.Config, username, password string) error {
 	// Step 1: Get unscoped token
@@ -106,9 +148,25 @@ func LoginIAM(cfg *config.Config, username, password string) error {
 	}
 	color.Green("✓ IAM authentication successful")
 
+	// Cache token, together with the scoped tokens issued from it below
+	tokenCache := &cache.TokenCache{
+		UnscopedToken: unscopedToken,
+		IDToken:       unscopedToken,
+		RefreshToken:  "",
+		ExpiresAt:     time.Now().Add(24 * time.Hour),
+		Domain:        cfg.DomainName,
+		Region:        cfg.Region,
+		Profile:       cfg.Profile,
+		IdpURL:        cfg.IdpURL,
+	}
+	if err := cache.SaveToken(tokenCache); err != nil {
+		color.Yellow("⚠ Warning: Failed to save token cache: %v", err)
+	}
+	otcClient := otc.NewClient(cfg)
+	otcClient.UseSession(tokenCache)
+
 	// Step 2: Get domain token
 	color.Yellow("⏳ Step 2: Getting domain token...")
-	otcClient := otc.NewClient(cfg)
 	domainToken, err := otcClient.GetDomainScopedToken(unscopedToken)
 	if err != nil {
 		return fmt.Errorf("failed to get domain token: %w", err)
@@ -126,12 +184,13 @@ func LoginIAM(cfg *config.Config, username, password string) error {
 		return fmt.Errorf("no projects found for domain %s", cfg.DomainName)
 	}
 
+	project := pickProject(projects, cfg.Project)
 	color.Green("✓ Found %d project(s)", len(projects))
-	color.Cyan("  Using project: %s (%s)", projects[0].Name, projects[0].ID)
+	color.Cyan("  Using project: %s (%s)", project.Name, project.ID)
 
 	// Step 4: Get project-scoped token
 	color.Yellow("⏳ Step 4: Getting project token...")
-	projectToken, err := otcClient.GetProjectScopedToken(unscopedToken, projects[0].ID)
+	projectToken, err := otcClient.GetProjectScopedToken(unscopedToken, project.ID)
 	if err != nil {
 		return fmt.Errorf("failed to get project token: %w", err)
 	}
@@ -151,16 +210,7 @@ func LoginIAM(cfg *config.Config, username, password string) error {
 		return fmt.Errorf("failed to save credentials: %w", err)
 	}
 
-	// Cache token
-	tokenCache := &cache.TokenCache{
-		UnscopedToken: unscopedToken,
-		IDToken:       unscopedToken,
-		RefreshToken:  "",
-		ExpiresAt:     time.Now().Add(24 * time.Hour),
-		Domain:        cfg.DomainName,
-		Region:        cfg.Region,
-	}
-	cache.SaveToken(tokenCache)
+	cacheCredentials(tokenCache.Session(), project.ID, creds)
 
 	// Success message
 	color.Green("\n✓ Credentials saved to %s", scriptPath)
@@ -1,100 +1,99 @@
 package resource
 
 import (
-	"encoding/json"
 	"fmt"
+
 	"github.com/abdo-farag/otc-cli/internal/config"
 	"github.com/abdo-farag/otc-cli/internal/otc"
-
-	"github.com/fatih/color"
-	"github.com/rodaine/table"
+	"github.com/abdo-farag/otc-cli/internal/output"
 )
 
-// ListCCE lists all CCE clusters
-func ListCCE(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, raw bool) {
-	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, raw)
-	if err != nil {
-		color.Red("✗ %v", err)
-		return
-	}
-
-	cceURL := fmt.Sprintf("https://cce.%s.otc.t-systems.com/api/v3/projects/%s/clusters", cfg.Region, projectID)
+// Cluster is a CCE Kubernetes cluster
+type Cluster struct {
+	Kind       string `json:"kind,omitempty"`
+	APIVersion string `json:"apiVersion,omitempty"`
+	Metadata   struct {
+		UID               string            `json:"uid"`
+		Name              string            `json:"name"`
+		CreationTimestamp string            `json:"creationTimestamp,omitempty"`
+		Labels            map[string]string `json:"labels,omitempty"`
+	} `json:"metadata"`
+	Spec struct {
+		Type        string `json:"type"`
+		Flavor      string `json:"flavor"`
+		Version     string `json:"version"`
+		Description string `json:"description,omitempty"`
+		HostNetwork struct {
+			VPC    string `json:"vpc"`
+			Subnet string `json:"subnet"`
+		} `json:"hostNetwork"`
+		ContainerNetwork struct {
+			Mode string `json:"mode"`
+			CIDR string `json:"cidr,omitempty"`
+		} `json:"containerNetwork"`
+	} `json:"spec"`
+	Status struct {
+		Phase     string `json:"phase"`
+		Endpoints []struct {
+			URL  string `json:"url"`
+			Type string `json:"type"`
+		} `json:"endpoints,omitempty"`
+	} `json:"status"`
+}
 
-	body, statusCode, err := MakeRequest(cceURL, projectToken)
-	if err != nil {
-		color.Red("✗ Request failed: %v", err)
-		return
+func (Cluster) Columns(wide bool) []string {
+	columns := []string{"Name", "Status", "Type", "Flavor", "Version", "ID"}
+	if wide {
+		columns = append(columns, "Network", "VPC", "Created")
 	}
+	return columns
+}
 
-	if statusCode != 200 {
-		color.Red("✗ API error (status %d): %s", statusCode, string(body))
-		return
+func (c Cluster) Cells(wide bool) []string {
+	cells := []string{c.Metadata.Name, c.Status.Phase, c.Spec.Type, c.Spec.Flavor, c.Spec.Version, c.Metadata.UID}
+	if wide {
+		cells = append(cells,
+			output.OrDash(c.Spec.ContainerNetwork.Mode),
+			output.OrDash(c.Spec.HostNetwork.VPC),
+			output.OrDash(c.Metadata.CreationTimestamp))
 	}
+	return cells
+}
 
-	if raw {
-		var prettyJSON map[string]interface{}
-		json.Unmarshal(body, &prettyJSON)
-		formatted, _ := json.MarshalIndent(prettyJSON, "", "  ")
-		fmt.Println(string(formatted))
-		return
+// ListCCE lists all CCE clusters
+func ListCCE(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, quiet bool) (*output.List[Cluster], error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, quiet)
+	if err != nil {
+		return nil, err
 	}
 
+	cceURL := fmt.Sprintf("%s/api/v3/projects/%s/clusters", client.Endpoint("cce", projectID), projectID)
+
 	var result struct {
-		Clusters []struct {
-			Metadata struct {
-				UID  string `json:"uid"`
-				Name string `json:"name"`
-			} `json:"metadata"`
-			Spec struct {
-				Type    string `json:"type"`
-				Flavor  string `json:"flavor"`
-				Version string `json:"version"`
-			} `json:"spec"`
-			Status struct {
-				Phase string `json:"phase"`
-			} `json:"status"`
-		} `json:"items"`
+		Clusters []Cluster `json:"items"`
 	}
-
-	json.Unmarshal(body, &result)
-
-	headerFmt := color.New(color.FgCyan, color.Bold).SprintfFunc()
-	tbl := table.New("Name", "Status", "Type", "Flavor", "Version", "ID")
-	tbl.WithHeaderFormatter(headerFmt)
-
-	for _, c := range result.Clusters {
-		tbl.AddRow(c.Metadata.Name, c.Status.Phase, c.Spec.Type, c.Spec.Flavor, c.Spec.Version, c.Metadata.UID)
+	if err := client.Get(cceURL, projectToken, &result); err != nil {
+		return nil, err
 	}
 
-	fmt.Printf("\n")
-	color.Cyan("Project: %s", projectID)
-	tbl.Print()
-	fmt.Printf("\nTotal: %d clusters\n", len(result.Clusters))
+	list := output.NewList(result.Clusters, "clusters")
+	list.Title = "Project: " + projectID
+	return list, nil
 }
 
 // GetCCE gets a specific CCE cluster
-func GetCCE(cfg *config.Config, client *otc.Client, unscopedToken, projectID, resourceID string, raw bool) {
-	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, raw)
+func GetCCE(cfg *config.Config, client *otc.Client, unscopedToken, projectID, resourceID string, quiet bool) (*Cluster, error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, quiet)
 	if err != nil {
-		color.Red("✗ %v", err)
-		return
+		return nil, err
 	}
 
-	cceURL := fmt.Sprintf("https://cce.%s.otc.t-systems.com/api/v3/projects/%s/clusters/%s", cfg.Region, projectID, resourceID)
-
-	body, statusCode, err := MakeRequest(cceURL, projectToken)
-	if err != nil {
-		color.Red("✗ Request failed: %v", err)
-		return
-	}
+	cceURL := fmt.Sprintf("%s/api/v3/projects/%s/clusters/%s", client.Endpoint("cce", projectID), projectID, resourceID)
 
-	if statusCode != 200 {
-		color.Red("✗ API error (status %d): %s", statusCode, string(body))
-		return
+	var cluster Cluster
+	if err := client.Get(cceURL, projectToken, &cluster); err != nil {
+		return nil, err
 	}
 
-	var prettyJSON map[string]interface{}
-	json.Unmarshal(body, &prettyJSON)
-	formatted, _ := json.MarshalIndent(prettyJSON, "", "  ")
-	fmt.Println(string(formatted))
+	return &cluster, nil
 }
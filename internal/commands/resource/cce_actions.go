@@ -0,0 +1,115 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/abdo-farag/otc-cli/internal/config"
+	"github.com/abdo-farag/otc-cli/internal/otc"
+)
+
+// Cluster phases the hibernate and awake operations lead to
+const (
+	PhaseAvailable   = "Available"
+	PhaseHibernation = "Hibernation"
+)
+
+// GetNodePool gets a node pool of a cluster by name or ID
+func GetNodePool(cfg *config.Config, client *otc.Client, unscopedToken, projectID, clusterID, nameOrID string, quiet bool) (*NodePool, error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, quiet)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		NodePools []NodePool `json:"items"`
+	}
+	if err := client.Get(clusterURL(client, projectID, clusterID)+"/nodepools", projectToken, &result); err != nil {
+		return nil, err
+	}
+
+	var matches []NodePool
+	for _, p := range result.NodePools {
+		if p.Metadata.UID == nameOrID {
+			return &p, nil
+		}
+		if p.Metadata.Name == nameOrID {
+			matches = append(matches, p)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("node pool %s %w", nameOrID, otc.ErrNotFound)
+	case 1:
+		return &matches[0], nil
+	}
+	return nil, fmt.Errorf("%d node pools are named %s, use the ID", len(matches), nameOrID)
+}
+
+// UpdateNodePool writes the node count and autoscaling settings of a pool.
+// The node template is sent back exactly as GetNodePool read it.
+func UpdateNodePool(cfg *config.Config, client *otc.Client, unscopedToken, projectID, clusterID string, pool *NodePool) error {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, true)
+	if err != nil {
+		return err
+	}
+
+	var template interface{} = pool.Spec.NodeTemplate
+	if len(pool.Spec.NodeTemplate.Raw) > 0 {
+		template = pool.Spec.NodeTemplate.Raw
+	}
+
+	poolURL := fmt.Sprintf("%s/nodepools/%s", clusterURL(client, projectID, clusterID), pool.Metadata.UID)
+	_, err = client.Do("PUT", poolURL, projectToken, map[string]interface{}{
+		"metadata": map[string]string{"name": pool.Metadata.Name},
+		"spec": map[string]interface{}{
+			"initialNodeCount": pool.Spec.InitialNodeCount,
+			"autoscaling":      pool.Spec.Autoscaling,
+			"nodeTemplate":     template,
+		},
+	})
+	return err
+}
+
+// HibernateCluster stops the control plane and worker nodes of a cluster.
+// It returns the CCE job ID if the API reports one.
+func HibernateCluster(cfg *config.Config, client *otc.Client, unscopedToken, projectID, clusterID string) (string, error) {
+	return clusterOperation(cfg, client, unscopedToken, projectID, clusterID, "hibernate")
+}
+
+// AwakeCluster starts a hibernated cluster again. It returns the CCE job ID
+// if the API reports one.
+func AwakeCluster(cfg *config.Config, client *otc.Client, unscopedToken, projectID, clusterID string) (string, error) {
+	return clusterOperation(cfg, client, unscopedToken, projectID, clusterID, "awake")
+}
+
+func clusterOperation(cfg *config.Config, client *otc.Client, unscopedToken, projectID, clusterID, operation string) (string, error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, true)
+	if err != nil {
+		return "", err
+	}
+
+	operationURL := fmt.Sprintf("%s/operation/%s", clusterURL(client, projectID, clusterID), operation)
+	body, err := client.Do("POST", operationURL, projectToken, map[string]interface{}{})
+	if err != nil {
+		return "", err
+	}
+
+	// The operations answer with an empty body or with the job running them
+	var result struct {
+		JobID  string `json:"jobID"`
+		Status struct {
+			JobID string `json:"jobID"`
+		} `json:"status"`
+	}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &result); err != nil {
+			return "", fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
+	if result.JobID == "" {
+		result.JobID = result.Status.JobID
+	}
+	return result.JobID, nil
+}
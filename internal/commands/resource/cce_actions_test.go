@@ -0,0 +1,66 @@
+package resource
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/abdo-farag/otc-cli/internal/config"
+	"github.com/abdo-farag/otc-cli/internal/otc"
+)
+
+const nodePoolTemplate = `{
+	"flavor": "s3.large.2",
+	"az": "eu-de-01",
+	"os": "EulerOS 2.9",
+	"login": {"sshKey": "ops"},
+	"rootVolume": {"volumetype": "SSD", "size": 50},
+	"dataVolumes": [{"volumetype": "SSD", "size": 100}],
+	"k8sTags": {"role": "worker"},
+	"taints": [{"key": "dedicated", "value": "gpu", "effect": "NoSchedule"}],
+	"extendParam": {"maxPods": 110}
+}`
+
+func TestUpdateNodePoolKeepsTemplate(t *testing.T) {
+	var put map[string]interface{}
+	srv := newIAMStub(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v3/projects/p1/clusters/c1/nodepools":
+			io.WriteString(w, `{"items":[{"metadata":{"uid":"np1","name":"pool-a"},"spec":{"initialNodeCount":2,"nodeTemplate":`+nodePoolTemplate+`,"autoscaling":{"enable":false}}}]}`)
+		case r.Method == "PUT" && r.URL.Path == "/api/v3/projects/p1/clusters/c1/nodepools/np1":
+			json.NewDecoder(r.Body).Decode(&put)
+			io.WriteString(w, `{}`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	cfg := &config.Config{AUTHURL: srv.URL, Endpoints: map[string]string{"cce": srv.URL}}
+	client := otc.NewClient(cfg)
+
+	pool, err := GetNodePool(cfg, client, "token", "p1", "c1", "pool-a", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool.Spec.InitialNodeCount = 5
+	pool.Spec.Autoscaling = Autoscaling{Enable: true, MinNodeCount: 1, MaxNodeCount: 8}
+	if err := UpdateNodePool(cfg, client, "token", "p1", "c1", pool); err != nil {
+		t.Fatal(err)
+	}
+
+	spec, _ := put["spec"].(map[string]interface{})
+	if spec["initialNodeCount"] != 5.0 {
+		t.Errorf("initialNodeCount = %v, want 5", spec["initialNodeCount"])
+	}
+	if autoscaling, _ := spec["autoscaling"].(map[string]interface{}); autoscaling["enable"] != true || autoscaling["maxNodeCount"] != 8.0 {
+		t.Errorf("autoscaling = %v, want enabled up to 8 nodes", autoscaling)
+	}
+
+	var want interface{}
+	json.Unmarshal([]byte(nodePoolTemplate), &want)
+	if !reflect.DeepEqual(spec["nodeTemplate"], want) {
+		t.Errorf("nodeTemplate changed:\ngot  %v\nwant %v", spec["nodeTemplate"], want)
+	}
+}
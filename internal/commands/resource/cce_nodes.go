@@ -0,0 +1,234 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/abdo-farag/otc-cli/internal/config"
+	"github.com/abdo-farag/otc-cli/internal/otc"
+	"github.com/abdo-farag/otc-cli/internal/output"
+)
+
+// NodeTemplate is the part of a node spec shared by nodes and node pools
+type NodeTemplate struct {
+	Flavor  string            `json:"flavor"`
+	AZ      string            `json:"az"`
+	OS      string            `json:"os,omitempty"`
+	K8sTags map[string]string `json:"k8sTags,omitempty"`
+	Taints  []Taint           `json:"taints,omitempty"`
+
+	// Raw is the template as the API returned it, including the disks,
+	// login and other fields not modelled here
+	Raw json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the template and keeps a copy of the raw JSON
+func (t *NodeTemplate) UnmarshalJSON(data []byte) error {
+	type template NodeTemplate
+	if err := json.Unmarshal(data, (*template)(t)); err != nil {
+		return err
+	}
+	t.Raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// Taint is a Kubernetes taint put on the nodes of a pool
+type Taint struct {
+	Key    string `json:"key"`
+	Value  string `json:"value,omitempty"`
+	Effect string `json:"effect"`
+}
+
+// NodePool is a group of CCE nodes with the same template
+type NodePool struct {
+	Metadata struct {
+		UID               string `json:"uid"`
+		Name              string `json:"name"`
+		CreationTimestamp string `json:"creationTimestamp,omitempty"`
+	} `json:"metadata"`
+	Spec struct {
+		Type             string       `json:"type"`
+		NodeTemplate     NodeTemplate `json:"nodeTemplate"`
+		InitialNodeCount int          `json:"initialNodeCount"`
+		Autoscaling      Autoscaling  `json:"autoscaling"`
+	} `json:"spec"`
+	Status struct {
+		Phase       string `json:"phase"`
+		CurrentNode int    `json:"currentNode"`
+	} `json:"status"`
+
+	ClusterVersion string `json:"-"` // Kubernetes version of the cluster, for display
+}
+
+// Autoscaling is the autoscaler configuration of a node pool
+type Autoscaling struct {
+	Enable                bool `json:"enable"`
+	MinNodeCount          int  `json:"minNodeCount"`
+	MaxNodeCount          int  `json:"maxNodeCount"`
+	ScaleDownCooldownTime int  `json:"scaleDownCooldownTime,omitempty"`
+	Priority              int  `json:"priority,omitempty"`
+}
+
+func (NodePool) Columns(wide bool) []string {
+	columns := []string{"Name", "Status", "Nodes", "Autoscaling", "Flavor", "AZ", "Cluster Version"}
+	if wide {
+		columns = append(columns, "OS", "ID", "Created")
+	}
+	return columns
+}
+
+func (p NodePool) Cells(wide bool) []string {
+	nodes := fmt.Sprintf("%d/%d", p.Status.CurrentNode, p.Spec.InitialNodeCount)
+	autoscaling := "off"
+	if p.Spec.Autoscaling.Enable {
+		autoscaling = fmt.Sprintf("%d-%d", p.Spec.Autoscaling.MinNodeCount, p.Spec.Autoscaling.MaxNodeCount)
+	}
+
+	cells := []string{p.Metadata.Name, p.Phase(), nodes, autoscaling,
+		p.Spec.NodeTemplate.Flavor, output.OrDash(p.Spec.NodeTemplate.AZ), output.OrDash(p.ClusterVersion)}
+	if wide {
+		cells = append(cells,
+			output.OrDash(p.Spec.NodeTemplate.OS),
+			p.Metadata.UID,
+			output.OrDash(p.Metadata.CreationTimestamp))
+	}
+	return cells
+}
+
+// Phase returns the status of the pool. CCE leaves it empty for pools that
+// are up and not changing.
+func (p NodePool) Phase() string {
+	if p.Status.Phase == "" {
+		return "Active"
+	}
+	return p.Status.Phase
+}
+
+// Node is a CCE worker node backed by an ECS server
+type Node struct {
+	Metadata struct {
+		UID               string            `json:"uid"`
+		Name              string            `json:"name"`
+		CreationTimestamp string            `json:"creationTimestamp,omitempty"`
+		Annotations       map[string]string `json:"annotations,omitempty"`
+	} `json:"metadata"`
+	Spec   NodeTemplate `json:"spec"`
+	Status struct {
+		Phase     string `json:"phase"`
+		ServerID  string `json:"serverId"`
+		PrivateIP string `json:"privateIP"`
+		PublicIP  string `json:"publicIP,omitempty"`
+		JobID     string `json:"jobID,omitempty"`
+	} `json:"status"`
+
+	ClusterVersion string `json:"-"` // Kubernetes version of the cluster, for display
+}
+
+func (Node) Columns(wide bool) []string {
+	columns := []string{"Name", "Status", "Private IP", "Flavor", "AZ", "Cluster Version", "Server ID"}
+	if wide {
+		columns = append(columns, "Public IP", "OS", "Node Pool", "ID", "Created")
+	}
+	return columns
+}
+
+func (n Node) Cells(wide bool) []string {
+	cells := []string{n.Metadata.Name, n.Status.Phase, output.OrDash(n.Status.PrivateIP), n.Spec.Flavor,
+		output.OrDash(n.Spec.AZ), output.OrDash(n.ClusterVersion), output.OrDash(n.Status.ServerID)}
+	if wide {
+		cells = append(cells,
+			output.OrDash(n.Status.PublicIP),
+			output.OrDash(n.Spec.OS),
+			output.OrDash(n.NodePoolID()),
+			n.Metadata.UID,
+			output.OrDash(n.Metadata.CreationTimestamp))
+	}
+	return cells
+}
+
+// NodePoolID returns the ID of the pool the node belongs to, if any
+func (n Node) NodePoolID() string {
+	return n.Metadata.Annotations["kubernetes.io/node-pool.id"]
+}
+
+// ListNodePools lists the node pools of a cluster given by name or ID
+func ListNodePools(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, options map[string]interface{}, quiet bool) (*output.List[NodePool], error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, quiet)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterNameOrID, _ := options["cluster"].(string)
+	cluster, err := lookupCluster(client, projectID, projectToken, clusterNameOrID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		NodePools []NodePool `json:"items"`
+	}
+	if err := client.Get(clusterURL(client, projectID, cluster.Metadata.UID)+"/nodepools", projectToken, &result); err != nil {
+		return nil, err
+	}
+	for i := range result.NodePools {
+		result.NodePools[i].ClusterVersion = cluster.Spec.Version
+	}
+
+	list := output.NewList(result.NodePools, "node pools")
+	list.Title = fmt.Sprintf("Cluster: %s (%s)", cluster.Metadata.Name, cluster.Spec.Version)
+	return list, nil
+}
+
+// ListNodes lists the nodes of a cluster given by name or ID
+func ListNodes(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, options map[string]interface{}, quiet bool) (*output.List[Node], error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, quiet)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterNameOrID, _ := options["cluster"].(string)
+	cluster, err := lookupCluster(client, projectID, projectToken, clusterNameOrID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Nodes []Node `json:"items"`
+	}
+	if err := client.Get(clusterURL(client, projectID, cluster.Metadata.UID)+"/nodes", projectToken, &result); err != nil {
+		return nil, err
+	}
+	for i := range result.Nodes {
+		result.Nodes[i].ClusterVersion = cluster.Spec.Version
+	}
+
+	list := output.NewList(result.Nodes, "nodes")
+	list.Title = fmt.Sprintf("Cluster: %s (%s)", cluster.Metadata.Name, cluster.Spec.Version)
+	return list, nil
+}
+
+// ResolveCluster gets a cluster by name or ID
+func ResolveCluster(cfg *config.Config, client *otc.Client, unscopedToken, projectID, nameOrID string, quiet bool) (*Cluster, error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, quiet)
+	if err != nil {
+		return nil, err
+	}
+	return lookupCluster(client, projectID, projectToken, nameOrID)
+}
+
+func lookupCluster(client *otc.Client, projectID, projectToken, nameOrID string) (*Cluster, error) {
+	clusterID, _, err := resolveCluster(client, projectID, projectToken, nameOrID)
+	if err != nil {
+		return nil, err
+	}
+
+	var cluster Cluster
+	if err := client.Get(clusterURL(client, projectID, clusterID), projectToken, &cluster); err != nil {
+		return nil, err
+	}
+	return &cluster, nil
+}
+
+func clusterURL(client *otc.Client, projectID, clusterID string) string {
+	return fmt.Sprintf("%s/api/v3/projects/%s/clusters/%s", client.Endpoint("cce", projectID), projectID, clusterID)
+}
@@ -1,18 +1,18 @@
 package resource
 
 import (
+	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"github.com/abdo-farag/otc-cli/internal/config"
 	"github.com/abdo-farag/otc-cli/internal/otc"
-	"time"
+	"github.com/abdo-farag/otc-cli/internal/output"
 
 	"github.com/fatih/color"
 )
 
-// GetProjectToken gets or resolves a project token
-func GetProjectToken(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, raw bool) (string, string, error) {
+// GetProjectToken gets or resolves a project token. Status messages are only
+// printed when quiet is false.
+func GetProjectToken(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, quiet bool) (string, string, error) {
 	if projectID == "" {
 		domainToken, err := client.GetDomainScopedToken(unscopedToken)
 		if err != nil {
@@ -20,12 +20,16 @@ func GetProjectToken(cfg *config.Config, client *otc.Client, unscopedToken, proj
 		}
 
 		projects, err := client.ListProjects(domainToken)
-		if err != nil || len(projects) == 0 {
+		if err != nil {
+			// Already says "failed to list projects" and wraps the typed API error
+			return "", "", err
+		}
+		if len(projects) == 0 {
 			return "", "", fmt.Errorf("no projects found")
 		}
 
 		projectID = projects[0].ID
-		if !raw {
+		if !quiet {
 			color.Cyan("✓ Using default project: %s (%s)", projects[0].Name, projectID)
 		}
 	}
@@ -38,19 +42,94 @@ func GetProjectToken(cfg *config.Config, client *otc.Client, unscopedToken, proj
 	return projectID, projectToken, nil
 }
 
-// MakeRequest makes an authenticated HTTP GET request
-func MakeRequest(url, token string) ([]byte, int, error) {
-	req, _ := http.NewRequest("GET", url, nil)
-	req.Header.Set("X-Auth-Token", token)
-	req.Header.Set("Content-Type", "application/json")
+// listAll reads every page of a paginated list endpoint. Items for which keep
+// returns false are dropped, and paging stops once options["limit"] items were
+// kept. When the caller streams output (options["onPage"]), each page is handed
+// over as it arrives instead of being collected, and streamed is true.
+func listAll[T output.Row](client *otc.Client, url, token string, opts otc.ListOptions, options map[string]interface{}, keep func(T) bool) (items []T, streamed bool, err error) {
+	limit, _ := options["limit"].(int)
+	if pageSize, _ := options["page-size"].(int); pageSize > 0 {
+		opts.PageSize = pageSize
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = otc.DefaultPageSize
+	}
+	// Without client-side filters there is no point in fetching more than the limit
+	if limit > 0 && limit < opts.PageSize && keep == nil {
+		opts.PageSize = limit
+	}
+
+	onPage, _ := options["onPage"].(func(output.Tabular) error)
+	count := 0
+
+	err = client.List(url, token, opts, func(raw []json.RawMessage) error {
+		page := make([]T, 0, len(raw))
+		for _, data := range raw {
+			if limit > 0 && count+len(page) >= limit {
+				break
+			}
 
-	httpClient := &http.Client{Timeout: 30 * time.Second}
-	resp, err := httpClient.Do(req)
+			var item T
+			if err := json.Unmarshal(data, &item); err != nil {
+				return fmt.Errorf("failed to parse response: %w", err)
+			}
+			if keep == nil || keep(item) {
+				page = append(page, item)
+			}
+		}
+		count += len(page)
+
+		if onPage == nil {
+			items = append(items, page...)
+		} else if len(page) > 0 {
+			if err := onPage(output.NewList(page, "")); err != nil {
+				return err
+			}
+		}
+
+		if limit > 0 && count >= limit {
+			return otc.ErrStopPaging
+		}
+		return nil
+	})
+
+	return items, onPage != nil, err
+}
+
+// resolveID returns the ID of the item with the given name or ID from a list
+// endpoint whose items have "id" and "name" fields. kind names the items in errors.
+func resolveID(client *otc.Client, url, token, itemsKey, kind, nameOrID string) (string, error) {
+	var matches []string
+
+	err := client.List(url, token, otc.ListOptions{ItemsKey: itemsKey}, func(raw []json.RawMessage) error {
+		for _, data := range raw {
+			var item struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+			}
+			if err := json.Unmarshal(data, &item); err != nil {
+				return fmt.Errorf("failed to parse response: %w", err)
+			}
+
+			if item.ID == nameOrID {
+				matches = []string{item.ID}
+				return otc.ErrStopPaging
+			}
+			if item.Name == nameOrID {
+				matches = append(matches, item.ID)
+			}
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, 0, err
+		return "", err
 	}
-	defer resp.Body.Close()
 
-	body, _ := io.ReadAll(resp.Body)
-	return body, resp.StatusCode, nil
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("%s %s %w", kind, nameOrID, otc.ErrNotFound)
+	case 1:
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("%s name %s matches %d items, use the ID instead", kind, nameOrID, len(matches))
 }
@@ -0,0 +1,150 @@
+package resource
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/abdo-farag/otc-cli/internal/config"
+	"github.com/abdo-farag/otc-cli/internal/otc"
+	"github.com/abdo-farag/otc-cli/internal/output"
+)
+
+// vpcServer serves total VPCs named vpc-0, vpc-1, ... with offset paging and
+// returns the limits it was asked for
+func vpcServer(t *testing.T, total int) (*httptest.Server, *[]string) {
+	t.Helper()
+
+	var limits []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limits = append(limits, r.URL.Query().Get("limit"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+		vpcs := []VPC{}
+		for i := offset; i < min(offset+limit, total); i++ {
+			vpcs = append(vpcs, VPC{ID: fmt.Sprintf("v%d", i), Name: fmt.Sprintf("vpc-%d", i)})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"vpcs": vpcs})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &limits
+}
+
+// newIAMStub starts a server that issues tokens on /v3/auth/tokens and hands
+// every other request to handler. It is closed when the test ends.
+func newIAMStub(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v3/auth/tokens" {
+			w.Header().Set("X-Subject-Token", "scoped-token")
+			w.WriteHeader(http.StatusCreated)
+			io.WriteString(w, `{"token":{"expires_at":"2099-01-01T00:00:00Z"}}`)
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func vpcNames(vpcs []VPC) []string {
+	var names []string
+	for _, v := range vpcs {
+		names = append(names, v.Name)
+	}
+	return names
+}
+
+func TestListAllLimit(t *testing.T) {
+	srv, limits := vpcServer(t, 10)
+	client := otc.NewClient(&config.Config{})
+	opts := otc.ListOptions{ItemsKey: "vpcs", Style: otc.PageOffset}
+
+	vpcs, streamed, err := listAll[VPC](client, srv.URL, "token", opts, map[string]interface{}{"limit": 3}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"vpc-0", "vpc-1", "vpc-2"}; !reflect.DeepEqual(vpcNames(vpcs), want) || streamed {
+		t.Errorf("got %v (streamed %v), want %v", vpcNames(vpcs), streamed, want)
+	}
+	// Without a filter the limit is also the page size
+	if want := []string{"3"}; !reflect.DeepEqual(*limits, want) {
+		t.Errorf("limits requested = %q, want %q", *limits, want)
+	}
+}
+
+func TestListAllLimitWithFilter(t *testing.T) {
+	srv, limits := vpcServer(t, 10)
+	client := otc.NewClient(&config.Config{})
+	opts := otc.ListOptions{ItemsKey: "vpcs", Style: otc.PageOffset}
+	options := map[string]interface{}{"limit": 2, "page-size": 3}
+	keep := func(v VPC) bool { return v.Name == "vpc-1" || v.Name == "vpc-3" || v.Name == "vpc-5" }
+
+	vpcs, _, err := listAll(client, srv.URL, "token", opts, options, keep)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"vpc-1", "vpc-3"}; !reflect.DeepEqual(vpcNames(vpcs), want) {
+		t.Errorf("got %v, want %v", vpcNames(vpcs), want)
+	}
+	// vpc-3 is on the second page, so paging stops there
+	if want := []string{"3", "3"}; !reflect.DeepEqual(*limits, want) {
+		t.Errorf("limits requested = %q, want %q", *limits, want)
+	}
+}
+
+func TestListAllStreamsPages(t *testing.T) {
+	srv, _ := vpcServer(t, 5)
+	client := otc.NewClient(&config.Config{})
+	opts := otc.ListOptions{ItemsKey: "vpcs", Style: otc.PageOffset}
+
+	var pages [][]string
+	options := map[string]interface{}{
+		"page-size": 2,
+		"onPage": func(page output.Tabular) error {
+			var names []string
+			for _, row := range page.Rows(false) {
+				names = append(names, row[0])
+			}
+			pages = append(pages, names)
+			return nil
+		},
+	}
+
+	vpcs, streamed, err := listAll[VPC](client, srv.URL, "token", opts, options, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !streamed || len(vpcs) != 0 {
+		t.Errorf("streamed = %v with %d collected items, want pages handed over instead", streamed, len(vpcs))
+	}
+	if want := [][]string{{"vpc-0", "vpc-1"}, {"vpc-2", "vpc-3"}, {"vpc-4"}}; !reflect.DeepEqual(pages, want) {
+		t.Errorf("pages = %v, want %v", pages, want)
+	}
+}
+
+func TestGetProjectTokenKeepsProjectListError(t *testing.T) {
+	srv := newIAMStub(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/auth/projects":
+			w.WriteHeader(http.StatusForbidden)
+			io.WriteString(w, `{"error":{"code":"IAM.0002","message":"no permission"}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	cfg := &config.Config{AUTHURL: srv.URL}
+	_, _, err := GetProjectToken(cfg, otc.NewClient(cfg), "token", "", true)
+	if !errors.Is(err, otc.ErrForbidden) {
+		t.Errorf("GetProjectToken() = %v, want it to wrap ErrForbidden", err)
+	}
+}
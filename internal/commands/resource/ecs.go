@@ -1,239 +1,221 @@
 package resource
 
 import (
-  "encoding/json"
-  "fmt"
-  "strings"
-
-  "github.com/abdo-farag/otc-cli/internal/config"
-  "github.com/abdo-farag/otc-cli/internal/otc"
-
-  "github.com/fatih/color"
-  "github.com/rodaine/table"
-	
-	golangsdk "github.com/opentelekomcloud/gophertelekomcloud"
-	"github.com/opentelekomcloud/gophertelekomcloud/openstack"
-  "github.com/opentelekomcloud/gophertelekomcloud/openstack/ecs/v1/cloudservers"
-)
+	"fmt"
+	"sort"
+	"strings"
 
-// ListECS lists all ECS instances with optional filters
-func ListECS(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, options map[string]interface{}, raw bool) {
-  projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, raw)
-  if err != nil {
-    color.Red("✗ %v", err)
-    return
-  }
-
-  // Use ECS v1 API endpoint directly (SDK doesn't have List method)
-  computeURL := fmt.Sprintf("https://ecs.%s.otc.t-systems.com/v1/%s/cloudservers/detail", cfg.Region, projectID)
-
-  body, statusCode, err := MakeRequest(computeURL, projectToken)
-  if err != nil {
-    color.Red("✗ Request failed: %v", err)
-    return
-  }
-
-  if statusCode != 200 {
-    color.Red("✗ API error (status %d): %s", statusCode, string(body))
-    return
-  }
-
-  // Use SDK's CloudServer struct for type safety
-  var result struct {
-    Servers []cloudservers.CloudServer `json:"servers"`
-  }
-
-  if err := json.Unmarshal(body, &result); err != nil {
-    color.Red("✗ Failed to parse response: %v", err)
-    return
-  }
-
-  // Apply client-side filters
-  servers := result.Servers
-
-  // Filter by availability zone
-  if azFilter, ok := options["az"].(string); ok && azFilter != "" {
-    var filtered []cloudservers.CloudServer
-    for _, s := range servers {
-      if s.AvailabilityZone == azFilter {
-        filtered = append(filtered, s)
-      }
-    }
-    servers = filtered
-  }
-
-  // Filter by status
-  if statusFilter, ok := options["status"].(string); ok && statusFilter != "" {
-    var filtered []cloudservers.CloudServer
-    for _, s := range servers {
-      if strings.EqualFold(s.Status, statusFilter) {
-        filtered = append(filtered, s)
-      }
-    }
-    servers = filtered
-  }
-
-  // Filter by name (partial match)
-  if nameFilter, ok := options["name"].(string); ok && nameFilter != "" {
-    var filtered []cloudservers.CloudServer
-    for _, s := range servers {
-      if strings.Contains(strings.ToLower(s.Name), strings.ToLower(nameFilter)) {
-        filtered = append(filtered, s)
-      }
-    }
-    servers = filtered
-  }
-
-  // Filter by tag (tags are array of "key=value" strings)
-  if tagFilter, ok := options["tag"].(string); ok && tagFilter != "" {
-    var filtered []cloudservers.CloudServer
-    parts := strings.SplitN(tagFilter, "=", 2)
-
-    for _, s := range servers {
-      matched := false
-
-      if len(parts) == 2 {
-        // Full key=value match
-        searchTag := tagFilter
-        for _, tag := range s.Tags {
-          if tag == searchTag {
-            matched = true
-            break
-          }
-        }
-      } else {
-        // Key only match
-        searchKey := tagFilter + "="
-        for _, tag := range s.Tags {
-          if strings.HasPrefix(tag, searchKey) {
-            matched = true
-            break
-          }
-        }
-      }
-
-      if matched {
-        filtered = append(filtered, s)
-      }
-    }
-    servers = filtered
-  }
-
-  if raw {
-    jsonData, _ := json.MarshalIndent(servers, "", "  ")
-    fmt.Println(string(jsonData))
-    return
-  }
-
-  // Display table
-  displayServersTable(servers, projectID, options)
-}
+	"github.com/abdo-farag/otc-cli/internal/config"
+	"github.com/abdo-farag/otc-cli/internal/otc"
+	"github.com/abdo-farag/otc-cli/internal/output"
 
-// extractIPv4FromAddresses extracts IPv4 addresses from server addresses
-func extractIPv4FromAddresses(addresses map[string][]cloudservers.Address) []string {
-  var ipv4s []string
+	"github.com/opentelekomcloud/gophertelekomcloud/openstack/ecs/v1/cloudservers"
+)
 
-  for _, addrs := range addresses {
-    for _, addr := range addrs {
-      if addr.Version == "4" {
-        ipv4s = append(ipv4s, addr.Addr)
-      }
-    }
-  }
+// Server is an Elastic Cloud Server
+type Server struct {
+	cloudservers.CloudServer
 
-  return ipv4s
+	// EIPs bound to the server's ports; only looked up by ListECS for wide cells
+	EIPs []string `json:"-"`
 }
 
-func displayServersTable(servers []cloudservers.CloudServer, projectID string, options map[string]interface{}) {
-  headerFmt := color.New(color.FgCyan, color.Bold).SprintfFunc()
-  tbl := table.New("Name", "Status", "IPv4", "Flavor", "AZ", "ID")
-  tbl.WithHeaderFormatter(headerFmt)
-
-  for _, s := range servers {
-    flavorID := s.Flavor.ID
-    if flavorID == "" {
-      flavorID = "-"
-    }
-
-    az := s.AvailabilityZone
-    if az == "" {
-      az = "-"
-    }
-
-    // Extract IPv4 addresses
-    ipv4s := extractIPv4FromAddresses(s.Addresses)
-    ips := "-"
-    if len(ipv4s) > 0 {
-      ips = strings.Join(ipv4s, ", ")
-    }
-
-    tbl.AddRow(s.Name, s.Status, ips, flavorID, az, s.ID)
-  }
-
-  fmt.Printf("\n")
-  color.Cyan("Project: %s", projectID)
-
-  // Show active filters
-  if az, ok := options["az"].(string); ok && az != "" {
-    color.Yellow("Filter: AZ = %s", az)
-  }
-  if status, ok := options["status"].(string); ok && status != "" {
-    color.Yellow("Filter: Status = %s", status)
-  }
-  if name, ok := options["name"].(string); ok && name != "" {
-    color.Yellow("Filter: Name contains '%s'", name)
-  }
-  if tag, ok := options["tag"].(string); ok && tag != "" {
-    color.Yellow("Filter: Tag = %s", tag)
-  }
-
-  tbl.Print()
-  fmt.Printf("\nTotal: %d instances\n", len(servers))
+func (Server) Columns(wide bool) []string {
+	if wide {
+		return []string{"Name", "Status", "Private IP", "EIP", "Flavor", "AZ", "ID", "Image", "Key", "Created"}
+	}
+	return []string{"Name", "Status", "IPv4", "Flavor", "AZ", "ID"}
 }
 
-// GetECS gets a specific ECS instance using SDK
-func GetECS(cfg *config.Config, client *otc.Client, unscopedToken, projectID, resourceID string, raw bool) {
-	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, raw)
+func (s Server) Cells(wide bool) []string {
+	if wide {
+		return []string{
+			s.Name,
+			s.Status,
+			output.OrDash(strings.Join(extractIPv4FromAddresses(s.Addresses, "fixed"), ", ")),
+			output.OrDash(strings.Join(s.EIPs, ", ")),
+			output.OrDash(s.Flavor.ID),
+			output.OrDash(s.AvailabilityZone),
+			s.ID,
+			output.OrDash(s.Metadata.ImageName),
+			output.OrDash(s.KeyName),
+			s.Created.Format("2006-01-02 15:04"),
+		}
+	}
+	return []string{
+		s.Name,
+		s.Status,
+		output.OrDash(strings.Join(extractIPv4FromAddresses(s.Addresses, ""), ", ")),
+		output.OrDash(s.Flavor.ID),
+		output.OrDash(s.AvailabilityZone),
+		s.ID,
+	}
+}
+
+// ListECS lists all ECS instances with optional filters
+func ListECS(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, options map[string]interface{}, quiet bool) (*output.List[Server], error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, quiet)
 	if err != nil {
-		color.Red("✗ %v", err)
-		return
+		return nil, err
 	}
 
-	// Create authenticated client
-	authOpts := golangsdk.AuthOptions{
-		IdentityEndpoint: cfg.AUTHURL,
-		TokenID:          projectToken,
-		TenantID:         projectID,
+	// Use ECS v1 API endpoint directly (SDK doesn't have List method)
+	computeURL := fmt.Sprintf("%s/v1/%s/cloudservers/detail", client.Endpoint("ecs", projectID), projectID)
+
+	// Wide output shows the EIP bound to each server port, also on streamed pages
+	var byPort map[string]string
+	if wide, _ := options["wide"].(bool); wide {
+		byPort, err = eipsByPort(client, projectID, projectToken)
+		if err != nil {
+			return nil, err
+		}
+		if onPage, ok := options["onPage"].(func(output.Tabular) error); ok {
+			options["onPage"] = func(page output.Tabular) error {
+				if list, ok := page.(*output.List[Server]); ok {
+					setEIPs(list.Items, byPort)
+				}
+				return onPage(page)
+			}
+		}
 	}
 
-	provider, err := openstack.AuthenticatedClient(authOpts)
+	// ECS pages by page number: offset is the page, not the number of items to skip
+	servers, streamed, err := listAll(client, computeURL, projectToken, otc.ListOptions{ItemsKey: "servers", Style: otc.PageNumber}, options, serverFilter(options))
 	if err != nil {
-		color.Red("✗ Failed to create authenticated client: %v", err)
-		return
+		return nil, err
+	}
+	if byPort != nil {
+		setEIPs(servers, byPort)
 	}
 
-	// Create ECS client
-	ecsClient, err := openstack.NewComputeV1(provider, golangsdk.EndpointOpts{
-		Region: cfg.Region,
-	})
-	if err != nil {
-		color.Red("✗ Failed to create ECS client: %v", err)
-		return
+	list := output.NewList(servers, "instances")
+	list.Streamed = streamed
+	list.Title = "Project: " + projectID
+
+	// Show active filters
+	if az, ok := options["az"].(string); ok && az != "" {
+		list.Notes = append(list.Notes, fmt.Sprintf("Filter: AZ = %s", az))
+	}
+	if status, ok := options["status"].(string); ok && status != "" {
+		list.Notes = append(list.Notes, fmt.Sprintf("Filter: Status = %s", status))
+	}
+	if name, ok := options["name"].(string); ok && name != "" {
+		list.Notes = append(list.Notes, fmt.Sprintf("Filter: Name contains '%s'", name))
+	}
+	if tag, ok := options["tag"].(string); ok && tag != "" {
+		list.Notes = append(list.Notes, fmt.Sprintf("Filter: Tag = %s", tag))
 	}
 
-	// Use SDK's Get method
-	server, err := cloudservers.Get(ecsClient, resourceID).Extract()
-	if err != nil {
-		color.Red("✗ Failed to get server: %v", err)
-		return
+	return list, nil
+}
+
+// serverFilter returns the client-side filters of ListECS, or nil if none are set
+func serverFilter(options map[string]interface{}) func(Server) bool {
+	azFilter, _ := options["az"].(string)
+	statusFilter, _ := options["status"].(string)
+	nameFilter, _ := options["name"].(string)
+	tagFilter, _ := options["tag"].(string)
+
+	if azFilter == "" && statusFilter == "" && nameFilter == "" && tagFilter == "" {
+		return nil
 	}
 
-	// Output as JSON
-	jsonData, err := json.MarshalIndent(server, "", "  ")
+	return func(s Server) bool {
+		// Filter by availability zone
+		if azFilter != "" && s.AvailabilityZone != azFilter {
+			return false
+		}
+
+		// Filter by status
+		if statusFilter != "" && !strings.EqualFold(s.Status, statusFilter) {
+			return false
+		}
+
+		// Filter by name (partial match)
+		if nameFilter != "" && !strings.Contains(strings.ToLower(s.Name), strings.ToLower(nameFilter)) {
+			return false
+		}
+
+		// Filter by tag (tags are array of "key=value" strings)
+		if tagFilter != "" && !hasTag(s.Tags, tagFilter) {
+			return false
+		}
+
+		return true
+	}
+}
+
+// hasTag matches "key=value" exactly or "key" against the key only
+func hasTag(tags []string, filter string) bool {
+	for _, tag := range tags {
+		if strings.Contains(filter, "=") {
+			if tag == filter {
+				return true
+			}
+		} else if strings.HasPrefix(tag, filter+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// extractIPv4FromAddresses extracts IPv4 addresses from server addresses,
+// only those of addrType ("fixed" or "floating") if it is set
+func extractIPv4FromAddresses(addresses map[string][]cloudservers.Address, addrType string) []string {
+	var ipv4s []string
+
+	for _, addrs := range addresses {
+		for _, addr := range addrs {
+			if addr.Version == "4" && (addrType == "" || addr.Type == addrType) {
+				ipv4s = append(ipv4s, addr.Addr)
+			}
+		}
+	}
+
+	return ipv4s
+}
+
+// setEIPs sets the EIPs of servers from byPort, the EIPs bound to each port.
+// Floating addresses ECS reports itself are kept.
+func setEIPs(servers []Server, byPort map[string]string) {
+	for i := range servers {
+		s := &servers[i]
+		seen := map[string]bool{}
+		add := func(ip string) {
+			if ip != "" && !seen[ip] {
+				seen[ip] = true
+				s.EIPs = append(s.EIPs, ip)
+			}
+		}
+
+		for _, addrs := range s.Addresses {
+			for _, addr := range addrs {
+				if addr.Type == "floating" {
+					add(addr.Addr)
+				} else {
+					add(byPort[addr.PortID])
+				}
+			}
+		}
+		sort.Strings(s.EIPs)
+	}
+}
+
+// GetECS gets a specific ECS instance
+func GetECS(cfg *config.Config, client *otc.Client, unscopedToken, projectID, resourceID string, quiet bool) (*Server, error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, quiet)
 	if err != nil {
-		color.Red("✗ Failed to format output: %v", err)
-		return
+		return nil, err
+	}
+
+	serverURL := fmt.Sprintf("%s/v1/%s/cloudservers/%s", client.Endpoint("ecs", projectID), projectID, resourceID)
+
+	var result struct {
+		Server Server `json:"server"`
+	}
+	if err := client.Get(serverURL, projectToken, &result); err != nil {
+		return nil, err
 	}
 
-	fmt.Println(string(jsonData))
+	return &result.Server, nil
 }
@@ -0,0 +1,161 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/abdo-farag/otc-cli/internal/config"
+	"github.com/abdo-farag/otc-cli/internal/otc"
+)
+
+// Server actions accepted by ServerAction
+const (
+	ActionStart  = "start"
+	ActionStop   = "stop"
+	ActionReboot = "reboot"
+	ActionDelete = "delete"
+)
+
+// ServerActionOptions tunes a batch server action
+type ServerActionOptions struct {
+	Hard          bool // Forced stop or reboot instead of a graceful one
+	DeleteEIP     bool // Release EIPs bound to deleted servers
+	DeleteVolumes bool // Delete data disks attached to deleted servers
+}
+
+// ResolveServers resolves server names or IDs, plus all servers carrying tag
+// (key or key=value) if it is set. Names must match exactly one server.
+func ResolveServers(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, namesOrIDs []string, tag string, quiet bool) ([]Server, error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, quiet)
+	if err != nil {
+		return nil, err
+	}
+
+	computeURL := fmt.Sprintf("%s/v1/%s/cloudservers/detail", client.Endpoint("ecs", projectID), projectID)
+	all, _, err := listAll[Server](client, computeURL, projectToken, otc.ListOptions{ItemsKey: "servers", Style: otc.PageNumber}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var servers []Server
+	seen := map[string]bool{}
+	add := func(s Server) {
+		if !seen[s.ID] {
+			seen[s.ID] = true
+			servers = append(servers, s)
+		}
+	}
+
+	for _, nameOrID := range namesOrIDs {
+		var matches []Server
+		for _, s := range all {
+			if s.ID == nameOrID {
+				matches = []Server{s}
+				break
+			}
+			if s.Name == nameOrID {
+				matches = append(matches, s)
+			}
+		}
+
+		switch len(matches) {
+		case 0:
+			return nil, fmt.Errorf("server %s %w", nameOrID, otc.ErrNotFound)
+		case 1:
+			add(matches[0])
+		default:
+			return nil, fmt.Errorf("server name %s matches %d servers, use the ID instead", nameOrID, len(matches))
+		}
+	}
+
+	if tag != "" {
+		for _, s := range all {
+			if hasTag(s.Tags, tag) {
+				add(s)
+			}
+		}
+		if len(servers) == 0 {
+			return nil, fmt.Errorf("servers tagged %s %w", tag, otc.ErrNotFound)
+		}
+	}
+
+	return servers, nil
+}
+
+// ServerAction starts, stops, reboots or deletes servers in one batch request
+// and returns the ID of the ECS job
+func ServerAction(cfg *config.Config, client *otc.Client, unscopedToken, projectID, action string, serverIDs []string, opts ServerActionOptions) (string, error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, true)
+	if err != nil {
+		return "", err
+	}
+
+	servers := make([]map[string]string, len(serverIDs))
+	for i, id := range serverIDs {
+		servers[i] = map[string]string{"id": id}
+	}
+
+	actionType := "SOFT"
+	if opts.Hard {
+		actionType = "HARD"
+	}
+
+	baseURL := fmt.Sprintf("%s/v1/%s/cloudservers", client.Endpoint("ecs", projectID), projectID)
+	actionURL := baseURL + "/action"
+
+	var payload map[string]interface{}
+	switch action {
+	case ActionStart:
+		payload = map[string]interface{}{"os-start": map[string]interface{}{"servers": servers}}
+	case ActionStop:
+		payload = map[string]interface{}{"os-stop": map[string]interface{}{"type": actionType, "servers": servers}}
+	case ActionReboot:
+		payload = map[string]interface{}{"reboot": map[string]interface{}{"type": actionType, "servers": servers}}
+	case ActionDelete:
+		actionURL = baseURL + "/delete"
+		payload = map[string]interface{}{
+			"servers":         servers,
+			"delete_publicip": opts.DeleteEIP,
+			"delete_volume":   opts.DeleteVolumes,
+		}
+	default:
+		return "", fmt.Errorf("unknown server action: %s", action)
+	}
+
+	return startJob(client, "POST", actionURL, projectToken, payload)
+}
+
+// ResizeServer changes the flavor of a server and returns the ID of the ECS job
+func ResizeServer(cfg *config.Config, client *otc.Client, unscopedToken, projectID, serverID, flavor string) (string, error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, true)
+	if err != nil {
+		return "", err
+	}
+
+	resizeURL := fmt.Sprintf("%s/v1/%s/cloudservers/%s/resize", client.Endpoint("ecs", projectID), projectID, serverID)
+	payload := map[string]interface{}{
+		"resize": map[string]string{"flavorRef": flavor},
+	}
+
+	return startJob(client, "POST", resizeURL, projectToken, payload)
+}
+
+// startJob sends a request that starts an asynchronous job and returns its ID
+func startJob(client *otc.Client, method, url, token string, payload interface{}) (string, error) {
+	var result struct {
+		JobID string `json:"job_id"`
+	}
+
+	body, err := client.Do(method, url, token, payload)
+	if err != nil {
+		return "", err
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	if result.JobID == "" {
+		return "", fmt.Errorf("no job_id in response")
+	}
+
+	return result.JobID, nil
+}
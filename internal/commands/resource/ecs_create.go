@@ -0,0 +1,338 @@
+package resource
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/abdo-farag/otc-cli/internal/config"
+	"github.com/abdo-farag/otc-cli/internal/otc"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Defaults for servers created from a spec
+const (
+	defaultDiskType     = "SSD"
+	defaultEIPType      = "5_bgp"
+	defaultEIPBandwidth = 10 // Mbit/s
+)
+
+// ServerSpec describes a server to create. It is read from a YAML spec file
+// and/or command line flags; names are resolved to IDs by CreateServer.
+type ServerSpec struct {
+	Name             string            `yaml:"name"`
+	Flavor           string            `yaml:"flavor"`
+	Image            string            `yaml:"image"`                       // Name or ID
+	VPC              string            `yaml:"vpc,omitempty"`               // Name or ID, defaults to the subnet's VPC
+	Subnet           string            `yaml:"subnet"`                      // Name or ID
+	AvailabilityZone string            `yaml:"availability_zone,omitempty"` // Defaults to the subnet's AZ
+	Keypair          string            `yaml:"keypair,omitempty"`
+	SecurityGroups   []string          `yaml:"security_groups,omitempty"` // Names or IDs
+	SystemDisk       DiskSpec          `yaml:"system_disk,omitempty"`
+	DataDisks        []DiskSpec        `yaml:"data_disks,omitempty"`
+	EIP              bool              `yaml:"eip,omitempty"`           // Allocate and bind a new EIP
+	EIPBandwidth     int               `yaml:"eip_bandwidth,omitempty"` // Mbit/s
+	Tags             map[string]string `yaml:"tags,omitempty"`
+	UserDataFile     string            `yaml:"user_data_file,omitempty"`
+}
+
+// DiskSpec is the size (GB) and type (SATA, SAS, SSD, ...) of a disk
+type DiskSpec struct {
+	Size int    `yaml:"size,omitempty"`
+	Type string `yaml:"type,omitempty"`
+}
+
+// LoadServerSpec reads a server spec from a YAML file
+func LoadServerSpec(path string) (*ServerSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec: %w", err)
+	}
+
+	var spec ServerSpec
+	if err := yaml.UnmarshalStrict(data, &spec); err != nil {
+		return nil, fmt.Errorf("invalid spec %s: %w", path, err)
+	}
+	return &spec, nil
+}
+
+// Validate checks that the required fields are set
+func (s *ServerSpec) Validate() error {
+	var missing []string
+	for field, value := range map[string]string{"name": s.Name, "flavor": s.Flavor, "image": s.Image, "subnet": s.Subnet} {
+		if value == "" {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("missing required fields: %v", missing)
+	}
+
+	for _, disk := range append([]DiskSpec{s.SystemDisk}, s.DataDisks...) {
+		if disk.Size < 0 {
+			return fmt.Errorf("invalid disk size: %d", disk.Size)
+		}
+	}
+	return nil
+}
+
+// CreateServer resolves the names in spec, validates the flavor against the
+// flavors of the availability zone and starts creating the server. It returns the ID of
+// the ECS job and the ID of the new server.
+func CreateServer(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, spec ServerSpec, quiet bool) (string, string, error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, quiet)
+	if err != nil {
+		return "", "", err
+	}
+
+	image, err := resolveImage(client, projectID, projectToken, spec.Image)
+	if err != nil {
+		return "", "", err
+	}
+
+	vpcID := ""
+	if spec.VPC != "" {
+		vpcURL := fmt.Sprintf("%s/v1/%s/vpcs", client.Endpoint("vpc", projectID), projectID)
+		if vpcID, err = resolveID(client, vpcURL, projectToken, "vpcs", "VPC", spec.VPC); err != nil {
+			return "", "", err
+		}
+	}
+
+	subnet, err := resolveSubnet(client, projectID, projectToken, vpcID, spec.Subnet)
+	if err != nil {
+		return "", "", err
+	}
+
+	securityGroups := []map[string]string{}
+	for _, sg := range spec.SecurityGroups {
+		id, err := resolveID(client, securityGroupsURL(client, projectID), projectToken, "security_groups", "security group", sg)
+		if err != nil {
+			return "", "", err
+		}
+		securityGroups = append(securityGroups, map[string]string{"id": id})
+	}
+
+	az := spec.AvailabilityZone
+	if az == "" {
+		az = subnet.AvailabilityZone
+	}
+	if az == "" {
+		return "", "", fmt.Errorf("subnet %s has no availability zone, set one for the server", subnet.Name)
+	}
+
+	if err := validateFlavor(client, projectID, projectToken, az, spec.Flavor); err != nil {
+		return "", "", err
+	}
+
+	// The system disk must hold the image
+	systemDisk := spec.SystemDisk
+	if systemDisk.Size == 0 {
+		systemDisk.Size = max(image.MinDisk, 40)
+	}
+	if systemDisk.Size < image.MinDisk {
+		return "", "", fmt.Errorf("system disk of %d GB is smaller than the %d GB image %s needs", systemDisk.Size, image.MinDisk, image.Name)
+	}
+
+	server := map[string]interface{}{
+		"name":              spec.Name,
+		"flavorRef":         spec.Flavor,
+		"imageRef":          image.ID,
+		"vpcid":             subnet.VpcID,
+		"availability_zone": az,
+		"nics":              []map[string]string{{"subnet_id": subnet.ID}},
+		"root_volume":       volumeSpec(systemDisk),
+		"count":             1,
+	}
+
+	if len(securityGroups) > 0 {
+		server["security_groups"] = securityGroups
+	}
+	if spec.Keypair != "" {
+		server["key_name"] = spec.Keypair
+	}
+
+	if len(spec.DataDisks) > 0 {
+		volumes := make([]map[string]interface{}, len(spec.DataDisks))
+		for i, disk := range spec.DataDisks {
+			if disk.Size == 0 {
+				return "", "", fmt.Errorf("data disk %d needs a size", i+1)
+			}
+			volumes[i] = volumeSpec(disk)
+		}
+		server["data_volumes"] = volumes
+	}
+
+	if spec.EIP {
+		bandwidth := spec.EIPBandwidth
+		if bandwidth == 0 {
+			bandwidth = defaultEIPBandwidth
+		}
+		server["publicip"] = map[string]interface{}{
+			"eip": map[string]interface{}{
+				"iptype": defaultEIPType,
+				"bandwidth": map[string]interface{}{
+					"size":       bandwidth,
+					"sharetype":  "PER",
+					"chargemode": "traffic",
+				},
+			},
+		}
+	}
+
+	if len(spec.Tags) > 0 {
+		keys := make([]string, 0, len(spec.Tags))
+		for key := range spec.Tags {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		tags := make([]map[string]string, len(keys))
+		for i, key := range keys {
+			tags[i] = map[string]string{"key": key, "value": spec.Tags[key]}
+		}
+		server["server_tags"] = tags
+	}
+
+	if spec.UserDataFile != "" {
+		data, err := os.ReadFile(spec.UserDataFile)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read user data: %w", err)
+		}
+		server["user_data"] = base64.StdEncoding.EncodeToString(data)
+	}
+
+	createURL := fmt.Sprintf("%s/v1/%s/cloudservers", client.Endpoint("ecs", projectID), projectID)
+	body, err := client.Do("POST", createURL, projectToken, map[string]interface{}{"server": server})
+	if err != nil {
+		return "", "", err
+	}
+
+	var result struct {
+		JobID     string   `json:"job_id"`
+		ServerIDs []string `json:"serverIds"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	if result.JobID == "" {
+		return "", "", fmt.Errorf("no job_id in response")
+	}
+
+	serverID := ""
+	if len(result.ServerIDs) > 0 {
+		serverID = result.ServerIDs[0]
+	}
+	return result.JobID, serverID, nil
+}
+
+func volumeSpec(disk DiskSpec) map[string]interface{} {
+	volumeType := disk.Type
+	if volumeType == "" {
+		volumeType = defaultDiskType
+	}
+	return map[string]interface{}{"volumetype": volumeType, "size": disk.Size}
+}
+
+// validateFlavor checks that flavor can be used in the availability zone az
+func validateFlavor(client *otc.Client, projectID, projectToken, az, flavor string) error {
+	flavorsURL := fmt.Sprintf("%s/v1/%s/cloudservers/flavors?availability_zone=%s", client.Endpoint("ecs", projectID), projectID, url.QueryEscape(az))
+
+	var result struct {
+		Flavors []struct {
+			ID string `json:"id"`
+		} `json:"flavors"`
+	}
+	if err := client.Get(flavorsURL, projectToken, &result); err != nil {
+		return fmt.Errorf("failed to list flavors: %w", err)
+	}
+
+	for _, f := range result.Flavors {
+		if f.ID == flavor {
+			return nil
+		}
+	}
+	return fmt.Errorf("flavor %s is not available in %s (see: otc-cli list flavor)", flavor, az)
+}
+
+// resolveImage finds an image by ID or exact name
+func resolveImage(client *otc.Client, projectID, projectToken, nameOrID string) (*Image, error) {
+	imageURL := client.Endpoint("ims", projectID) + "/v2/cloudimages"
+
+	params := []string{"name"}
+	if IsUUID(nameOrID) {
+		params = []string{"id", "name"}
+	}
+
+	for _, param := range params {
+		images, _, err := listAll[Image](client, imageURL+"?"+param+"="+url.QueryEscape(nameOrID), projectToken, otc.ListOptions{ItemsKey: "images"}, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		switch len(images) {
+		case 0:
+			continue
+		case 1:
+			return &images[0], nil
+		default:
+			return nil, fmt.Errorf("image name %s matches %d images, use the ID instead", nameOrID, len(images))
+		}
+	}
+
+	return nil, fmt.Errorf("image %s %w", nameOrID, otc.ErrNotFound)
+}
+
+// resolveSubnet finds a subnet by ID or name, within vpcID if it is set
+func resolveSubnet(client *otc.Client, projectID, projectToken, vpcID, nameOrID string) (*Subnet, error) {
+	subnetURL := fmt.Sprintf("%s/v1/%s/subnets", client.Endpoint("vpc", projectID), projectID)
+	if vpcID != "" {
+		subnetURL += "?vpc_id=" + url.QueryEscape(vpcID)
+	}
+
+	subnets, _, err := listAll[Subnet](client, subnetURL, projectToken, otc.ListOptions{ItemsKey: "subnets"}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Subnet
+	for _, s := range subnets {
+		if s.ID == nameOrID {
+			return &s, nil
+		}
+		if s.Name == nameOrID {
+			matches = append(matches, s)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("subnet %s %w", nameOrID, otc.ErrNotFound)
+	case 1:
+		return &matches[0], nil
+	}
+	return nil, fmt.Errorf("subnet name %s matches %d subnets, set the VPC or use the ID instead", nameOrID, len(matches))
+}
+
+// IsUUID reports whether s has the form of the IDs OTC services use
+func IsUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i, c := range s {
+		switch {
+		case i == 8 || i == 13 || i == 18 || i == 23:
+			if c != '-' {
+				return false
+			}
+		case !strings.ContainsRune("0123456789abcdefABCDEF", c):
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,43 @@
+package resource
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/abdo-farag/otc-cli/internal/config"
+	"github.com/abdo-farag/otc-cli/internal/otc"
+)
+
+func TestValidateFlavor(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/p1/cloudservers/flavors" {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.URL.Query().Get("availability_zone") {
+		case "eu-de-01":
+			io.WriteString(w, `{"flavors":[{"id":"s3.medium.1"},{"id":"s3.large.2"}]}`)
+		case "eu-de-02":
+			io.WriteString(w, `{"flavors":[{"id":"s3.large.2"}]}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			io.WriteString(w, `{"error":{"code":"Ecs.0005","message":"invalid availability zone"}}`)
+		}
+	}))
+	defer srv.Close()
+
+	client := otc.NewClient(&config.Config{Endpoints: map[string]string{"ecs": srv.URL}})
+
+	if err := validateFlavor(client, "p1", "token", "eu-de-01", "s3.medium.1"); err != nil {
+		t.Errorf("s3.medium.1 in eu-de-01: %v", err)
+	}
+	if err := validateFlavor(client, "p1", "token", "eu-de-02", "s3.medium.1"); err == nil || !strings.Contains(err.Error(), "not available in eu-de-02") {
+		t.Errorf("s3.medium.1 in eu-de-02: got %v, want not available", err)
+	}
+	if err := validateFlavor(client, "p1", "token", "eu-de-99", "s3.medium.1"); err == nil || !strings.Contains(err.Error(), "invalid availability zone") {
+		t.Errorf("unknown AZ: got %v, want the API error", err)
+	}
+}
@@ -0,0 +1,279 @@
+package resource
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abdo-farag/otc-cli/internal/config"
+	"github.com/abdo-farag/otc-cli/internal/otc"
+	"github.com/abdo-farag/otc-cli/internal/output"
+)
+
+// EIP is an Elastic IP, bound to a port when Status is ACTIVE
+type EIP struct {
+	ID                 string `json:"id"`
+	Name               string `json:"alias,omitempty"`
+	Status             string `json:"status"`
+	Type               string `json:"type"`
+	PublicIPAddress    string `json:"public_ip_address"`
+	PrivateIPAddress   string `json:"private_ip_address,omitempty"`
+	PortID             string `json:"port_id,omitempty"`
+	BandwidthID        string `json:"bandwidth_id"`
+	BandwidthName      string `json:"bandwidth_name,omitempty"`
+	BandwidthSize      int    `json:"bandwidth_size"`
+	BandwidthShareType string `json:"bandwidth_share_type"`
+	IPVersion          int    `json:"ip_version,omitempty"`
+	CreateTime         string `json:"create_time,omitempty"`
+}
+
+func (EIP) Columns(wide bool) []string {
+	columns := []string{"Address", "ID", "Status", "Private IP", "Port", "Bandwidth"}
+	if wide {
+		columns = append(columns, "Name", "Type", "Sharing", "Created")
+	}
+	return columns
+}
+
+func (e EIP) Cells(wide bool) []string {
+	cells := []string{
+		e.PublicIPAddress,
+		e.ID,
+		e.Status,
+		output.OrDash(e.PrivateIPAddress),
+		output.OrDash(e.PortID),
+		strconv.Itoa(e.BandwidthSize) + " Mbit/s",
+	}
+	if wide {
+		cells = append(cells, output.OrDash(e.Name), e.Type, e.BandwidthShareType, output.OrDash(e.CreateTime))
+	}
+	return cells
+}
+
+// ListEIPs lists all EIPs
+func ListEIPs(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, options map[string]interface{}, quiet bool) (*output.List[EIP], error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, quiet)
+	if err != nil {
+		return nil, err
+	}
+
+	eips, streamed, err := listAll[EIP](client, eipsURL(client, projectID), projectToken, otc.ListOptions{ItemsKey: "publicips"}, options, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	list := output.NewList(eips, "EIPs")
+	list.Streamed = streamed
+	list.Title = "Project: " + projectID
+	return list, nil
+}
+
+// GetEIP gets an EIP by ID, address or name
+func GetEIP(cfg *config.Config, client *otc.Client, unscopedToken, projectID, eip string, quiet bool) (*EIP, error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, quiet)
+	if err != nil {
+		return nil, err
+	}
+
+	return resolveEIP(client, projectID, projectToken, eip)
+}
+
+// EIPSpec describes an EIP to allocate
+type EIPSpec struct {
+	Name      string
+	Type      string // e.g. 5_bgp
+	Bandwidth int    // Mbit/s
+}
+
+// AllocateEIP allocates a new EIP with a dedicated bandwidth
+func AllocateEIP(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, spec EIPSpec) (*EIP, error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	publicIP := map[string]interface{}{"type": spec.Type}
+	if spec.Name != "" {
+		publicIP["alias"] = spec.Name
+	}
+	// A dedicated (PER) bandwidth must be named
+	bandwidthName := spec.Name
+	if bandwidthName == "" {
+		bandwidthName = "bandwidth-" + time.Now().UTC().Format("20060102-150405")
+	}
+	bandwidth := map[string]interface{}{
+		"name":        bandwidthName,
+		"size":        spec.Bandwidth,
+		"share_type":  "PER", // dedicated
+		"charge_mode": "traffic",
+	}
+
+	body, err := client.Do("POST", eipsURL(client, projectID), projectToken, map[string]interface{}{
+		"publicip":  publicIP,
+		"bandwidth": bandwidth,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		EIP EIP `json:"publicip"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &result.EIP, nil
+}
+
+// AssociateEIP binds an EIP to a port. target is a server name or ID, whose
+// only NIC is used, or a port ID.
+func AssociateEIP(cfg *config.Config, client *otc.Client, unscopedToken, projectID, eip, target string) (*EIP, error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	address, err := resolveEIP(client, projectID, projectToken, eip)
+	if err != nil {
+		return nil, err
+	}
+	if address.PortID != "" {
+		return nil, fmt.Errorf("EIP %s is already associated with port %s, disassociate it first", address.PublicIPAddress, address.PortID)
+	}
+
+	portID, err := resolvePort(cfg, client, unscopedToken, projectID, target)
+	if err != nil {
+		return nil, err
+	}
+
+	return updateEIPPort(client, projectID, projectToken, address.ID, map[string]interface{}{"port_id": portID})
+}
+
+// DisassociateEIP unbinds an EIP from its port
+func DisassociateEIP(cfg *config.Config, client *otc.Client, unscopedToken, projectID, eip string) (*EIP, error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	address, err := resolveEIP(client, projectID, projectToken, eip)
+	if err != nil {
+		return nil, err
+	}
+	if address.PortID == "" {
+		return address, nil
+	}
+
+	// An update without port_id unbinds the EIP
+	return updateEIPPort(client, projectID, projectToken, address.ID, map[string]interface{}{})
+}
+
+// ReleaseEIP deletes an EIP and its dedicated bandwidth
+func ReleaseEIP(cfg *config.Config, client *otc.Client, unscopedToken, projectID, eipID string) error {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, true)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Do("DELETE", eipsURL(client, projectID)+"/"+eipID, projectToken, nil)
+	return err
+}
+
+// eipsByPort maps the port IDs of bound EIPs to their public addresses
+func eipsByPort(client *otc.Client, projectID, projectToken string) (map[string]string, error) {
+	eips, _, err := listAll[EIP](client, eipsURL(client, projectID), projectToken, otc.ListOptions{ItemsKey: "publicips"}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	byPort := map[string]string{}
+	for _, eip := range eips {
+		if eip.PortID != "" {
+			byPort[eip.PortID] = eip.PublicIPAddress
+		}
+	}
+	return byPort, nil
+}
+
+func updateEIPPort(client *otc.Client, projectID, projectToken, eipID string, publicIP map[string]interface{}) (*EIP, error) {
+	body, err := client.Do("PUT", eipsURL(client, projectID)+"/"+eipID, projectToken, map[string]interface{}{"publicip": publicIP})
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		EIP EIP `json:"publicip"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &result.EIP, nil
+}
+
+// resolveEIP finds an EIP by ID, public address or name
+func resolveEIP(client *otc.Client, projectID, projectToken, eip string) (*EIP, error) {
+	eips, _, err := listAll[EIP](client, eipsURL(client, projectID), projectToken, otc.ListOptions{ItemsKey: "publicips"}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []EIP
+	for _, e := range eips {
+		if e.ID == eip || e.PublicIPAddress == eip {
+			return &e, nil
+		}
+		if e.Name == eip {
+			matches = append(matches, e)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("EIP %s %w", eip, otc.ErrNotFound)
+	case 1:
+		return &matches[0], nil
+	}
+	return nil, fmt.Errorf("EIP name %s matches %d EIPs, use the address or ID instead", eip, len(matches))
+}
+
+// resolvePort returns the port of a server's only NIC, or target itself if
+// it is no server but looks like a port ID
+func resolvePort(cfg *config.Config, client *otc.Client, unscopedToken, projectID, target string) (string, error) {
+	servers, err := ResolveServers(cfg, client, unscopedToken, projectID, []string{target}, "", true)
+	if err != nil {
+		if errors.Is(err, otc.ErrNotFound) && IsUUID(target) {
+			return target, nil
+		}
+		return "", err
+	}
+	server := servers[0]
+
+	var ports, described []string
+	seen := map[string]bool{}
+	for _, addrs := range server.Addresses {
+		for _, addr := range addrs {
+			if addr.Type == "fixed" && addr.PortID != "" && !seen[addr.PortID] {
+				seen[addr.PortID] = true
+				ports = append(ports, addr.PortID)
+				described = append(described, fmt.Sprintf("%s (%s)", addr.PortID, addr.Addr))
+			}
+		}
+	}
+
+	switch len(ports) {
+	case 0:
+		return "", fmt.Errorf("server %s has no network port", server.Name)
+	case 1:
+		return ports[0], nil
+	}
+	sort.Strings(described)
+	return "", fmt.Errorf("server %s has %d ports, pass one of them instead: %s", server.Name, len(ports), strings.Join(described, ", "))
+}
+
+func eipsURL(client *otc.Client, projectID string) string {
+	return fmt.Sprintf("%s/v1/%s/publicips", client.Endpoint("vpc", projectID), projectID)
+}
@@ -0,0 +1,95 @@
+package resource
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/abdo-farag/otc-cli/internal/config"
+	"github.com/abdo-farag/otc-cli/internal/otc"
+	"github.com/abdo-farag/otc-cli/internal/output"
+)
+
+func TestAllocateEIPRequest(t *testing.T) {
+	tests := []struct {
+		name          string
+		spec          EIPSpec
+		wantAlias     string
+		wantBandwidth string // prefix of the bandwidth name
+	}{
+		{"named", EIPSpec{Name: "bastion", Type: "5_bgp", Bandwidth: 10}, "bastion", "bastion"},
+		{"unnamed", EIPSpec{Type: "5_bgp", Bandwidth: 10}, "", "bandwidth-"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body struct {
+				PublicIP  map[string]interface{} `json:"publicip"`
+				Bandwidth map[string]interface{} `json:"bandwidth"`
+			}
+			srv := newIAMStub(t, func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == "POST" && r.URL.Path == "/v1/p1/publicips":
+					json.NewDecoder(r.Body).Decode(&body)
+					io.WriteString(w, `{"publicip":{"id":"e1","public_ip_address":"80.158.0.1","status":"PENDING_CREATE"}}`)
+				default:
+					http.NotFound(w, r)
+				}
+			})
+
+			cfg := &config.Config{AUTHURL: srv.URL, Endpoints: map[string]string{"vpc": srv.URL}}
+			eip, err := AllocateEIP(cfg, otc.NewClient(cfg), "token", "p1", tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if eip.ID != "e1" {
+				t.Errorf("got EIP %+v, want e1", eip)
+			}
+
+			if body.PublicIP["type"] != "5_bgp" {
+				t.Errorf("publicip.type = %v, want 5_bgp", body.PublicIP["type"])
+			}
+			if alias, _ := body.PublicIP["alias"].(string); alias != tt.wantAlias {
+				t.Errorf("publicip.alias = %q, want %q", alias, tt.wantAlias)
+			}
+			if name, _ := body.Bandwidth["name"].(string); !strings.HasPrefix(name, tt.wantBandwidth) {
+				t.Errorf("bandwidth.name = %q, want it to start with %q", name, tt.wantBandwidth)
+			}
+			if body.Bandwidth["size"] != 10.0 || body.Bandwidth["share_type"] != "PER" || body.Bandwidth["charge_mode"] != "traffic" {
+				t.Errorf("bandwidth = %v, want 10 Mbit/s dedicated, charged by traffic", body.Bandwidth)
+			}
+		})
+	}
+}
+
+func TestListECSStreamedPagesShowEIPs(t *testing.T) {
+	srv := newIAMStub(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/p1/publicips":
+			io.WriteString(w, `{"publicips":[{"id":"e1","public_ip_address":"80.158.0.1","port_id":"port-1"}]}`)
+		case "/v1/p1/cloudservers/detail":
+			io.WriteString(w, `{"servers":[{"id":"s1","name":"web","addresses":{"net":[{"addr":"10.0.0.5","version":"4","OS-EXT-IPS:type":"fixed","OS-EXT-IPS:port_id":"port-1"}]}}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	var rows [][]string
+	options := map[string]interface{}{
+		"wide": true,
+		"onPage": func(page output.Tabular) error {
+			rows = append(rows, page.Rows(true)...)
+			return nil
+		},
+	}
+
+	cfg := &config.Config{AUTHURL: srv.URL, Endpoints: map[string]string{"ecs": srv.URL, "vpc": srv.URL}}
+	if _, err := ListECS(cfg, otc.NewClient(cfg), "token", "p1", options, true); err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || rows[0][3] != "80.158.0.1" {
+		t.Errorf("streamed rows = %v, want the EIP of port-1 in the EIP column", rows)
+	}
+}
@@ -2,18 +2,14 @@ package resource
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"github.com/abdo-farag/otc-cli/internal/config"
 	"github.com/abdo-farag/otc-cli/internal/otc"
+	"github.com/abdo-farag/otc-cli/internal/output"
 	"sort"
 	"strconv"
 	"strings"
-	"time"
-
-	"github.com/fatih/color"
-	"github.com/rodaine/table"
 )
 
 // parsePrice extracts numeric value from price string
@@ -46,36 +42,61 @@ func extractRAMSize(ramStr string) float64 {
 
 // PricingInfo holds pricing data from OTC Price API
 type PricingInfo struct {
-	OpiFlavour         string
-	vCPU               string
-	RAM                string
-	HourlyCost         float64
-	MonthlyCost        float64
-	OSUnit             string
-	ProductIdParameter string
+	OpiFlavour         string  `json:"flavor"`
+	VCPU               string  `json:"vcpus"`
+	RAM                string  `json:"ram"`
+	HourlyCost         float64 `json:"hourly_cost"`
+	MonthlyCost        float64 `json:"monthly_cost"`
+	OSUnit             string  `json:"os"`
+	ProductIdParameter string  `json:"type"`
+}
+
+func (PricingInfo) Columns(wide bool) []string {
+	columns := []string{"Flavor ID", "Type", "vCPUs", "RAM", "Cost/Hour", "Cost/Month"}
+	if wide {
+		columns = append(columns, "OS")
+	}
+	return columns
 }
 
-// FetchFlavorPricing fetches pricing from OTC price API with real specs
-func FetchFlavorPricing(region string, osType string) (map[string]PricingInfo, error) {
-	pricing := make(map[string]PricingInfo)
+func (p PricingInfo) Cells(wide bool) []string {
+	// Format price, show N/A if zero
+	priceStr := fmt.Sprintf("€%.4f", p.HourlyCost)
+	if p.HourlyCost == 0 {
+		priceStr = "N/A"
+	}
+	monthlyStr := fmt.Sprintf("€%.2f", p.MonthlyCost)
+	if p.MonthlyCost == 0 {
+		monthlyStr = "N/A"
+	}
+
+	cells := []string{p.OpiFlavour, p.ProductIdParameter, p.VCPU, p.RAM, priceStr, monthlyStr}
+	if wide {
+		cells = append(cells, p.OSUnit)
+	}
+	return cells
+}
+
+// priceAPI is the public OTC price API
+var priceAPI = "https://calculator.otc-service.com/en/open-telekom-price-api/"
+
+// FetchFlavorPricing fetches pricing from OTC price API with real specs.
+// Service types whose prices can't be read are skipped and returned in
+// failed; it only fails if none can be read.
+func FetchFlavorPricing(client *otc.Client, region string, osType string) (pricing map[string]PricingInfo, failed []string, err error) {
+	pricing = make(map[string]PricingInfo)
 
 	// Query all service types: ecs, ecsnoc, gpu, deh
 	serviceNames := []string{"ecs", "ecsnoc", "memo", "uhio", "hps", "gpu", "deh", "dehl"}
 
 	for _, serviceName := range serviceNames {
-		pricingURL := fmt.Sprintf("https://calculator.otc-service.com/en/open-telekom-price-api/?serviceName=%s&region=%s&limitMax=1000", serviceName, region)
-
-		req, _ := http.NewRequest("GET", pricingURL, nil)
-		req.Header.Set("Content-Type", "application/json")
+		pricingURL := fmt.Sprintf("%s?serviceName=%s&region=%s&limitMax=1000", priceAPI, serviceName, region)
 
-		httpClient := &http.Client{Timeout: 15 * time.Second}
-		resp, err := httpClient.Do(req)
+		body, err := fetchPrices(client, pricingURL)
 		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%v)", serviceName, err))
 			continue
 		}
-		defer resp.Body.Close()
-
-		body, _ := io.ReadAll(resp.Body)
 
 		// Generic response structure that works for all service types
 		var apiResponse struct {
@@ -94,6 +115,7 @@ func FetchFlavorPricing(region string, osType string) (map[string]PricingInfo, e
 		}
 
 		if err := json.Unmarshal(body, &apiResponse); err != nil {
+			failed = append(failed, fmt.Sprintf("%s (invalid response)", serviceName))
 			continue
 		}
 
@@ -143,7 +165,7 @@ func FetchFlavorPricing(region string, osType string) (map[string]PricingInfo, e
 
 					pricing[item.OpiFlavour] = PricingInfo{
 						OpiFlavour:         item.OpiFlavour,
-						vCPU:               item.VCPU,
+						VCPU:               item.VCPU,
 						RAM:                item.RAM,
 						HourlyCost:         hourly,
 						MonthlyCost:        monthly,
@@ -156,44 +178,54 @@ func FetchFlavorPricing(region string, osType string) (map[string]PricingInfo, e
 		}
 	}
 
-	return pricing, nil
+	if len(failed) == len(serviceNames) {
+		return nil, failed, fmt.Errorf("price API unavailable: %s", strings.Join(failed, ", "))
+	}
+	return pricing, failed, nil
+}
+
+// fetchPrices reads one page of the price API. The API is public, so no token is sent.
+func fetchPrices(client *otc.Client, pricingURL string) ([]byte, error) {
+	body, err := client.Do("GET", pricingURL, "", nil)
+
+	// Failures end up in a note listing every failed service type, keep them short
+	var apiErr *otc.APIError
+	if errors.As(err, &apiErr) {
+		return nil, fmt.Errorf("HTTP %d", apiErr.StatusCode)
+	}
+	return body, err
 }
 
-// ListPricing lists pricing for all servers from OTC API - sorted
-func ListFlavors(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, raw bool, osType string) {
+// ListFlavors lists pricing for all server flavors from the OTC price API, sorted
+func ListFlavors(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, osType string) (*output.List[PricingInfo], error) {
 	// Default to OpenLinux if not specified
 	if osType == "" {
 		osType = "openlinux"
 	}
 
 	// Fetch pricing data
-	pricing, err := FetchFlavorPricing(cfg.Region, osType)
+	pricing, failed, err := FetchFlavorPricing(client, cfg.Region, osType)
 	if err != nil {
-		color.Red("✗ Failed to fetch pricing: %v", err)
-		return
+		return nil, fmt.Errorf("failed to fetch pricing: %w", err)
 	}
 
 	if len(pricing) == 0 {
-		color.Red("✗ No pricing data found for region: %s with OS: %s", cfg.Region, osType)
-		return
-	}
-
-	if raw {
-		formatted, _ := json.MarshalIndent(pricing, "", "  ")
-		fmt.Println(string(formatted))
-		return
+		return nil, fmt.Errorf("no pricing data found for region: %s with OS: %s", cfg.Region, osType)
 	}
 
-	// Convert map to slice for sorting
+	// Convert map to slice for sorting, skipping zero CPU/RAM entries
 	var pricingList []PricingInfo
 	for _, p := range pricing {
+		if extractVCPUCount(p.VCPU) == 0 || extractRAMSize(p.RAM) == 0 {
+			continue
+		}
 		pricingList = append(pricingList, p)
 	}
 
 	// Sort by vCPU count, then by RAM size, then by hourly cost
 	sort.Slice(pricingList, func(i, j int) bool {
-		vCPUI := extractVCPUCount(pricingList[i].vCPU)
-		vCPUJ := extractVCPUCount(pricingList[j].vCPU)
+		vCPUI := extractVCPUCount(pricingList[i].VCPU)
+		vCPUJ := extractVCPUCount(pricingList[j].VCPU)
 
 		if vCPUI != vCPUJ {
 			return vCPUI < vCPUJ
@@ -209,44 +241,14 @@ func ListFlavors(cfg *config.Config, client *otc.Client, unscopedToken, projectI
 		return pricingList[i].HourlyCost < pricingList[j].HourlyCost
 	})
 
-	// Create table with header formatter
-	headerFmt := color.New(color.FgCyan, color.Bold).SprintfFunc()
-	tbl := table.New("Flavor ID", "Type", "vCPUs", "RAM", "Cost/Hour", "Cost/Month")
-	tbl.WithHeaderFormatter(headerFmt)
-
-	// Add rows
-	for _, p := range pricingList {
-		// Skip zero CPU/RAM entries
-		vCPUCount := extractVCPUCount(p.vCPU)
-		ramSize := extractRAMSize(p.RAM)
-		if vCPUCount == 0 || ramSize == 0 {
-			continue
-		}
-
-		// Format price, show N/A if zero
-		priceStr := fmt.Sprintf("€%.4f", p.HourlyCost)
-		if p.HourlyCost == 0 {
-			priceStr = "N/A"
-		}
-		monthlyStr := fmt.Sprintf("€%.2f", p.MonthlyCost)
-		if p.MonthlyCost == 0 {
-			monthlyStr = "N/A"
-		}
-
-		tbl.AddRow(
-			p.OpiFlavour,
-			p.ProductIdParameter,
-			p.vCPU,
-			p.RAM,
-			priceStr,
-			monthlyStr,
-		)
+	list := output.NewList(pricingList, "servers")
+	list.Title = fmt.Sprintf("Server Pricing (Region: %s, OS: %s)", cfg.Region, osType)
+	list.Footer = []string{
+		"Sorted by vCPUs → RAM → Cost",
+		"Pricing based on hourly rates (730 hours/month)",
 	}
-
-	// Print table
-	fmt.Printf("\n")
-	color.Cyan("Server Pricing (Region: %s, OS: %s)", cfg.Region, osType)
-	tbl.Print()
-	fmt.Printf("Total: %d servers | Sorted by vCPUs → RAM → Cost\n", len(pricingList))
-	fmt.Printf("Pricing based on hourly rates (730 hours/month)\n\n")
+	if len(failed) > 0 {
+		list.Notes = append(list.Notes, "Prices missing for: "+strings.Join(failed, ", "))
+	}
+	return list, nil
 }
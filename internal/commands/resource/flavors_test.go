@@ -0,0 +1,61 @@
+package resource
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/abdo-farag/otc-cli/internal/config"
+	"github.com/abdo-farag/otc-cli/internal/otc"
+)
+
+// priceServer serves one flavor per service type, failing for those in broken
+func priceServer(t *testing.T, broken ...string) {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		service := r.URL.Query().Get("serviceName")
+		for _, b := range broken {
+			if b == service {
+				http.Error(w, "no such service", http.StatusNotFound)
+				return
+			}
+		}
+		fmt.Fprintf(w, `{"response":{"result":{"%[1]s":[{"opiFlavour":"%[1]s.large.2","vCpu":"2","ram":"4 GiB","priceAmount":"0.1 EUR","osUnit":"Open Linux","region":"%[2]s"}]}}}`,
+			service, r.URL.Query().Get("region"))
+	}))
+	t.Cleanup(srv.Close)
+
+	old := priceAPI
+	priceAPI = srv.URL + "/"
+	t.Cleanup(func() { priceAPI = old })
+}
+
+func TestListFlavorsSkipsFailedServiceTypes(t *testing.T) {
+	priceServer(t, "dehl", "gpu")
+
+	cfg := &config.Config{Region: "eu-de"}
+	list, err := ListFlavors(cfg, otc.NewClient(cfg), "token", "p1", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(list.Items) != 6 {
+		t.Errorf("got %d flavors, want one per working service type (6)", len(list.Items))
+	}
+	notes := strings.Join(list.Notes, "\n")
+	if !strings.Contains(notes, "dehl (HTTP 404)") || !strings.Contains(notes, "gpu (HTTP 404)") {
+		t.Errorf("notes don't name the failed service types: %q", notes)
+	}
+}
+
+func TestListFlavorsFailsWithoutPrices(t *testing.T) {
+	priceServer(t, "ecs", "ecsnoc", "memo", "uhio", "hps", "gpu", "deh", "dehl")
+
+	cfg := &config.Config{Region: "eu-de"}
+	if _, err := ListFlavors(cfg, otc.NewClient(cfg), "token", "p1", ""); err == nil || !strings.Contains(err.Error(), "price API unavailable") {
+		t.Errorf("got error %v, want price API unavailable", err)
+	}
+}
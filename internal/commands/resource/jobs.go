@@ -0,0 +1,27 @@
+package resource
+
+import (
+	"github.com/abdo-farag/otc-cli/internal/config"
+	"github.com/abdo-farag/otc-cli/internal/otc"
+	"github.com/abdo-farag/otc-cli/internal/otc/jobs"
+)
+
+// GetJob gets an asynchronous job of an ECS, EVS or CCE operation
+func GetJob(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, service jobs.Service, jobID string, quiet bool) (*jobs.Job, error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, quiet)
+	if err != nil {
+		return nil, err
+	}
+
+	return jobs.Get(client, service, projectID, projectToken, jobID)
+}
+
+// WaitJob polls an asynchronous job until it finishes
+func WaitJob(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, service jobs.Service, jobID string, opts jobs.WaitOptions) (*jobs.Job, error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return jobs.Wait(client, service, projectID, projectToken, jobID, opts)
+}
@@ -1,49 +1,100 @@
 package resource
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
 	"github.com/abdo-farag/otc-cli/internal/config"
 	"github.com/abdo-farag/otc-cli/internal/otc"
-	"time"
 
 	"github.com/fatih/color"
+	"gopkg.in/yaml.v2"
 )
 
-func GetKubeconfig(cfg *config.Config, client *otc.Client, unscopedToken, projectID, clusterNameOrID, outputPath string) {
-	// Get project token
-	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, false)
-	if err != nil {
-		color.Red("✗ %v", err)
-		return
-	}
+// Kubeconfig is the subset of a kubeconfig file otc-cli reads and writes.
+// CCE returns it as JSON, which the YAML decoder also accepts.
+type Kubeconfig struct {
+	APIVersion     string         `yaml:"apiVersion"`
+	Kind           string         `yaml:"kind"`
+	Clusters       []NamedCluster `yaml:"clusters"`
+	Users          []NamedUser    `yaml:"users"`
+	Contexts       []NamedContext `yaml:"contexts"`
+	CurrentContext string         `yaml:"current-context"`
+}
 
-	color.Yellow("⏳ Finding cluster...")
+type NamedCluster struct {
+	Name    string      `yaml:"name"`
+	Cluster ClusterInfo `yaml:"cluster"`
+}
 
-	// First, list clusters to find by name if needed
-	cceURL := fmt.Sprintf("https://cce.%s.otc.t-systems.com/api/v3/projects/%s/clusters", cfg.Region, projectID)
+type ClusterInfo struct {
+	Server                   string `yaml:"server"`
+	CertificateAuthorityData string `yaml:"certificate-authority-data,omitempty"`
+	InsecureSkipTLSVerify    bool   `yaml:"insecure-skip-tls-verify,omitempty"`
+}
 
-	req, _ := http.NewRequest("GET", cceURL, nil)
-	req.Header.Set("X-Auth-Token", projectToken)
-	req.Header.Set("Content-Type", "application/json")
+type NamedUser struct {
+	Name string   `yaml:"name"`
+	User AuthInfo `yaml:"user"`
+}
 
-	httpClient := &http.Client{Timeout: 30 * time.Second}
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		color.Red("✗ Request failed: %v", err)
-		return
-	}
-	defer resp.Body.Close()
+type AuthInfo struct {
+	ClientCertificateData string      `yaml:"client-certificate-data,omitempty"`
+	ClientKeyData         string      `yaml:"client-key-data,omitempty"`
+	Exec                  *ExecConfig `yaml:"exec,omitempty"`
+}
 
-	body, _ := io.ReadAll(resp.Body)
+// ExecConfig runs a credential plugin (client.authentication.k8s.io)
+type ExecConfig struct {
+	APIVersion      string   `yaml:"apiVersion"`
+	Command         string   `yaml:"command"`
+	Args            []string `yaml:"args,omitempty"`
+	InteractiveMode string   `yaml:"interactiveMode,omitempty"`
+}
 
-	if resp.StatusCode != 200 {
-		color.Red("✗ API error (status %d): %s", resp.StatusCode, string(body))
-		return
-	}
+type NamedContext struct {
+	Name    string      `yaml:"name"`
+	Context ContextInfo `yaml:"context"`
+}
+
+type ContextInfo struct {
+	Cluster   string `yaml:"cluster"`
+	User      string `yaml:"user"`
+	Namespace string `yaml:"namespace,omitempty"`
+}
+
+// KubeconfigOptions controls how GetKubeconfig writes the kubeconfig
+type KubeconfigOptions struct {
+	OutputPath  string // File or directory to write; with Merge, the kubeconfig to merge into
+	Exec        bool   // Authenticate with "otc-cli cce token" instead of embedded certs
+	Profile     string // Profile passed to the exec plugin
+	Merge       bool   // Merge into an existing kubeconfig instead of writing a new file
+	ContextName string // Defaults to otc-<project>-<cluster>
+	SetCurrent  bool   // Make the merged context the current context
+	Endpoint    string // "internal" or "external"; defaults to the one CCE marks current
+
+	// ClusterCA returns the CA of the cluster for Exec kubeconfigs, which
+	// take the server from the cluster instead of a downloaded kubeconfig
+	ClusterCA func(projectID, clusterID string) (string, error)
+}
+
+// ClusterCert is a short-lived client certificate issued by CCE
+type ClusterCert struct {
+	Server                   string
+	CertificateAuthorityData string
+	ClientCertificateData    string // base64-encoded PEM, as in kubeconfig
+	ClientKeyData            string // base64-encoded PEM, as in kubeconfig
+	ExpiresAt                time.Time
+}
+
+// resolveCluster finds a cluster by name or ID and returns its ID and name
+func resolveCluster(client *otc.Client, projectID, projectToken, clusterNameOrID string) (string, string, error) {
+	cceURL := fmt.Sprintf("%s/api/v3/projects/%s/clusters", client.Endpoint("cce", projectID), projectID)
 
 	var clusterList struct {
 		Clusters []struct {
@@ -54,63 +105,374 @@ func GetKubeconfig(cfg *config.Config, client *otc.Client, unscopedToken, projec
 		} `json:"items"`
 	}
 
-	json.Unmarshal(body, &clusterList)
+	if err := client.Get(cceURL, projectToken, &clusterList); err != nil {
+		return "", "", err
+	}
 
-	// Find cluster by name or ID
-	var clusterID string
 	for _, c := range clusterList.Clusters {
 		if c.Metadata.UID == clusterNameOrID || c.Metadata.Name == clusterNameOrID {
-			clusterID = c.Metadata.UID
-			color.Cyan("✓ Found cluster: %s (%s)", c.Metadata.Name, clusterID)
-			break
+			return c.Metadata.UID, c.Metadata.Name, nil
+		}
+	}
+
+	return "", "", fmt.Errorf("cluster %s %w", clusterNameOrID, otc.ErrNotFound)
+}
+
+// kubeconfigDocument unwraps a {"kubeconfig": "..."} response, or returns
+// body if it is a plain kubeconfig document
+func kubeconfigDocument(body []byte) []byte {
+	var wrapper struct {
+		Kubeconfig string `json:"kubeconfig"`
+	}
+	if err := json.Unmarshal(body, &wrapper); err == nil && wrapper.Kubeconfig != "" {
+		return []byte(wrapper.Kubeconfig)
+	}
+	return body
+}
+
+// parseKubeconfig accepts both a plain kubeconfig document and the
+// {"kubeconfig": "..."} wrapper
+func parseKubeconfig(body []byte) (*Kubeconfig, error) {
+	body = kubeconfigDocument(body)
+
+	var kubeconfig Kubeconfig
+	if err := yaml.Unmarshal(body, &kubeconfig); err != nil {
+		return nil, err
+	}
+
+	if len(kubeconfig.Clusters) == 0 {
+		return nil, fmt.Errorf("no clusters in kubeconfig")
+	}
+
+	return &kubeconfig, nil
+}
+
+// cluster returns the named cluster entry, or the one of the current context
+func (k *Kubeconfig) cluster(name string) *NamedCluster {
+	if name == "" {
+		for _, ctx := range k.Contexts {
+			if ctx.Name == k.CurrentContext {
+				name = ctx.Context.Cluster
+			}
+		}
+	}
+	for i := range k.Clusters {
+		if k.Clusters[i].Name == name {
+			return &k.Clusters[i]
+		}
+	}
+	return &k.Clusters[0]
+}
+
+// endpoint returns the cluster entry and user for the "internal" or "external"
+// endpoint, or those of the current context when endpoint is empty
+func (k *Kubeconfig) endpoint(endpoint string) (*NamedCluster, *NamedUser, error) {
+	var user *NamedUser
+	if len(k.Users) > 0 {
+		user = &k.Users[0]
+	}
+
+	contextName := k.CurrentContext
+	if endpoint != "" {
+		contextName = endpoint
+	}
+
+	for _, ctx := range k.Contexts {
+		if ctx.Name != contextName {
+			continue
+		}
+		for i := range k.Users {
+			if k.Users[i].Name == ctx.Context.User {
+				user = &k.Users[i]
+			}
+		}
+		return k.cluster(ctx.Context.Cluster), user, nil
+	}
+
+	if endpoint == "" {
+		return k.cluster(""), user, nil
+	}
+
+	// CCE names the clusters internalCluster and externalCluster
+	for i := range k.Clusters {
+		if strings.HasPrefix(strings.ToLower(k.Clusters[i].Name), endpoint) {
+			return &k.Clusters[i], user, nil
+		}
+	}
+
+	if endpoint == "external" {
+		return nil, nil, fmt.Errorf("cluster has no external endpoint (bind an EIP to the cluster first)")
+	}
+	return nil, nil, fmt.Errorf("cluster has no %s endpoint", endpoint)
+}
+
+// clusterEndpoint returns the URL of the cluster's "internal" or "external"
+// API endpoint, the internal one by default
+func clusterEndpoint(client *otc.Client, projectID, projectToken, clusterID, endpoint string) (string, error) {
+	cceURL := fmt.Sprintf("%s/api/v3/projects/%s/clusters/%s", client.Endpoint("cce", projectID), projectID, clusterID)
+
+	var cluster Cluster
+	if err := client.Get(cceURL, projectToken, &cluster); err != nil {
+		return "", err
+	}
+
+	if endpoint == "" {
+		endpoint = "internal"
+	}
+	for _, e := range cluster.Status.Endpoints {
+		if strings.EqualFold(e.Type, endpoint) {
+			return e.URL, nil
+		}
+	}
+
+	if endpoint == "external" {
+		return "", fmt.Errorf("cluster has no external endpoint (bind an EIP to the cluster first)")
+	}
+	return "", fmt.Errorf("cluster has no %s endpoint", endpoint)
+}
+
+// requestClusterCert asks CCE for a cluster certificate valid for durationDays
+func requestClusterCert(client *otc.Client, projectID, projectToken, clusterID string, durationDays int) (*Kubeconfig, error) {
+	certURL := fmt.Sprintf("%s/api/v3/projects/%s/clusters/%s/clustercert", client.Endpoint("cce", projectID), projectID, clusterID)
+
+	body, err := client.Do("POST", certURL, projectToken, map[string]int{"duration": durationDays})
+	if err != nil {
+		return nil, err
+	}
+
+	kubeconfig, err := parseKubeconfig(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cluster certificate: %w", err)
+	}
+
+	return kubeconfig, nil
+}
+
+// GetClusterCert issues a short-lived client certificate for a cluster
+func GetClusterCert(cfg *config.Config, client *otc.Client, unscopedToken, projectID, clusterNameOrID string, durationDays int) (*ClusterCert, error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterID, _, err := resolveCluster(client, projectID, projectToken, clusterNameOrID)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeconfig, err := requestClusterCert(client, projectID, projectToken, clusterID, durationDays)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(kubeconfig.Users) == 0 {
+		return nil, fmt.Errorf("no user credentials in cluster certificate")
+	}
+
+	cluster := kubeconfig.cluster("")
+	return &ClusterCert{
+		Server:                   cluster.Cluster.Server,
+		CertificateAuthorityData: cluster.Cluster.CertificateAuthorityData,
+		ClientCertificateData:    kubeconfig.Users[0].User.ClientCertificateData,
+		ClientKeyData:            kubeconfig.Users[0].User.ClientKeyData,
+		ExpiresAt:                time.Now().Add(time.Duration(durationDays) * 24 * time.Hour),
+	}, nil
+}
+
+// PEM returns the decoded client certificate and key
+func (c *ClusterCert) PEM() (string, string, error) {
+	cert, err := base64.StdEncoding.DecodeString(c.ClientCertificateData)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid client certificate: %w", err)
+	}
+	key, err := base64.StdEncoding.DecodeString(c.ClientKeyData)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid client key: %w", err)
+	}
+	return string(cert), string(key), nil
+}
+
+// execAuthInfo returns a user that runs "otc-cli cce token" instead of
+// embedding long-lived certificates
+func execAuthInfo(clusterID, projectID, profile string) AuthInfo {
+	args := []string{"cce", "token", clusterID, "--project", projectID}
+	if profile != "" && profile != config.DefaultProfileName {
+		args = append(args, "--profile", profile)
+	}
+
+	return AuthInfo{Exec: &ExecConfig{
+		APIVersion:      "client.authentication.k8s.io/v1",
+		Command:         "otc-cli",
+		Args:            args,
+		InteractiveMode: "IfAvailable",
+	}}
+}
+
+// singleContext builds a kubeconfig whose cluster, user and context share one name
+func singleContext(name string, cluster ClusterInfo, user AuthInfo) *Kubeconfig {
+	return &Kubeconfig{
+		APIVersion: "v1",
+		Kind:       "Config",
+		Clusters: []NamedCluster{
+			{Name: name, Cluster: cluster},
+		},
+		Users: []NamedUser{
+			{Name: name, User: user},
+		},
+		Contexts: []NamedContext{
+			{Name: name, Context: ContextInfo{Cluster: name, User: name}},
+		},
+		CurrentContext: name,
+	}
+}
+
+// projectName returns the name of a project, or its ID if it can't be listed
+func projectName(client *otc.Client, unscopedToken, projectID string) string {
+	projects, err := client.GetProjects(unscopedToken)
+	if err != nil {
+		return projectID
+	}
+	for _, p := range projects {
+		if p.ID == projectID {
+			return p.Name
 		}
 	}
+	return projectID
+}
+
+// downloadKubeconfig fetches the kubeconfig with embedded certificates. It
+// also returns the document as CCE sent it, with all of its contexts.
+func downloadKubeconfig(client *otc.Client, projectID, projectToken, clusterID string) (*Kubeconfig, []byte, error) {
+	kubeconfigURL := fmt.Sprintf("%s/api/v3/projects/%s/clusters/%s/clustercert", client.Endpoint("cce", projectID), projectID, clusterID)
 
-	if clusterID == "" {
-		color.Red("✗ Cluster not found: %s", clusterNameOrID)
-		return
+	body, err := client.Do("GET", kubeconfigURL, projectToken, nil)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Get kubeconfig
-	color.Yellow("⏳ Downloading kubeconfig...")
-	kubeconfigURL := fmt.Sprintf("https://cce.%s.otc.t-systems.com/api/v3/projects/%s/clusters/%s/clustercert",
-		cfg.Region, projectID, clusterID)
+	kubeconfig, err := parseKubeconfig(body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse kubeconfig: %w", err)
+	}
 
-	req2, _ := http.NewRequest("GET", kubeconfigURL, nil)
-	req2.Header.Set("X-Auth-Token", projectToken)
-	req2.Header.Set("Content-Type", "application/json")
+	return kubeconfig, kubeconfigDocument(body), nil
+}
 
-	resp2, err := httpClient.Do(req2)
+// GetKubeconfig writes a kubeconfig for a cluster, or merges it into an existing one
+func GetKubeconfig(cfg *config.Config, client *otc.Client, unscopedToken, projectID, clusterNameOrID string, opts KubeconfigOptions) error {
+	// Get project token
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, false)
 	if err != nil {
-		color.Red("✗ Request failed: %v", err)
-		return
+		return err
 	}
-	defer resp2.Body.Close()
 
-	body2, _ := io.ReadAll(resp2.Body)
+	color.Yellow("⏳ Finding cluster...")
 
-	if resp2.StatusCode != 200 {
-		color.Red("✗ API error (status %d): %s", resp2.StatusCode, string(body2))
-		return
+	clusterID, clusterName, err := resolveCluster(client, projectID, projectToken, clusterNameOrID)
+	if err != nil {
+		return err
 	}
+	color.Cyan("✓ Found cluster: %s (%s)", clusterName, clusterID)
 
-	var kubeconfigResp struct {
-		Kubeconfig string `json:"kubeconfig"`
+	// Without options that pick or rename a context, the kubeconfig CCE
+	// returns is written as is, keeping its internal and external contexts
+	verbatim := !opts.Exec && !opts.Merge && opts.Endpoint == "" && opts.ContextName == ""
+
+	var clusterInfo ClusterInfo
+	var authInfo AuthInfo
+	var data []byte
+	if opts.Exec {
+		// kubectl gets its certificates from "otc-cli cce token", so only the
+		// server and CA are needed here
+		color.Yellow("⏳ Fetching cluster endpoint...")
+		clusterInfo.Server, err = clusterEndpoint(client, projectID, projectToken, clusterID, opts.Endpoint)
+		if err != nil {
+			return err
+		}
+		if opts.ClusterCA != nil {
+			clusterInfo.CertificateAuthorityData, err = opts.ClusterCA(projectID, clusterID)
+			if err != nil {
+				return err
+			}
+		}
+		authInfo = execAuthInfo(clusterID, projectID, opts.Profile)
+	} else {
+		color.Yellow("⏳ Downloading kubeconfig...")
+		downloaded, document, err := downloadKubeconfig(client, projectID, projectToken, clusterID)
+		if err != nil {
+			return err
+		}
+		if verbatim {
+			data = document
+		}
+
+		cluster, user, err := downloaded.endpoint(opts.Endpoint)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return fmt.Errorf("no user credentials in kubeconfig")
+		}
+		clusterInfo, authInfo = cluster.Cluster, user.User
 	}
 
-	if err := json.Unmarshal(body2, &kubeconfigResp); err != nil {
-		color.Red("✗ Failed to parse kubeconfig: %v", err)
-		return
+	contextName := opts.ContextName
+	if contextName == "" {
+		contextName = fmt.Sprintf("otc-%s-%s", projectName(client, unscopedToken, projectID), clusterName)
+	}
+	kubeconfig := singleContext(contextName, clusterInfo, authInfo)
+
+	if opts.Merge {
+		path := DefaultKubeconfigPath()
+		if opts.OutputPath != "" {
+			path = expandPath(opts.OutputPath)
+		}
+
+		backup, err := mergeKubeconfig(path, kubeconfig, opts.SetCurrent)
+		if err != nil {
+			return fmt.Errorf("failed to merge kubeconfig: %w", err)
+		}
+
+		color.Green("✓ Context %s merged into: %s", contextName, path)
+		if backup != "" {
+			color.Cyan("  Previous kubeconfig saved to: %s", backup)
+		}
+		printKubeconfigUsage(opts, clusterID)
+		if !opts.SetCurrent {
+			fmt.Printf("  kubectl config use-context %s\n", contextName)
+		}
+		fmt.Printf("  kubectl get nodes\n")
+		return nil
+	}
+
+	if !verbatim {
+		data, err = yaml.Marshal(kubeconfig)
+		if err != nil {
+			return fmt.Errorf("failed to build kubeconfig: %w", err)
+		}
+	}
+
+	// A directory gets one file per context
+	outputPath := expandPath(opts.OutputPath)
+	if info, err := os.Stat(outputPath); err == nil && info.IsDir() {
+		outputPath = filepath.Join(outputPath, contextName)
 	}
 
 	// Save to file
-	if err := os.WriteFile(outputPath, []byte(kubeconfigResp.Kubeconfig), 0600); err != nil {
-		color.Red("✗ Failed to save kubeconfig: %v", err)
-		return
+	if err := os.WriteFile(outputPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to save kubeconfig: %w", err)
 	}
 
 	color.Green("✓ Kubeconfig saved to: %s", outputPath)
-	color.Cyan("\nUsage:")
+	printKubeconfigUsage(opts, clusterID)
 	fmt.Printf("  export KUBECONFIG=%s\n", outputPath)
 	fmt.Printf("  kubectl get nodes\n")
+	return nil
+}
+
+func printKubeconfigUsage(opts KubeconfigOptions, clusterID string) {
+	if opts.Exec {
+		color.Cyan("  Credentials are issued on demand by: otc-cli cce token %s", clusterID)
+	}
+	color.Cyan("\nUsage:")
 }
@@ -0,0 +1,138 @@
+package resource
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultKubeconfigPath returns the first file in $KUBECONFIG, or ~/.kube/config
+func DefaultKubeconfigPath() string {
+	for _, path := range filepath.SplitList(os.Getenv("KUBECONFIG")) {
+		if path != "" {
+			return expandPath(path)
+		}
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".kube", "config")
+}
+
+// expandPath expands a leading ~ to the home directory
+func expandPath(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, _ := os.UserHomeDir()
+		return filepath.Join(home, strings.TrimPrefix(path, "~"))
+	}
+	return path
+}
+
+// mergeKubeconfig inserts or replaces the clusters, users and contexts of add in
+// the kubeconfig at path. The existing document is edited generically so fields
+// otc-cli doesn't know about are preserved. The prior file is backed up first;
+// the backup path is returned (empty if there was no prior file).
+func mergeKubeconfig(path string, add *Kubeconfig, setCurrent bool) (string, error) {
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	var doc yaml.MapSlice
+	if err := yaml.Unmarshal(existing, &doc); err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(doc) == 0 {
+		doc = yaml.MapSlice{
+			{Key: "apiVersion", Value: "v1"},
+			{Key: "kind", Value: "Config"},
+			{Key: "preferences", Value: yaml.MapSlice{}},
+		}
+	}
+
+	// Convert the typed entries to the generic form used by doc
+	data, err := yaml.Marshal(add)
+	if err != nil {
+		return "", err
+	}
+	var addDoc yaml.MapSlice
+	if err := yaml.Unmarshal(data, &addDoc); err != nil {
+		return "", err
+	}
+
+	for _, section := range []string{"clusters", "users", "contexts"} {
+		entries, _ := getKey(addDoc, section).([]interface{})
+		doc = setKey(doc, section, upsertNamed(getKey(doc, section), entries))
+	}
+
+	if current, _ := getKey(doc, "current-context").(string); setCurrent || current == "" {
+		doc = setKey(doc, "current-context", add.CurrentContext)
+	}
+
+	out, err := yaml.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+
+	backup := ""
+	if existing != nil {
+		backup = fmt.Sprintf("%s.%s.bak", path, time.Now().Format("20060102-150405"))
+		if err := os.WriteFile(backup, existing, 0600); err != nil {
+			return "", fmt.Errorf("failed to back up %s: %w", path, err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return backup, err
+	}
+
+	return backup, os.WriteFile(path, out, 0600)
+}
+
+func getKey(doc yaml.MapSlice, key string) interface{} {
+	for _, item := range doc {
+		if item.Key == key {
+			return item.Value
+		}
+	}
+	return nil
+}
+
+func setKey(doc yaml.MapSlice, key string, value interface{}) yaml.MapSlice {
+	for i, item := range doc {
+		if item.Key == key {
+			doc[i].Value = value
+			return doc
+		}
+	}
+	return append(doc, yaml.MapItem{Key: key, Value: value})
+}
+
+// upsertNamed replaces list entries with the same "name" and appends new ones
+func upsertNamed(list interface{}, entries []interface{}) []interface{} {
+	existing, _ := list.([]interface{})
+
+	for _, entry := range entries {
+		name := getKey(toMapSlice(entry), "name")
+		replaced := false
+		for i, item := range existing {
+			if getKey(toMapSlice(item), "name") == name {
+				existing[i] = entry
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			existing = append(existing, entry)
+		}
+	}
+
+	return existing
+}
+
+func toMapSlice(v interface{}) yaml.MapSlice {
+	m, _ := v.(yaml.MapSlice)
+	return m
+}
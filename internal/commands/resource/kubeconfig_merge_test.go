@@ -0,0 +1,168 @@
+package resource
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+const existingKubeconfig = `apiVersion: v1
+kind: Config
+preferences:
+  colors: true
+clusters:
+- name: minikube
+  cluster:
+    server: https://192.168.49.2:8443
+- name: otc-prod
+  cluster:
+    server: https://old.example.com:5443
+users:
+- name: minikube
+  user:
+    client-certificate: /home/me/.minikube/client.crt
+- name: otc-prod
+  user:
+    client-certificate-data: b2xk
+contexts:
+- name: minikube
+  context:
+    cluster: minikube
+    user: minikube
+    namespace: dev
+- name: otc-prod
+  context:
+    cluster: otc-prod
+    user: otc-prod
+current-context: minikube
+`
+
+func newKubeconfig(name, server string) *Kubeconfig {
+	return &Kubeconfig{
+		APIVersion:     "v1",
+		Kind:           "Config",
+		Clusters:       []NamedCluster{{Name: name, Cluster: ClusterInfo{Server: server}}},
+		Users:          []NamedUser{{Name: name, User: AuthInfo{ClientCertificateData: "bmV3"}}},
+		Contexts:       []NamedContext{{Name: name, Context: ContextInfo{Cluster: name, User: name}}},
+		CurrentContext: name,
+	}
+}
+
+func writeKubeconfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func readKubeconfig(t *testing.T, path string) (*Kubeconfig, string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kubeconfig Kubeconfig
+	if err := yaml.Unmarshal(data, &kubeconfig); err != nil {
+		t.Fatalf("merged kubeconfig doesn't parse: %v\n%s", err, data)
+	}
+	return &kubeconfig, string(data)
+}
+
+func TestMergeKubeconfigReplacesEntries(t *testing.T) {
+	path := writeKubeconfig(t, existingKubeconfig)
+
+	backup, err := mergeKubeconfig(path, newKubeconfig("otc-prod", "https://new.example.com:5443"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merged, _ := readKubeconfig(t, path)
+	if len(merged.Clusters) != 2 || len(merged.Users) != 2 || len(merged.Contexts) != 2 {
+		t.Fatalf("got %d clusters, %d users, %d contexts, want 2 each", len(merged.Clusters), len(merged.Users), len(merged.Contexts))
+	}
+	if got := merged.Clusters[1]; got.Name != "otc-prod" || got.Cluster.Server != "https://new.example.com:5443" {
+		t.Errorf("cluster not replaced in place: %+v", got)
+	}
+	if got := merged.Users[1]; got.User.ClientCertificateData != "bmV3" {
+		t.Errorf("user not replaced: %+v", got)
+	}
+
+	old, err := os.ReadFile(backup)
+	if err != nil || string(old) != existingKubeconfig {
+		t.Errorf("backup %q doesn't hold the prior file (err %v)", backup, err)
+	}
+}
+
+func TestMergeKubeconfigKeepsUnrelatedEntries(t *testing.T) {
+	path := writeKubeconfig(t, existingKubeconfig)
+
+	if _, err := mergeKubeconfig(path, newKubeconfig("otc-dev", "https://dev.example.com:5443"), false); err != nil {
+		t.Fatal(err)
+	}
+
+	merged, raw := readKubeconfig(t, path)
+	if len(merged.Clusters) != 3 || len(merged.Users) != 3 || len(merged.Contexts) != 3 {
+		t.Fatalf("got %d clusters, %d users, %d contexts, want 3 each", len(merged.Clusters), len(merged.Users), len(merged.Contexts))
+	}
+	if merged.Clusters[0].Name != "minikube" || merged.Clusters[1].Cluster.Server != "https://old.example.com:5443" {
+		t.Errorf("existing clusters changed: %+v", merged.Clusters)
+	}
+	if merged.Contexts[0].Context.Namespace != "dev" {
+		t.Errorf("namespace of existing context lost: %+v", merged.Contexts[0])
+	}
+	// Fields otc-cli doesn't model must survive
+	for _, want := range []string{"client-certificate: /home/me/.minikube/client.crt", "colors: true"} {
+		if !strings.Contains(raw, want) {
+			t.Errorf("merged kubeconfig lost %q:\n%s", want, raw)
+		}
+	}
+	if merged.CurrentContext != "minikube" {
+		t.Errorf("current-context = %q without --set-current, want minikube", merged.CurrentContext)
+	}
+}
+
+func TestMergeKubeconfigSetCurrent(t *testing.T) {
+	path := writeKubeconfig(t, existingKubeconfig)
+
+	if _, err := mergeKubeconfig(path, newKubeconfig("otc-dev", "https://dev.example.com:5443"), true); err != nil {
+		t.Fatal(err)
+	}
+
+	merged, _ := readKubeconfig(t, path)
+	if merged.CurrentContext != "otc-dev" {
+		t.Errorf("current-context = %q, want otc-dev", merged.CurrentContext)
+	}
+}
+
+func TestMergeKubeconfigMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".kube", "config")
+
+	backup, err := mergeKubeconfig(path, newKubeconfig("otc-dev", "https://dev.example.com:5443"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backup != "" {
+		t.Errorf("backup = %q for a new file, want none", backup)
+	}
+
+	merged, _ := readKubeconfig(t, path)
+	if merged.APIVersion != "v1" || merged.Kind != "Config" {
+		t.Errorf("new kubeconfig lacks header: apiVersion %q, kind %q", merged.APIVersion, merged.Kind)
+	}
+	if len(merged.Contexts) != 1 || merged.CurrentContext != "otc-dev" {
+		t.Errorf("got contexts %+v, current %q; want otc-dev as the only and current context", merged.Contexts, merged.CurrentContext)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("kubeconfig mode = %o, want 600", perm)
+	}
+}
@@ -0,0 +1,132 @@
+package resource
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/abdo-farag/otc-cli/internal/config"
+	"github.com/abdo-farag/otc-cli/internal/otc"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestGetKubeconfigExec(t *testing.T) {
+	var requests []string
+	srv := newIAMStub(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v3/projects/p1/clusters":
+			io.WriteString(w, `{"items":[{"metadata":{"uid":"c1","name":"dev"}}]}`)
+		case r.Method == "GET" && r.URL.Path == "/api/v3/projects/p1/clusters/c1":
+			io.WriteString(w, `{"metadata":{"uid":"c1","name":"dev"},"status":{"phase":"Available","endpoints":[
+				{"url":"https://192.168.0.10:5443","type":"Internal"},
+				{"url":"https://80.158.1.2:5443","type":"External"}]}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	cfg := &config.Config{AUTHURL: srv.URL, Endpoints: map[string]string{"cce": srv.URL}}
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+
+	for endpoint, want := range map[string]string{"": "https://192.168.0.10:5443", "external": "https://80.158.1.2:5443"} {
+		requests = nil
+		err := GetKubeconfig(cfg, otc.NewClient(cfg), "unscoped", "p1", "dev", KubeconfigOptions{
+			OutputPath:  path,
+			Exec:        true,
+			ContextName: "dev",
+			Endpoint:    endpoint,
+			ClusterCA:   func(projectID, clusterID string) (string, error) { return "Y2E=", nil },
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var kubeconfig Kubeconfig
+		if err := yaml.Unmarshal(data, &kubeconfig); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := kubeconfig.Clusters[0].Cluster; got.Server != want || got.CertificateAuthorityData != "Y2E=" {
+			t.Errorf("endpoint %q: cluster = %+v, want server %s with the CA", endpoint, got, want)
+		}
+		if exec := kubeconfig.Users[0].User.Exec; exec == nil || !reflect.DeepEqual(exec.Args, []string{"cce", "token", "c1", "--project", "p1"}) {
+			t.Errorf("endpoint %q: exec = %+v, want otc-cli cce token", endpoint, exec)
+		}
+		// No certificate is requested
+		for _, r := range requests {
+			if filepath.Base(r) == "clustercert" {
+				t.Errorf("endpoint %q: requested %s", endpoint, r)
+			}
+		}
+	}
+}
+
+func TestGetKubeconfigKeepsDownloadedContexts(t *testing.T) {
+	const document = `apiVersion: v1
+kind: Config
+clusters:
+- name: internalCluster
+  cluster:
+    server: https://192.168.0.10:5443
+- name: externalCluster
+  cluster:
+    server: https://80.158.1.2:5443
+users:
+- name: user
+  user:
+    client-certificate-data: Y2VydA==
+contexts:
+- name: internal
+  context:
+    cluster: internalCluster
+    user: user
+- name: external
+  context:
+    cluster: externalCluster
+    user: user
+current-context: internal
+`
+	srv := newIAMStub(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/projects/p1/clusters":
+			io.WriteString(w, `{"items":[{"metadata":{"uid":"c1","name":"dev"}}]}`)
+		case "/api/v3/projects/p1/clusters/c1/clustercert":
+			json.NewEncoder(w).Encode(map[string]string{"kubeconfig": document})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	cfg := &config.Config{AUTHURL: srv.URL, Endpoints: map[string]string{"cce": srv.URL}}
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+
+	if err := GetKubeconfig(cfg, otc.NewClient(cfg), "unscoped", "p1", "dev", KubeconfigOptions{OutputPath: path}); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != document {
+		t.Errorf("kubeconfig = %q, want it as downloaded", data)
+	}
+
+	// Picking an endpoint writes a single context
+	if err := GetKubeconfig(cfg, otc.NewClient(cfg), "unscoped", "p1", "dev", KubeconfigOptions{OutputPath: path, Endpoint: "external", ContextName: "dev"}); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	var kubeconfig Kubeconfig
+	if err := yaml.Unmarshal(data, &kubeconfig); err != nil {
+		t.Fatal(err)
+	}
+	if len(kubeconfig.Contexts) != 1 || kubeconfig.Clusters[0].Cluster.Server != "https://80.158.1.2:5443" {
+		t.Errorf("kubeconfig = %+v, want the external context only", kubeconfig)
+	}
+}
@@ -1,30 +1,71 @@
 package resource
 
 import (
-	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
+
 	"github.com/abdo-farag/otc-cli/internal/config"
 	"github.com/abdo-farag/otc-cli/internal/otc"
-
-	"github.com/fatih/color"
-	"github.com/rodaine/table"
+	"github.com/abdo-farag/otc-cli/internal/output"
 )
 
+// Image is an IMS system or custom image
+type Image struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Status         string `json:"status"`
+	MinDisk        int    `json:"min_disk"`
+	MinRAM         int    `json:"min_ram"`
+	DiskSize       int    `json:"disk_size,omitempty"`
+	OsType         string `json:"__os_type"`
+	Platform       string `json:"__platform"`
+	ImageType      string `json:"__imagetype"`
+	Visibility     string `json:"visibility"`
+	OsVersion      string `json:"__os_version"`
+	SupportKvm     string `json:"__support_kvm,omitempty"`
+	VirtualEnvType string `json:"virtual_env_type"`
+}
+
+func (Image) Columns(wide bool) []string {
+	columns := []string{"Name", "Platform", "Type", "Status", "ID"}
+	if wide {
+		columns = append(columns, "OS Version", "Min Disk (GB)", "Min RAM (MB)")
+	}
+	return columns
+}
+
+func (img Image) Cells(wide bool) []string {
+	platform := img.Platform
+	if platform == "" {
+		platform = img.OsType
+	}
+
+	imageType := img.ImageType
+	if imageType == "" {
+		imageType = img.Visibility
+	}
+
+	cells := []string{img.Name, output.OrDash(platform), imageType, img.Status, img.ID}
+	if wide {
+		cells = append(cells, output.OrDash(img.OsVersion), strconv.Itoa(img.MinDisk), strconv.Itoa(img.MinRAM))
+	}
+	return cells
+}
+
 // ListImages lists all available ECS images
-func ListImages(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, options map[string]interface{}, raw bool) {
-	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, raw)
+func ListImages(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, options map[string]interface{}, quiet bool) (*output.List[Image], error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, quiet)
 	if err != nil {
-		color.Red("✗ %v", err)
-		return
+		return nil, err
 	}
 
 	// Using IMS (Image Management Service) API for images
-	imageURL := fmt.Sprintf("https://ims.%s.otc.t-systems.com/v2/cloudimages", cfg.Region)
+	imageURL := client.Endpoint("ims", projectID) + "/v2/cloudimages"
 
 	// Build query parameters for filtering
 	queryParams := []string{}
-	
+
 	// Filter by visibility (private/public/shared)
 	if visibility, ok := options["visibility"].(string); ok && visibility != "" {
 		if strings.EqualFold(visibility, "public") {
@@ -56,121 +97,36 @@ func ListImages(cfg *config.Config, client *otc.Client, unscopedToken, projectID
 		imageURL = imageURL + "?" + strings.Join(queryParams, "&")
 	}
 
-	body, statusCode, err := MakeRequest(imageURL, projectToken)
-	if err != nil {
-		color.Red("✗ Request failed: %v", err)
-		return
-	}
-
-	if statusCode != 200 {
-		color.Red("✗ API error (status %d): %s", statusCode, string(body))
-		return
-	}
-
-	var result struct {
-		Images []struct {
-			ID              string `json:"id"`
-			Name            string `json:"name"`
-			Status          string `json:"status"`
-			MinDisk         int    `json:"min_disk"`
-			MinRAM          int    `json:"min_ram"`
-			DiskSize        int    `json:"disk_size"`
-			OsType          string `json:"__os_type"`
-			Platform        string `json:"__platform"`
-			ImageType       string `json:"__imagetype"`
-			Visibility      string `json:"visibility"`
-			OsVersion       string `json:"__os_version"`
-			SupportKvm      string `json:"__support_kvm"`
-			VirtualEnvType  string `json:"virtual_env_type"`
-		} `json:"images"`
-	}
-
-	if err := json.Unmarshal(body, &result); err != nil {
-		color.Red("✗ Failed to parse response: %v", err)
-		return
-	}
-
 	// Apply client-side name filtering (case-insensitive contains)
-	images := result.Images
+	var keep func(Image) bool
 	if nameFilter, ok := options["name"].(string); ok && nameFilter != "" {
-		var filtered []struct {
-			ID              string `json:"id"`
-			Name            string `json:"name"`
-			Status          string `json:"status"`
-			MinDisk         int    `json:"min_disk"`
-			MinRAM          int    `json:"min_ram"`
-			DiskSize        int    `json:"disk_size"`
-			OsType          string `json:"__os_type"`
-			Platform        string `json:"__platform"`
-			ImageType       string `json:"__imagetype"`
-			Visibility      string `json:"visibility"`
-			OsVersion       string `json:"__os_version"`
-			SupportKvm      string `json:"__support_kvm"`
-			VirtualEnvType  string `json:"virtual_env_type"`
+		keep = func(img Image) bool {
+			return strings.Contains(strings.ToLower(img.Name), strings.ToLower(nameFilter))
 		}
-		for _, img := range images {
-			if strings.Contains(strings.ToLower(img.Name), strings.ToLower(nameFilter)) {
-				filtered = append(filtered, img)
-			}
-		}
-		images = filtered
-	}
-
-	if raw {
-		jsonData, _ := json.MarshalIndent(images, "", "  ")
-		fmt.Println(string(jsonData))
-		return
 	}
 
-	if len(images) == 0 {
-		color.Yellow("⚠ No images found matching the filters")
-		
-		// If filtered and no results, suggest trying without filters
-		if visibility, ok := options["visibility"].(string); ok && visibility != "" {
-			color.Cyan("\nTip: Try listing all images first to see what's available:")
-			color.Cyan("  otc-cli list images")
-		}
-		return
+	images, streamed, err := listAll(client, imageURL, projectToken, otc.ListOptions{ItemsKey: "images"}, options, keep)
+	if err != nil {
+		return nil, err
 	}
 
-	headerFmt := color.New(color.FgCyan, color.Bold).SprintfFunc()
-	tbl := table.New("Name", "Platform", "Type", "Status", "ID")
-	tbl.WithHeaderFormatter(headerFmt)
+	list := output.NewList(images, "images")
+	list.Streamed = streamed
+	list.Title = "Project: " + projectID
 
-	for _, img := range images {
-		platform := img.Platform
-		if platform == "" {
-			platform = img.OsType
-		}
-		if platform == "" {
-			platform = "-"
-		}
-
-		imageType := img.ImageType
-		if imageType == "" {
-			imageType = img.Visibility
-		}
-
-		tbl.AddRow(img.Name, platform, imageType, img.Status, img.ID)
-	}
-
-	fmt.Printf("\n")
-	color.Cyan("Project: %s", projectID)
-	
 	// Show active filters
 	if visibility, ok := options["visibility"].(string); ok && visibility != "" {
-		color.Yellow("Filter: Visibility = %s", visibility)
+		list.Notes = append(list.Notes, fmt.Sprintf("Filter: Visibility = %s", visibility))
 	}
 	if osType, ok := options["os"].(string); ok && osType != "" {
-		color.Yellow("Filter: OS Type = %s", osType)
+		list.Notes = append(list.Notes, fmt.Sprintf("Filter: OS Type = %s", osType))
 	}
 	if platform, ok := options["platform"].(string); ok && platform != "" {
-		color.Yellow("Filter: Platform = %s", platform)
+		list.Notes = append(list.Notes, fmt.Sprintf("Filter: Platform = %s", platform))
 	}
 	if name, ok := options["name"].(string); ok && name != "" {
-		color.Yellow("Filter: Name contains '%s'", name)
+		list.Notes = append(list.Notes, fmt.Sprintf("Filter: Name contains '%s'", name))
 	}
-	
-	tbl.Print()
-	fmt.Printf("\nTotal: %d images\n", len(images))
-}
\ No newline at end of file
+
+	return list, nil
+}
@@ -1,75 +1,60 @@
 package resource
 
 import (
-	"encoding/json"
 	"fmt"
+
 	"github.com/abdo-farag/otc-cli/internal/config"
 	"github.com/abdo-farag/otc-cli/internal/otc"
-
-	"github.com/fatih/color"
-	"github.com/rodaine/table"
+	"github.com/abdo-farag/otc-cli/internal/output"
 )
 
+// Keypair is an SSH keypair
+type Keypair struct {
+	Name        string `json:"name"`
+	Fingerprint string `json:"fingerprint"`
+	PublicKey   string `json:"public_key"`
+	Type        string `json:"type,omitempty"`
+}
+
+func (Keypair) Columns(wide bool) []string {
+	if wide {
+		return []string{"Name", "Fingerprint", "Type"}
+	}
+	return []string{"Name", "Fingerprint"}
+}
+
+func (k Keypair) Cells(wide bool) []string {
+	if wide {
+		return []string{k.Name, k.Fingerprint, output.OrDash(k.Type)}
+	}
+	return []string{k.Name, k.Fingerprint}
+}
+
 // ListKeypairs lists all available SSH keypairs
-func ListKeypairs(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, raw bool) {
-	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, raw)
+func ListKeypairs(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, quiet bool) (*output.List[Keypair], error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, quiet)
 	if err != nil {
-		color.Red("✗ %v", err)
-		return
+		return nil, err
 	}
 
 	// Keypairs endpoint - includes project ID
-	keypairURL := fmt.Sprintf("https://ecs.%s.otc.t-systems.com/v2.1/%s/os-keypairs", cfg.Region, projectID)
+	keypairURL := fmt.Sprintf("%s/v2.1/%s/os-keypairs", client.Endpoint("ecs", projectID), projectID)
 
-	body, statusCode, err := MakeRequest(keypairURL, projectToken)
-	if err != nil {
-		color.Red("✗ Request failed: %v", err)
-		return
+	var result struct {
+		Keypairs []struct {
+			Keypair Keypair `json:"keypair"`
+		} `json:"keypairs"`
 	}
-
-	if statusCode != 200 {
-		color.Red("✗ API error (status %d): %s", statusCode, string(body))
-		return
+	if err := client.Get(keypairURL, projectToken, &result); err != nil {
+		return nil, err
 	}
 
-	if raw {
-		var prettyJSON map[string]interface{}
-		json.Unmarshal(body, &prettyJSON)
-		formatted, _ := json.MarshalIndent(prettyJSON, "", "  ")
-		fmt.Println(string(formatted))
-		return
+	keypairs := make([]Keypair, 0, len(result.Keypairs))
+	for _, item := range result.Keypairs {
+		keypairs = append(keypairs, item.Keypair)
 	}
 
-var result struct {
-  Keypairs []struct {
-    Keypair struct {
-      Name        string `json:"name"`
-      Fingerprint string `json:"fingerprint"`
-      PublicKey   string `json:"public_key"`
-    } `json:"keypair"`
-  } `json:"keypairs"`
-}
-
-if err := json.Unmarshal(body, &result); err != nil {
-  color.Red("✗ Failed to parse response: %v", err)
-  return
-}
-
-if len(result.Keypairs) == 0 {
-  color.Cyan("No keypairs found")
-  return
-}
-
-headerFmt := color.New(color.FgCyan, color.Bold).SprintfFunc()
-tbl := table.New("Name", "Fingerprint")
-tbl.WithHeaderFormatter(headerFmt)
-
-for _, item := range result.Keypairs {
-  tbl.AddRow(item.Keypair.Name, item.Keypair.Fingerprint)
-}
-
-	fmt.Printf("\n")
-	color.Cyan("Available Keypairs")
-	tbl.Print()
-	fmt.Printf("\nTotal: %d keypairs\n", len(result.Keypairs))
+	list := output.NewList(keypairs, "keypairs")
+	list.Title = "Available Keypairs"
+	return list, nil
 }
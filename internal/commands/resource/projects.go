@@ -1,50 +1,45 @@
 package resource
 
 import (
-	"encoding/json"
 	"fmt"
+
 	"github.com/abdo-farag/otc-cli/internal/config"
 	"github.com/abdo-farag/otc-cli/internal/otc"
-
-	"github.com/fatih/color"
-	"github.com/rodaine/table"
+	"github.com/abdo-farag/otc-cli/internal/output"
 )
 
-// listProjects lists all OTC projects
-func ListProjects(cfg *config.Config, client *otc.Client, unscopedToken string, raw bool) {
+// Project is an IAM project
+type Project struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func (Project) Columns(wide bool) []string {
+	return []string{"Name", "ID"}
+}
+
+func (p Project) Cells(wide bool) []string {
+	return []string{p.Name, p.ID}
+}
+
+// ListProjects lists all OTC projects
+func ListProjects(cfg *config.Config, client *otc.Client, unscopedToken string) (*output.List[Project], error) {
 	domainToken, err := client.GetDomainScopedToken(unscopedToken)
 	if err != nil {
-		color.Red("✗ Failed to get domain token: %v", err)
-		return
+		return nil, fmt.Errorf("failed to get domain token: %w", err)
 	}
 
 	projects, err := client.ListProjects(domainToken)
 	if err != nil {
-		color.Red("✗ Failed to list projects: %v", err)
-		return
+		return nil, fmt.Errorf("failed to list projects: %w", err)
 	}
 
-	if raw {
-		// Output as JSON
-		jsonData := map[string]interface{}{
-			"projects": projects,
-		}
-		formatted, _ := json.MarshalIndent(jsonData, "", "  ")
-		fmt.Println(string(formatted))
-		return
-	}
-
-	// Formatted table output
-	headerFmt := color.New(color.FgCyan, color.Bold).SprintfFunc()
-	tbl := table.New("Name", "ID")
-	tbl.WithHeaderFormatter(headerFmt)
-
+	items := make([]Project, 0, len(projects))
 	for _, p := range projects {
-		tbl.AddRow(p.Name, p.ID)
+		items = append(items, Project{ID: p.ID, Name: p.Name})
 	}
 
-	fmt.Printf("\n")
-	color.Cyan("OTC Projects")
-	tbl.Print()
-	fmt.Printf("\nTotal: %d projects\n", len(projects))
+	list := output.NewList(items, "projects")
+	list.Title = "OTC Projects"
+	return list, nil
 }
@@ -0,0 +1,453 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/abdo-farag/otc-cli/internal/config"
+	"github.com/abdo-farag/otc-cli/internal/otc"
+	"github.com/abdo-farag/otc-cli/internal/output"
+)
+
+// SecurityGroup is a VPC security group with its rules
+type SecurityGroup struct {
+	ID                  string              `json:"id"`
+	Name                string              `json:"name"`
+	Description         string              `json:"description,omitempty"`
+	VpcID               string              `json:"vpc_id,omitempty"`
+	EnterpriseProjectID string              `json:"enterprise_project_id,omitempty"`
+	Rules               []SecurityGroupRule `json:"security_group_rules"`
+}
+
+func (SecurityGroup) Columns(wide bool) []string {
+	if wide {
+		return []string{"Name", "ID", "VPC", "Rules", "Description"}
+	}
+	return []string{"Name", "ID", "VPC", "Rules"}
+}
+
+func (g SecurityGroup) Cells(wide bool) []string {
+	cells := []string{g.Name, g.ID, output.OrDash(g.VpcID), strconv.Itoa(len(g.Rules))}
+	if wide {
+		cells = append(cells, output.OrDash(g.Description))
+	}
+	return cells
+}
+
+// SecurityGroupRule allows traffic in one direction from or to a CIDR or
+// the members of another security group
+type SecurityGroupRule struct {
+	ID              string `json:"id"`
+	SecurityGroupID string `json:"security_group_id"`
+	Direction       string `json:"direction"` // ingress or egress
+	Ethertype       string `json:"ethertype"` // IPv4 or IPv6
+	Protocol        string `json:"protocol,omitempty"`
+	PortRangeMin    *int   `json:"port_range_min,omitempty"`
+	PortRangeMax    *int   `json:"port_range_max,omitempty"`
+	RemoteIPPrefix  string `json:"remote_ip_prefix,omitempty"`
+	RemoteGroupID   string `json:"remote_group_id,omitempty"`
+	Description     string `json:"description,omitempty"`
+
+	// RemoteGroupName is filled in by GetSecurityGroup for display
+	RemoteGroupName string `json:"-"`
+}
+
+func (SecurityGroupRule) Columns(wide bool) []string {
+	columns := []string{"Direction", "Protocol", "Ports", "Remote"}
+	if wide {
+		columns = append(columns, "Ethertype", "ID", "Description")
+	}
+	return columns
+}
+
+func (r SecurityGroupRule) Cells(wide bool) []string {
+	cells := []string{r.Direction, r.ProtocolName(), r.Ports(), r.Remote()}
+	if wide {
+		cells = append(cells, r.Ethertype, r.ID, output.OrDash(r.Description))
+	}
+	return cells
+}
+
+// ProtocolName returns the protocol, or "any" if the rule allows all protocols
+func (r SecurityGroupRule) ProtocolName() string {
+	if r.Protocol == "" {
+		return "any"
+	}
+	return r.Protocol
+}
+
+// Ports returns the port range as "22", "8000-8100" or "any"
+func (r SecurityGroupRule) Ports() string {
+	low, high := r.portRange()
+	switch {
+	case low == 1 && high == 65535:
+		return "any"
+	case low == high:
+		return strconv.Itoa(low)
+	}
+	return fmt.Sprintf("%d-%d", low, high)
+}
+
+// portRange returns the ports the rule covers, 1-65535 if it isn't limited
+func (r SecurityGroupRule) portRange() (int, int) {
+	low, high := 1, 65535
+	if r.PortRangeMin != nil && *r.PortRangeMin > 0 {
+		low = *r.PortRangeMin
+	}
+	if r.PortRangeMax != nil && *r.PortRangeMax > 0 {
+		high = *r.PortRangeMax
+	}
+	return low, high
+}
+
+// Remote returns the CIDR or security group the rule applies to
+func (r SecurityGroupRule) Remote() string {
+	switch {
+	case r.RemoteGroupID != "" && r.RemoteGroupName != "":
+		return "group " + r.RemoteGroupName
+	case r.RemoteGroupID != "":
+		return "group " + r.RemoteGroupID
+	case r.RemoteIPPrefix != "":
+		return r.RemoteIPPrefix
+	case r.Ethertype == "IPv6":
+		return "::/0"
+	}
+	return "0.0.0.0/0"
+}
+
+// openToInternet reports whether the rule lets in traffic from any address
+func (r SecurityGroupRule) openToInternet() bool {
+	if r.Direction != "ingress" || r.RemoteGroupID != "" {
+		return false
+	}
+	return r.RemoteIPPrefix == "" || r.RemoteIPPrefix == "0.0.0.0/0" || r.RemoteIPPrefix == "::/0"
+}
+
+// ListSecurityGroups lists all security groups, optionally only those of one VPC
+func ListSecurityGroups(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, options map[string]interface{}, quiet bool) (*output.List[SecurityGroup], error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, quiet)
+	if err != nil {
+		return nil, err
+	}
+
+	sgURL := securityGroupsURL(client, projectID)
+	vpc, _ := options["vpc"].(string)
+	if vpc != "" {
+		vpcURL := fmt.Sprintf("%s/v1/%s/vpcs", client.Endpoint("vpc", projectID), projectID)
+		vpcID, err := resolveID(client, vpcURL, projectToken, "vpcs", "VPC", vpc)
+		if err != nil {
+			return nil, err
+		}
+		sgURL += "?vpc_id=" + url.QueryEscape(vpcID)
+	}
+
+	groups, streamed, err := listAll[SecurityGroup](client, sgURL, projectToken, otc.ListOptions{ItemsKey: "security_groups"}, options, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	list := output.NewList(groups, "security groups")
+	list.Streamed = streamed
+	list.Title = "Project: " + projectID
+	if vpc != "" {
+		list.Notes = append(list.Notes, "Filter: VPC = "+vpc)
+	}
+	return list, nil
+}
+
+// GetSecurityGroup gets a security group by name or ID, with the names of
+// the groups its rules refer to
+func GetSecurityGroup(cfg *config.Config, client *otc.Client, unscopedToken, projectID, nameOrID string, quiet bool) (*SecurityGroup, error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, quiet)
+	if err != nil {
+		return nil, err
+	}
+
+	groupID, err := resolveID(client, securityGroupsURL(client, projectID), projectToken, "security_groups", "security group", nameOrID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		SecurityGroup SecurityGroup `json:"security_group"`
+	}
+	if err := client.Get(securityGroupsURL(client, projectID)+"/"+groupID, projectToken, &result); err != nil {
+		return nil, err
+	}
+	group := &result.SecurityGroup
+
+	names := map[string]string{group.ID: group.Name}
+	for i, rule := range group.Rules {
+		if rule.RemoteGroupID == "" {
+			continue
+		}
+		if _, ok := names[rule.RemoteGroupID]; !ok {
+			var remote struct {
+				SecurityGroup SecurityGroup `json:"security_group"`
+			}
+			// A group we may not read is shown by ID
+			if err := client.Get(securityGroupsURL(client, projectID)+"/"+rule.RemoteGroupID, projectToken, &remote); err == nil {
+				names[rule.RemoteGroupID] = remote.SecurityGroup.Name
+			} else {
+				names[rule.RemoteGroupID] = ""
+			}
+		}
+		group.Rules[i].RemoteGroupName = names[rule.RemoteGroupID]
+	}
+
+	sortRules(group.Rules)
+	return group, nil
+}
+
+// CreateSecurityGroup creates a security group. OTC adds default rules that
+// allow all egress traffic and all traffic between members of the group.
+func CreateSecurityGroup(cfg *config.Config, client *otc.Client, unscopedToken, projectID, name, vpc, description string) (*SecurityGroup, error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	group := map[string]interface{}{"name": name}
+	if vpc != "" {
+		vpcURL := fmt.Sprintf("%s/v1/%s/vpcs", client.Endpoint("vpc", projectID), projectID)
+		vpcID, err := resolveID(client, vpcURL, projectToken, "vpcs", "VPC", vpc)
+		if err != nil {
+			return nil, err
+		}
+		group["vpc_id"] = vpcID
+	}
+	if description != "" {
+		group["description"] = description
+	}
+
+	body, err := client.Do("POST", securityGroupsURL(client, projectID), projectToken, map[string]interface{}{"security_group": group})
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		SecurityGroup SecurityGroup `json:"security_group"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	sortRules(result.SecurityGroup.Rules)
+	return &result.SecurityGroup, nil
+}
+
+// RuleSpec describes a security group rule to add
+type RuleSpec struct {
+	Direction   string // ingress or egress
+	Ethertype   string // IPv4 or IPv6
+	Protocol    string // tcp, udp, icmp or empty for any
+	PortMin     int    // 0 for all ports
+	PortMax     int
+	RemoteIP    string // CIDR
+	RemoteGroup string // Security group name or ID
+	Description string
+}
+
+// AddSecurityGroupRule adds a rule to the security group with the given name or ID
+func AddSecurityGroupRule(cfg *config.Config, client *otc.Client, unscopedToken, projectID, group string, spec RuleSpec) (*SecurityGroupRule, error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	sgURL := securityGroupsURL(client, projectID)
+	groupID, err := resolveID(client, sgURL, projectToken, "security_groups", "security group", group)
+	if err != nil {
+		return nil, err
+	}
+
+	rule := map[string]interface{}{
+		"security_group_id": groupID,
+		"direction":         spec.Direction,
+		"ethertype":         spec.Ethertype,
+	}
+	if spec.Protocol != "" {
+		rule["protocol"] = spec.Protocol
+	}
+	if spec.PortMin > 0 {
+		rule["port_range_min"] = spec.PortMin
+		rule["port_range_max"] = spec.PortMax
+	}
+	if spec.RemoteIP != "" {
+		rule["remote_ip_prefix"] = spec.RemoteIP
+	}
+	if spec.RemoteGroup != "" {
+		remoteID, err := resolveID(client, sgURL, projectToken, "security_groups", "security group", spec.RemoteGroup)
+		if err != nil {
+			return nil, err
+		}
+		rule["remote_group_id"] = remoteID
+	}
+	if spec.Description != "" {
+		rule["description"] = spec.Description
+	}
+
+	ruleURL := fmt.Sprintf("%s/v1/%s/security-group-rules", client.Endpoint("vpc", projectID), projectID)
+	body, err := client.Do("POST", ruleURL, projectToken, map[string]interface{}{"security_group_rule": rule})
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Rule SecurityGroupRule `json:"security_group_rule"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &result.Rule, nil
+}
+
+// DeleteSecurityGroupRule deletes a security group rule by ID
+func DeleteSecurityGroupRule(cfg *config.Config, client *otc.Client, unscopedToken, projectID, ruleID string) error {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, true)
+	if err != nil {
+		return err
+	}
+
+	ruleURL := fmt.Sprintf("%s/v1/%s/security-group-rules/%s", client.Endpoint("vpc", projectID), projectID, ruleID)
+	_, err = client.Do("DELETE", ruleURL, projectToken, nil)
+	return err
+}
+
+// SensitivePorts are services that should never be reachable from the whole
+// internet. AuditSecurityGroups flags ingress rules that open them.
+var SensitivePorts = map[int]string{
+	21:    "FTP",
+	22:    "SSH",
+	23:    "Telnet",
+	135:   "MS RPC",
+	139:   "NetBIOS",
+	445:   "SMB",
+	1433:  "MS SQL",
+	1521:  "Oracle",
+	2375:  "Docker",
+	2376:  "Docker TLS",
+	2379:  "etcd",
+	3306:  "MySQL",
+	3389:  "RDP",
+	5432:  "PostgreSQL",
+	5900:  "VNC",
+	6379:  "Redis",
+	6443:  "Kubernetes API",
+	9200:  "Elasticsearch",
+	10250: "Kubelet",
+	11211: "Memcached",
+	27017: "MongoDB",
+}
+
+// AuditFinding is a rule that opens sensitive ports to the internet
+type AuditFinding struct {
+	Group    string            `json:"security_group"`
+	GroupID  string            `json:"security_group_id"`
+	Rule     SecurityGroupRule `json:"rule"`
+	Services []string          `json:"services"`
+}
+
+func (AuditFinding) Columns(wide bool) []string {
+	columns := []string{"Group", "Protocol", "Ports", "Remote", "Exposes"}
+	if wide {
+		columns = append(columns, "Group ID", "Rule ID")
+	}
+	return columns
+}
+
+func (f AuditFinding) Cells(wide bool) []string {
+	cells := []string{f.Group, f.Rule.ProtocolName(), f.Rule.Ports(), f.Rule.Remote(), strings.Join(f.Services, ", ")}
+	if wide {
+		cells = append(cells, f.GroupID, f.Rule.ID)
+	}
+	return cells
+}
+
+// AuditSecurityGroups checks the given security groups, or all of them, for
+// ingress rules that open sensitive ports to 0.0.0.0/0 or ::/0
+func AuditSecurityGroups(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, namesOrIDs []string, quiet bool) (*output.List[AuditFinding], error) {
+	var groups []SecurityGroup
+	if len(namesOrIDs) == 0 {
+		list, err := ListSecurityGroups(cfg, client, unscopedToken, projectID, nil, quiet)
+		if err != nil {
+			return nil, err
+		}
+		groups = list.Items
+	}
+	for _, nameOrID := range namesOrIDs {
+		group, err := GetSecurityGroup(cfg, client, unscopedToken, projectID, nameOrID, quiet)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, *group)
+	}
+
+	var findings []AuditFinding
+	for _, group := range groups {
+		sortRules(group.Rules)
+		for _, rule := range group.Rules {
+			if services := exposedServices(rule); len(services) > 0 {
+				findings = append(findings, AuditFinding{Group: group.Name, GroupID: group.ID, Rule: rule, Services: services})
+			}
+		}
+	}
+
+	list := output.NewList(findings, "findings")
+	list.Title = fmt.Sprintf("Audited %d security group(s) for sensitive ports open to the internet", len(groups))
+	return list, nil
+}
+
+// exposedServices returns the sensitive services a rule opens to the internet
+func exposedServices(rule SecurityGroupRule) []string {
+	if !rule.openToInternet() {
+		return nil
+	}
+	switch strings.ToLower(rule.Protocol) {
+	case "", "tcp", "udp":
+	default:
+		return nil
+	}
+
+	low, high := rule.portRange()
+	if low == 1 && high == 65535 {
+		return []string{"all ports"}
+	}
+
+	var ports []int
+	for port := range SensitivePorts {
+		if port >= low && port <= high {
+			ports = append(ports, port)
+		}
+	}
+	sort.Ints(ports)
+
+	services := make([]string, len(ports))
+	for i, port := range ports {
+		services[i] = fmt.Sprintf("%s (%d)", SensitivePorts[port], port)
+	}
+	return services
+}
+
+// sortRules orders rules by direction (ingress first), protocol and port
+func sortRules(rules []SecurityGroupRule) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		a, b := rules[i], rules[j]
+		if a.Direction != b.Direction {
+			return a.Direction == "ingress"
+		}
+		if a.ProtocolName() != b.ProtocolName() {
+			return a.ProtocolName() < b.ProtocolName()
+		}
+		lowA, _ := a.portRange()
+		lowB, _ := b.portRange()
+		return lowA < lowB
+	})
+}
+
+func securityGroupsURL(client *otc.Client, projectID string) string {
+	return fmt.Sprintf("%s/v1/%s/security-groups", client.Endpoint("vpc", projectID), projectID)
+}
@@ -0,0 +1,62 @@
+package resource
+
+import (
+	"strings"
+	"testing"
+)
+
+func intPtr(i int) *int { return &i }
+
+func TestSecurityGroupRuleRendering(t *testing.T) {
+	tests := []struct {
+		name       string
+		rule       SecurityGroupRule
+		wantPorts  string
+		wantRemote string
+	}{
+		{"all ports", SecurityGroupRule{Ethertype: "IPv4"}, "any", "0.0.0.0/0"},
+		{"single port", SecurityGroupRule{Ethertype: "IPv4", PortRangeMin: intPtr(22), PortRangeMax: intPtr(22), RemoteIPPrefix: "10.0.0.0/8"}, "22", "10.0.0.0/8"},
+		{"port range", SecurityGroupRule{Ethertype: "IPv4", PortRangeMin: intPtr(8000), PortRangeMax: intPtr(8100)}, "8000-8100", "0.0.0.0/0"},
+		{"IPv6 any", SecurityGroupRule{Ethertype: "IPv6", PortRangeMin: intPtr(443), PortRangeMax: intPtr(443)}, "443", "::/0"},
+		{"remote group", SecurityGroupRule{Ethertype: "IPv4", RemoteGroupID: "sg-1"}, "any", "group sg-1"},
+		{"named remote group", SecurityGroupRule{Ethertype: "IPv4", RemoteGroupID: "sg-1", RemoteGroupName: "web"}, "any", "group web"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Ports(); got != tt.wantPorts {
+				t.Errorf("Ports() = %q, want %q", got, tt.wantPorts)
+			}
+			if got := tt.rule.Remote(); got != tt.wantRemote {
+				t.Errorf("Remote() = %q, want %q", got, tt.wantRemote)
+			}
+		})
+	}
+}
+
+func TestExposedServices(t *testing.T) {
+	tests := []struct {
+		name string
+		rule SecurityGroupRule
+		want string // services joined by ", "
+	}{
+		{"all ports", SecurityGroupRule{Direction: "ingress", Ethertype: "IPv4"}, "all ports"},
+		{"single port", SecurityGroupRule{Direction: "ingress", Protocol: "tcp", PortRangeMin: intPtr(22), PortRangeMax: intPtr(22), RemoteIPPrefix: "0.0.0.0/0"}, "SSH (22)"},
+		{"port range", SecurityGroupRule{Direction: "ingress", Protocol: "tcp", PortRangeMin: intPtr(3300), PortRangeMax: intPtr(3400)}, "MySQL (3306), RDP (3389)"},
+		{"IPv6 any", SecurityGroupRule{Direction: "ingress", Ethertype: "IPv6", Protocol: "tcp", PortRangeMin: intPtr(6379), PortRangeMax: intPtr(6379), RemoteIPPrefix: "::/0"}, "Redis (6379)"},
+		{"harmless port", SecurityGroupRule{Direction: "ingress", Protocol: "tcp", PortRangeMin: intPtr(443), PortRangeMax: intPtr(443)}, ""},
+		{"private CIDR", SecurityGroupRule{Direction: "ingress", Protocol: "tcp", PortRangeMin: intPtr(22), PortRangeMax: intPtr(22), RemoteIPPrefix: "10.0.0.0/8"}, ""},
+		{"remote group", SecurityGroupRule{Direction: "ingress", Protocol: "tcp", PortRangeMin: intPtr(22), PortRangeMax: intPtr(22), RemoteGroupID: "sg-1"}, ""},
+		{"remote group all ports", SecurityGroupRule{Direction: "ingress", RemoteGroupID: "sg-1"}, ""},
+		{"egress", SecurityGroupRule{Direction: "egress", Protocol: "tcp", PortRangeMin: intPtr(22), PortRangeMax: intPtr(22)}, ""},
+		{"icmp", SecurityGroupRule{Direction: "ingress", Protocol: "icmp"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Join(exposedServices(tt.rule), ", "); got != tt.want {
+				t.Errorf("exposedServices() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abdo-farag/otc-cli/internal/config"
+	"github.com/abdo-farag/otc-cli/internal/otc"
+	"github.com/abdo-farag/otc-cli/internal/output"
+)
+
+// Snapshot is a point-in-time copy of an EVS volume
+type Snapshot struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Status      string            `json:"status"`
+	Size        int               `json:"size"`
+	VolumeID    string            `json:"volume_id"`
+	CreatedAt   string            `json:"created_at"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+func (Snapshot) Columns(wide bool) []string {
+	columns := []string{"Name", "Size (GB)", "Status", "Volume", "Created"}
+	if wide {
+		columns = append(columns, "Tags", "ID")
+	}
+	return columns
+}
+
+func (s Snapshot) Cells(wide bool) []string {
+	cells := []string{output.OrDash(s.Name), strconv.Itoa(s.Size), s.Status, s.VolumeID, output.OrDash(s.CreatedAt)}
+	if wide {
+		cells = append(cells, output.OrDash(strings.Join(s.Tags(), ", ")), s.ID)
+	}
+	return cells
+}
+
+// Tags returns the metadata of the snapshot as sorted "key=value" strings
+func (s Snapshot) Tags() []string {
+	tags := make([]string, 0, len(s.Metadata))
+	for key, value := range s.Metadata {
+		tags = append(tags, key+"="+value)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// Created returns the creation time. EVS omits the time zone; it is UTC.
+func (s Snapshot) Created() time.Time {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999"} {
+		if t, err := time.Parse(layout, s.CreatedAt); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// ListSnapshots lists snapshots, optionally only those of one volume and/or
+// with a tag (key or key=value)
+func ListSnapshots(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, options map[string]interface{}, quiet bool) (*output.List[Snapshot], error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, quiet)
+	if err != nil {
+		return nil, err
+	}
+
+	volume, _ := options["volume"].(string)
+	tag, _ := options["tag"].(string)
+
+	snapshotURL := snapshotsURL(client, projectID) + "/detail"
+	if volume != "" {
+		v, err := ResolveVolume(cfg, client, unscopedToken, projectID, volume, true)
+		if err != nil {
+			return nil, err
+		}
+		snapshotURL += "?volume_id=" + v.ID
+	}
+
+	var keep func(Snapshot) bool
+	if tag != "" {
+		keep = func(s Snapshot) bool { return hasTag(s.Tags(), tag) }
+	}
+
+	snapshots, streamed, err := listAll(client, snapshotURL, projectToken, otc.ListOptions{ItemsKey: "snapshots"}, options, keep)
+	if err != nil {
+		return nil, err
+	}
+
+	list := output.NewList(snapshots, "snapshots")
+	list.Streamed = streamed
+	list.Title = "Project: " + projectID
+	if volume != "" {
+		list.Notes = append(list.Notes, "Filter: Volume = "+volume)
+	}
+	if tag != "" {
+		list.Notes = append(list.Notes, "Filter: Tag = "+tag)
+	}
+	return list, nil
+}
+
+// GetSnapshot gets a snapshot by name or ID
+func GetSnapshot(cfg *config.Config, client *otc.Client, unscopedToken, projectID, nameOrID string, quiet bool) (*Snapshot, error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, quiet)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshotID, err := resolveID(client, snapshotsURL(client, projectID)+"/detail", projectToken, "snapshots", "snapshot", nameOrID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Snapshot Snapshot `json:"snapshot"`
+	}
+	if err := client.Get(snapshotsURL(client, projectID)+"/"+snapshotID, projectToken, &result); err != nil {
+		return nil, err
+	}
+	return &result.Snapshot, nil
+}
+
+// SnapshotSpec describes a snapshot to create
+type SnapshotSpec struct {
+	Name        string
+	Description string
+	Tags        map[string]string
+	Force       bool // Snapshot a volume that is in use
+}
+
+// CreateSnapshot starts creating a snapshot of a volume. The snapshot is
+// usable once its status is available.
+func CreateSnapshot(cfg *config.Config, client *otc.Client, unscopedToken, projectID, volumeID string, spec SnapshotSpec) (*Snapshot, error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := map[string]interface{}{
+		"volume_id": volumeID,
+		"name":      spec.Name,
+		"force":     spec.Force,
+	}
+	if spec.Description != "" {
+		snapshot["description"] = spec.Description
+	}
+	if len(spec.Tags) > 0 {
+		snapshot["metadata"] = spec.Tags
+	}
+
+	body, err := client.Do("POST", cloudSnapshotsURL(client, projectID), projectToken, map[string]interface{}{"snapshot": snapshot})
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Snapshot Snapshot `json:"snapshot"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &result.Snapshot, nil
+}
+
+// RestoreSnapshot rolls a volume back to a snapshot. The volume must not be attached.
+func RestoreSnapshot(cfg *config.Config, client *otc.Client, unscopedToken, projectID, snapshotID, volumeID string) error {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, true)
+	if err != nil {
+		return err
+	}
+
+	rollbackURL := fmt.Sprintf("%s/%s/rollback", cloudSnapshotsURL(client, projectID), snapshotID)
+	_, err = client.Do("POST", rollbackURL, projectToken, map[string]interface{}{
+		"rollback": map[string]string{"volume_id": volumeID},
+	})
+	return err
+}
+
+// DeleteSnapshot deletes a snapshot by ID
+func DeleteSnapshot(cfg *config.Config, client *otc.Client, unscopedToken, projectID, snapshotID string) error {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, true)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Do("DELETE", cloudSnapshotsURL(client, projectID)+"/"+snapshotID, projectToken, nil)
+	return err
+}
+
+// PrunePolicy selects the snapshots PruneCandidates returns
+type PrunePolicy struct {
+	OlderThan time.Duration // Only snapshots older than this; 0 for any age
+	Keep      int           // Always keep this many of the newest snapshots per volume
+}
+
+// PruneCandidates returns the snapshots the policy allows to delete, oldest
+// first. Snapshots are grouped by volume; the Keep newest of each volume are
+// kept, and of the rest those younger than OlderThan. Only available
+// snapshots with a known age count; others are left alone.
+func PruneCandidates(snapshots []Snapshot, policy PrunePolicy, now time.Time) []Snapshot {
+	byVolume := map[string][]Snapshot{}
+	for _, s := range snapshots {
+		if s.Status == "available" && !s.Created().IsZero() {
+			byVolume[s.VolumeID] = append(byVolume[s.VolumeID], s)
+		}
+	}
+
+	var candidates []Snapshot
+	for _, group := range byVolume {
+		sort.Slice(group, func(i, j int) bool { return group[i].Created().After(group[j].Created()) })
+		for i, s := range group {
+			if i < policy.Keep {
+				continue
+			}
+			if policy.OlderThan > 0 && now.Sub(s.Created()) < policy.OlderThan {
+				continue
+			}
+			candidates = append(candidates, s)
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Created().Before(candidates[j].Created()) })
+	return candidates
+}
+
+// snapshotsURL is the OpenStack-compatible API, which pages by marker
+func snapshotsURL(client *otc.Client, projectID string) string {
+	return fmt.Sprintf("%s/v2/%s/snapshots", client.Endpoint("evs", projectID), projectID)
+}
+
+// cloudSnapshotsURL is the EVS API for changes
+func cloudSnapshotsURL(client *otc.Client, projectID string) string {
+	return fmt.Sprintf("%s/v2/%s/cloudsnapshots", client.Endpoint("evs", projectID), projectID)
+}
@@ -0,0 +1,93 @@
+package resource
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPruneCandidates(t *testing.T) {
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	snapshot := func(id, volume string, age time.Duration) Snapshot {
+		created := now.Add(-age).Format("2006-01-02T15:04:05.000000")
+		return Snapshot{ID: id, VolumeID: volume, Status: "available", CreatedAt: created}
+	}
+
+	tests := []struct {
+		name      string
+		snapshots []Snapshot
+		policy    PrunePolicy
+		want      []string
+	}{
+		{
+			name: "keep the newest per volume",
+			snapshots: []Snapshot{
+				snapshot("a1", "a", 1*day), snapshot("a2", "a", 2*day), snapshot("a3", "a", 3*day),
+				snapshot("b1", "b", 5*day), snapshot("b2", "b", 6*day),
+			},
+			policy: PrunePolicy{Keep: 2},
+			want:   []string{"a3"},
+		},
+		{
+			name: "older-than boundary",
+			snapshots: []Snapshot{
+				snapshot("younger", "a", 30*day-time.Second),
+				snapshot("exact", "a", 30*day),
+				snapshot("older", "a", 31*day),
+			},
+			policy: PrunePolicy{OlderThan: 30 * day},
+			want:   []string{"older", "exact"},
+		},
+		{
+			name: "keep and older-than combined",
+			snapshots: []Snapshot{
+				snapshot("a1", "a", 40*day), snapshot("a2", "a", 50*day),
+				snapshot("b1", "b", 1*day), snapshot("b2", "b", 35*day),
+			},
+			policy: PrunePolicy{OlderThan: 30 * day, Keep: 1},
+			want:   []string{"a2", "b2"},
+		},
+		{
+			name: "only available snapshots",
+			snapshots: []Snapshot{
+				snapshot("available", "a", 10*day),
+				{ID: "creating", VolumeID: "a", Status: "creating", CreatedAt: now.Add(-20 * day).Format(time.RFC3339)},
+				{ID: "error", VolumeID: "a", Status: "error", CreatedAt: now.Add(-30 * day).Format(time.RFC3339)},
+			},
+			policy: PrunePolicy{OlderThan: day},
+			want:   []string{"available"},
+		},
+		{
+			name: "unparsable created_at",
+			snapshots: []Snapshot{
+				snapshot("dated", "a", 10*day),
+				{ID: "empty", VolumeID: "a", Status: "available"},
+				{ID: "garbage", VolumeID: "a", Status: "available", CreatedAt: "last tuesday"},
+			},
+			policy: PrunePolicy{OlderThan: day},
+			want:   []string{"dated"},
+		},
+		{
+			name: "undated snapshots don't count towards keep",
+			snapshots: []Snapshot{
+				snapshot("a1", "a", 10*day), snapshot("a2", "a", 20*day),
+				{ID: "undated", VolumeID: "a", Status: "available", CreatedAt: "?"},
+			},
+			policy: PrunePolicy{Keep: 1},
+			want:   []string{"a2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, s := range PruneCandidates(tt.snapshots, tt.policy, now) {
+				got = append(got, s.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PruneCandidates() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
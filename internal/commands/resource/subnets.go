@@ -1,95 +1,91 @@
 package resource
 
 import (
-	"encoding/json"
 	"fmt"
+
 	"github.com/abdo-farag/otc-cli/internal/config"
 	"github.com/abdo-farag/otc-cli/internal/otc"
-
-	"github.com/fatih/color"
-	"github.com/rodaine/table"
+	"github.com/abdo-farag/otc-cli/internal/output"
 )
 
-// ListSubnet lists all subnets
-func ListSubnet(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, raw bool) {
-	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, raw)
-	if err != nil {
-		color.Red("✗ %v", err)
-		return
-	}
-
-	subnetURL := fmt.Sprintf("https://vpc.%s.otc.t-systems.com/v1/%s/subnets", cfg.Region, projectID)
+// Subnet is a VPC subnet
+type Subnet struct {
+	ID               string `json:"id"`
+	Name             string `json:"name"`
+	CIDR             string `json:"cidr"`
+	GatewayIP        string `json:"gateway_ip"`
+	VpcID            string `json:"vpc_id"`
+	AvailabilityZone string `json:"availability_zone,omitempty"`
+	Status           string `json:"status"`
+	DhcpEnable       bool   `json:"dhcp_enable"`
+	PrimaryDNS       string `json:"primary_dns,omitempty"`
+	SecondaryDNS     string `json:"secondary_dns,omitempty"`
+	NeutronSubnetID  string `json:"neutron_subnet_id,omitempty"`
+	NeutronNetworkID string `json:"neutron_network_id,omitempty"`
+}
 
-	body, statusCode, err := MakeRequest(subnetURL, projectToken)
-	if err != nil {
-		color.Red("✗ Request failed: %v", err)
-		return
+func (Subnet) Columns(wide bool) []string {
+	if wide {
+		return []string{"ID", "Name", "CIDR", "Gateway", "VPC ID", "Status", "AZ", "DNS"}
 	}
+	return []string{"ID", "Name", "CIDR", "Gateway", "VPC ID", "Status"}
+}
 
-	if statusCode != 200 {
-		color.Red("✗ API error (status %d): %s", statusCode, string(body))
-		return
+func (s Subnet) Cells(wide bool) []string {
+	if wide {
+		dns := s.PrimaryDNS
+		if s.SecondaryDNS != "" {
+			dns += ", " + s.SecondaryDNS
+		}
+		return []string{s.ID, s.Name, s.CIDR, s.GatewayIP, s.VpcID, s.Status, output.OrDash(s.AvailabilityZone), output.OrDash(dns)}
 	}
+	return []string{s.ID, s.Name, s.CIDR, s.GatewayIP, s.VpcID, s.Status}
+}
 
-	if raw {
-		var prettyJSON map[string]interface{}
-		json.Unmarshal(body, &prettyJSON)
-		formatted, _ := json.MarshalIndent(prettyJSON, "", "  ")
-		fmt.Println(string(formatted))
-		return
+// ListSubnet lists all subnets
+func ListSubnet(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, options map[string]interface{}, quiet bool) (*output.List[Subnet], error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, quiet)
+	if err != nil {
+		return nil, err
 	}
 
-	var result struct {
-		Subnets []struct {
-			ID               string `json:"id"`
-			Name             string `json:"name"`
-			CIDR             string `json:"cidr"`
-			GatewayIP        string `json:"gateway_ip"`
-			VpcID            string `json:"vpc_id"`
-			AvailabilityZone string `json:"availability_zone"`
-			Status           string `json:"status"`
-		} `json:"subnets"`
-	}
+	subnetURL := fmt.Sprintf("%s/v1/%s/subnets", client.Endpoint("vpc", projectID), projectID)
 
-	json.Unmarshal(body, &result)
+	subnets, streamed, err := listAll[Subnet](client, subnetURL, projectToken, otc.ListOptions{ItemsKey: "subnets"}, options, nil)
+	if err != nil {
+		return nil, err
+	}
 
-	headerFmt := color.New(color.FgCyan, color.Bold).SprintfFunc()
-	tbl := table.New("ID", "Name", "CIDR", "Gateway", "VPC ID", "Status")
-	tbl.WithHeaderFormatter(headerFmt)
+	list := output.NewList(subnets, "subnets")
+	list.Streamed = streamed
+	list.Title = "Project: " + projectID
+	return list, nil
+}
 
-	for _, s := range result.Subnets {
-		tbl.AddRow(s.ID, s.Name, s.CIDR, s.GatewayIP, s.VpcID, s.Status)
+// ResolveSubnet gets a subnet by name or ID
+func ResolveSubnet(cfg *config.Config, client *otc.Client, unscopedToken, projectID, nameOrID string, quiet bool) (*Subnet, error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, quiet)
+	if err != nil {
+		return nil, err
 	}
-
-	fmt.Printf("\n")
-	color.Cyan("Project: %s", projectID)
-	tbl.Print()
-	fmt.Printf("\nTotal: %d subnets\n", len(result.Subnets))
+	return resolveSubnet(client, projectID, projectToken, "", nameOrID)
 }
 
 // GetSubnet gets a specific subnet
-func GetSubnet(cfg *config.Config, client *otc.Client, unscopedToken, projectID, resourceID string, raw bool) {
-	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, raw)
+func GetSubnet(cfg *config.Config, client *otc.Client, unscopedToken, projectID, resourceID string, quiet bool) (*Subnet, error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, quiet)
 	if err != nil {
-		color.Red("✗ %v", err)
-		return
+		return nil, err
 	}
 
-	subnetURL := fmt.Sprintf("https://vpc.%s.otc.t-systems.com/v1/%s/subnets/%s", cfg.Region, projectID, resourceID)
+	subnetURL := fmt.Sprintf("%s/v1/%s/subnets/%s", client.Endpoint("vpc", projectID), projectID, resourceID)
 
-	body, statusCode, err := MakeRequest(subnetURL, projectToken)
-	if err != nil {
-		color.Red("✗ Request failed: %v", err)
-		return
+	var result struct {
+		Subnet Subnet `json:"subnet"`
 	}
-
-	if statusCode != 200 {
-		color.Red("✗ API error (status %d): %s", statusCode, string(body))
-		return
+	if err := client.Get(subnetURL, projectToken, &result); err != nil {
+		return nil, err
 	}
 
-	var prettyJSON map[string]interface{}
-	json.Unmarshal(body, &prettyJSON)
-	formatted, _ := json.MarshalIndent(prettyJSON, "", "  ")
-	fmt.Println(string(formatted))
+	return &result.Subnet, nil
 }
@@ -0,0 +1,147 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/abdo-farag/otc-cli/internal/config"
+	"github.com/abdo-farag/otc-cli/internal/otc"
+)
+
+// VolumeSpec describes a volume to create
+type VolumeSpec struct {
+	Name             string
+	Size             int    // GB
+	Type             string // SATA, SAS, SSD, GPSSD, ...
+	AvailabilityZone string
+	Description      string
+}
+
+// ResolveVolume finds a volume by ID or exact name
+func ResolveVolume(cfg *config.Config, client *otc.Client, unscopedToken, projectID, nameOrID string, quiet bool) (*Volume, error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, quiet)
+	if err != nil {
+		return nil, err
+	}
+
+	volumeURL := fmt.Sprintf("%s/v2/%s/volumes/detail", client.Endpoint("evs", projectID), projectID)
+	volumes, _, err := listAll[Volume](client, volumeURL, projectToken, otc.ListOptions{ItemsKey: "volumes"}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Volume
+	for _, v := range volumes {
+		if v.ID == nameOrID {
+			return &v, nil
+		}
+		if v.Name == nameOrID {
+			matches = append(matches, v)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("volume %s %w", nameOrID, otc.ErrNotFound)
+	case 1:
+		return &matches[0], nil
+	}
+	return nil, fmt.Errorf("volume name %s matches %d volumes, use the ID instead", nameOrID, len(matches))
+}
+
+// CreateVolume starts creating a volume. It returns the ID of the EVS job
+// and the ID of the new volume.
+func CreateVolume(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, spec VolumeSpec) (string, string, error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, true)
+	if err != nil {
+		return "", "", err
+	}
+
+	volume := map[string]interface{}{
+		"name":              spec.Name,
+		"size":              spec.Size,
+		"volume_type":       spec.Type,
+		"availability_zone": spec.AvailabilityZone,
+		"count":             1,
+	}
+	if spec.Description != "" {
+		volume["description"] = spec.Description
+	}
+
+	createURL := fmt.Sprintf("%s/v2/%s/cloudvolumes", client.Endpoint("evs", projectID), projectID)
+	body, err := client.Do("POST", createURL, projectToken, map[string]interface{}{"volume": volume})
+	if err != nil {
+		return "", "", err
+	}
+
+	var result struct {
+		JobID     string   `json:"job_id"`
+		VolumeIDs []string `json:"volume_ids"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	if result.JobID == "" {
+		return "", "", fmt.Errorf("no job_id in response")
+	}
+
+	volumeID := ""
+	if len(result.VolumeIDs) > 0 {
+		volumeID = result.VolumeIDs[0]
+	}
+	return result.JobID, volumeID, nil
+}
+
+// AttachVolume attaches a volume to a server and returns the ID of the ECS
+// job. An empty device lets ECS pick the next free one.
+func AttachVolume(cfg *config.Config, client *otc.Client, unscopedToken, projectID, volumeID, serverID, device string) (string, error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, true)
+	if err != nil {
+		return "", err
+	}
+
+	attachment := map[string]string{"volumeId": volumeID}
+	if device != "" {
+		attachment["device"] = device
+	}
+
+	attachURL := fmt.Sprintf("%s/v1/%s/cloudservers/%s/attachvolume", client.Endpoint("ecs", projectID), projectID, serverID)
+	return startJob(client, "POST", attachURL, projectToken, map[string]interface{}{"volumeAttachment": attachment})
+}
+
+// DetachVolume detaches a volume from a server and returns the ID of the ECS job
+func DetachVolume(cfg *config.Config, client *otc.Client, unscopedToken, projectID, volumeID, serverID string) (string, error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, true)
+	if err != nil {
+		return "", err
+	}
+
+	detachURL := fmt.Sprintf("%s/v1/%s/cloudservers/%s/detachvolume/%s", client.Endpoint("ecs", projectID), projectID, serverID, volumeID)
+	return startJob(client, "DELETE", detachURL, projectToken, nil)
+}
+
+// ExtendVolume grows a volume to size GB and returns the ID of the EVS job
+func ExtendVolume(cfg *config.Config, client *otc.Client, unscopedToken, projectID, volumeID string, size int) (string, error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, true)
+	if err != nil {
+		return "", err
+	}
+
+	// Only the v2.1 API returns a job for the extension
+	extendURL := fmt.Sprintf("%s/v2.1/%s/cloudvolumes/%s/action", client.Endpoint("evs", projectID), projectID, volumeID)
+	payload := map[string]interface{}{
+		"os-extend": map[string]int{"new_size": size},
+	}
+	return startJob(client, "POST", extendURL, projectToken, payload)
+}
+
+// DeleteVolume deletes a volume and returns the ID of the EVS job
+func DeleteVolume(cfg *config.Config, client *otc.Client, unscopedToken, projectID, volumeID string) (string, error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, true)
+	if err != nil {
+		return "", err
+	}
+
+	deleteURL := fmt.Sprintf("%s/v2/%s/cloudvolumes/%s", client.Endpoint("evs", projectID), projectID, volumeID)
+	return startJob(client, "DELETE", deleteURL, projectToken, nil)
+}
@@ -1,104 +1,115 @@
 package resource
 
 import (
-	"encoding/json"
 	"fmt"
+	"strconv"
+
 	"github.com/abdo-farag/otc-cli/internal/config"
 	"github.com/abdo-farag/otc-cli/internal/otc"
-
-	"github.com/fatih/color"
-	"github.com/rodaine/table"
+	"github.com/abdo-farag/otc-cli/internal/output"
 )
 
-// ListVolume lists all volumes
-func ListVolume(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, raw bool) {
-	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, raw)
-	if err != nil {
-		color.Red("✗ %v", err)
-		return
-	}
+// Volume is an EVS disk
+type Volume struct {
+	ID               string             `json:"id"`
+	Name             string             `json:"name"`
+	Status           string             `json:"status"`
+	Size             int                `json:"size"`
+	VolumeType       string             `json:"volume_type"`
+	AvailabilityZone string             `json:"availability_zone"`
+	Bootable         string             `json:"bootable,omitempty"`
+	Multiattach      bool               `json:"multiattach"`
+	CreatedAt        string             `json:"created_at,omitempty"`
+	Attachments      []VolumeAttachment `json:"attachments"`
+	Metadata         map[string]string  `json:"metadata,omitempty"`
+}
 
-	volumeURL := fmt.Sprintf("https://evs.%s.otc.t-systems.com/v2/%s/volumes/detail", cfg.Region, projectID)
+// VolumeAttachment links a volume to a server
+type VolumeAttachment struct {
+	ServerID     string `json:"server_id"`
+	Device       string `json:"device"`
+	AttachmentID string `json:"attachment_id,omitempty"`
+}
 
-	body, statusCode, err := MakeRequest(volumeURL, projectToken)
-	if err != nil {
-		color.Red("✗ Request failed: %v", err)
-		return
+func (Volume) Columns(wide bool) []string {
+	columns := []string{"Name", "Size (GB)", "Type", "Status", "Attached To", "Device", "AZ"}
+	if wide {
+		columns = append(columns, "Bootable", "Created", "ID")
 	}
+	return columns
+}
 
-	if statusCode != 200 {
-		color.Red("✗ API error (status %d): %s", statusCode, string(body))
-		return
+func (v Volume) Cells(wide bool) []string {
+	serverID := ""
+	device := ""
+	if len(v.Attachments) > 0 {
+		serverID = v.Attachments[0].ServerID
+		device = v.Attachments[0].Device
 	}
 
-	if raw {
-		var prettyJSON map[string]interface{}
-		json.Unmarshal(body, &prettyJSON)
-		formatted, _ := json.MarshalIndent(prettyJSON, "", "  ")
-		fmt.Println(string(formatted))
-		return
+	cells := []string{v.Name, strconv.Itoa(v.Size), v.VolumeType, v.Status, serverID, device, v.AvailabilityZone}
+	if wide {
+		cells = append(cells, output.OrDash(v.Bootable), output.OrDash(v.CreatedAt), v.ID)
 	}
+	return cells
+}
 
-	var result struct {
-		Volumes []struct {
-			ID               string `json:"id"`
-			Name             string `json:"name"`
-			Status           string `json:"status"`
-			Size             int    `json:"size"`
-			VolumeType       string `json:"volume_type"`
-			AvailabilityZone string `json:"availability_zone"`
-			Attachments      []struct {
-				ServerID string `json:"server_id"`
-				Device   string `json:"device"`
-			} `json:"attachments"`
-		} `json:"volumes"`
+// GrowsTo reports whether extending the volume to size GB grows it, and
+// fails if it would shrink: volumes can't shrink
+func (v Volume) GrowsTo(size int) (bool, error) {
+	if size < v.Size {
+		return false, fmt.Errorf("volume %s has %d GB and can't shrink to %d GB", v.Name, v.Size, size)
 	}
+	return size > v.Size, nil
+}
 
-	json.Unmarshal(body, &result)
+// ListVolume lists all volumes
+func ListVolume(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, options map[string]interface{}, quiet bool) (*output.List[Volume], error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, quiet)
+	if err != nil {
+		return nil, err
+	}
 
-	headerFmt := color.New(color.FgCyan, color.Bold).SprintfFunc()
-	tbl := table.New("Name", "Size (GB)", "Type", "Status", "Attached To", "Device", "AZ")
-	tbl.WithHeaderFormatter(headerFmt)
+	volumeURL := fmt.Sprintf("%s/v2/%s/volumes/detail", client.Endpoint("evs", projectID), projectID)
 
-	for _, v := range result.Volumes {
-		serverID := ""
-		device := ""
-		if len(v.Attachments) > 0 {
-			serverID = v.Attachments[0].ServerID
-			device = v.Attachments[0].Device
+	// Orphaned volumes are available and attached to nobody
+	var keep func(Volume) bool
+	orphaned, _ := options["orphaned"].(bool)
+	if orphaned {
+		keep = func(v Volume) bool {
+			return v.Status == "available" && len(v.Attachments) == 0
 		}
-		tbl.AddRow(v.Name, v.Size, v.VolumeType, v.Status, serverID, device, v.AvailabilityZone)
 	}
 
-	fmt.Printf("\n")
-	color.Cyan("Project: %s", projectID)
-	tbl.Print()
-	fmt.Printf("\nTotal: %d volumes\n", len(result.Volumes))
+	volumes, streamed, err := listAll(client, volumeURL, projectToken, otc.ListOptions{ItemsKey: "volumes"}, options, keep)
+	if err != nil {
+		return nil, err
+	}
+
+	list := output.NewList(volumes, "volumes")
+	list.Streamed = streamed
+	list.Title = "Project: " + projectID
+	if orphaned {
+		list.Notes = append(list.Notes, "Filter: orphaned (available, not attached)")
+	}
+	return list, nil
 }
 
 // GetVolume gets a specific volume
-func GetVolume(cfg *config.Config, client *otc.Client, unscopedToken, projectID, resourceID string, raw bool) {
-	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, raw)
+func GetVolume(cfg *config.Config, client *otc.Client, unscopedToken, projectID, resourceID string, quiet bool) (*Volume, error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, quiet)
 	if err != nil {
-		color.Red("✗ %v", err)
-		return
+		return nil, err
 	}
 
-	volumeURL := fmt.Sprintf("https://evs.%s.otc.t-systems.com/v2/%s/volumes/%s", cfg.Region, projectID, resourceID)
+	volumeURL := fmt.Sprintf("%s/v2/%s/volumes/%s", client.Endpoint("evs", projectID), projectID, resourceID)
 
-	body, statusCode, err := MakeRequest(volumeURL, projectToken)
-	if err != nil {
-		color.Red("✗ Request failed: %v", err)
-		return
+	var result struct {
+		Volume Volume `json:"volume"`
 	}
-
-	if statusCode != 200 {
-		color.Red("✗ API error (status %d): %s", statusCode, string(body))
-		return
+	if err := client.Get(volumeURL, projectToken, &result); err != nil {
+		return nil, err
 	}
 
-	var prettyJSON map[string]interface{}
-	json.Unmarshal(body, &prettyJSON)
-	formatted, _ := json.MarshalIndent(prettyJSON, "", "  ")
-	fmt.Println(string(formatted))
+	return &result.Volume, nil
 }
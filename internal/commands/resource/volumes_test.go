@@ -0,0 +1,106 @@
+package resource
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/abdo-farag/otc-cli/internal/config"
+	"github.com/abdo-farag/otc-cli/internal/otc"
+)
+
+func TestListVolumeOrphaned(t *testing.T) {
+	srv := newIAMStub(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/p1/volumes/detail" {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, `{"volumes":[
+			{"id":"v1","name":"orphan","status":"available","attachments":[]},
+			{"id":"v2","name":"data","status":"in-use","attachments":[{"server_id":"s1","device":"/dev/vdb"}]},
+			{"id":"v3","name":"broken","status":"error","attachments":[]},
+			{"id":"v4","name":"spare","status":"available"}]}`)
+	})
+
+	cfg := &config.Config{AUTHURL: srv.URL, Endpoints: map[string]string{"evs": srv.URL}}
+	for orphaned, want := range map[bool][]string{
+		false: {"orphan", "data", "broken", "spare"},
+		true:  {"orphan", "spare"},
+	} {
+		list, err := ListVolume(cfg, otc.NewClient(cfg), "token", "p1", map[string]interface{}{"orphaned": orphaned}, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, v := range list.Items {
+			names = append(names, v.Name)
+		}
+		if !reflect.DeepEqual(names, want) {
+			t.Errorf("orphaned=%v: got %v, want %v", orphaned, names, want)
+		}
+	}
+}
+
+func TestVolumeGrowsTo(t *testing.T) {
+	volume := Volume{Name: "data", Size: 100}
+
+	tests := []struct {
+		size      int
+		wantGrows bool
+		wantErr   bool
+	}{
+		{150, true, false},
+		{100, false, false},
+		{50, false, true},
+	}
+
+	for _, tt := range tests {
+		grows, err := volume.GrowsTo(tt.size)
+		if grows != tt.wantGrows || (err != nil) != tt.wantErr {
+			t.Errorf("GrowsTo(%d) = %v, %v; want %v with error %v", tt.size, grows, err, tt.wantGrows, tt.wantErr)
+		}
+	}
+}
+
+func TestVolumeActionRequests(t *testing.T) {
+	var requests []string
+	var bodies []map[string]interface{}
+	srv := newIAMStub(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+		io.WriteString(w, `{"job_id":"j1"}`)
+	})
+
+	cfg := &config.Config{AUTHURL: srv.URL, Endpoints: map[string]string{"ecs": srv.URL, "evs": srv.URL}}
+	client := otc.NewClient(cfg)
+
+	if _, err := AttachVolume(cfg, client, "token", "p1", "v1", "s1", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DetachVolume(cfg, client, "token", "p1", "v1", "s1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ExtendVolume(cfg, client, "token", "p1", "v1", 200); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"POST /v1/p1/cloudservers/s1/attachvolume",
+		"DELETE /v1/p1/cloudservers/s1/detachvolume/v1",
+		"POST /v2.1/p1/cloudvolumes/v1/action",
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Fatalf("requests = %q, want %q", requests, want)
+	}
+	// Without a device ECS picks the next free one
+	if attachment, _ := bodies[0]["volumeAttachment"].(map[string]interface{}); attachment["volumeId"] != "v1" || attachment["device"] != nil {
+		t.Errorf("attach body = %v, want volumeId v1 and no device", bodies[0])
+	}
+	if extend, _ := bodies[2]["os-extend"].(map[string]interface{}); extend["new_size"] != 200.0 {
+		t.Errorf("extend body = %v, want new_size 200", bodies[2])
+	}
+}
@@ -1,92 +1,83 @@
 package resource
 
 import (
-	"encoding/json"
 	"fmt"
+
 	"github.com/abdo-farag/otc-cli/internal/config"
 	"github.com/abdo-farag/otc-cli/internal/otc"
-
-	"github.com/fatih/color"
-	"github.com/rodaine/table"
+	"github.com/abdo-farag/otc-cli/internal/output"
 )
 
-// ListVPC lists all VPCs
-func ListVPC(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, raw bool) {
-	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, raw)
-	if err != nil {
-		color.Red("✗ %v", err)
-		return
-	}
-
-	vpcURL := fmt.Sprintf("https://vpc.%s.otc.t-systems.com/v1/%s/vpcs", cfg.Region, projectID)
+// VPC is a Virtual Private Cloud
+type VPC struct {
+	ID                  string `json:"id"`
+	Name                string `json:"name"`
+	CIDR                string `json:"cidr"`
+	Status              string `json:"status"`
+	Description         string `json:"description,omitempty"`
+	EnterpriseProjectID string `json:"enterprise_project_id,omitempty"`
+}
 
-	body, statusCode, err := MakeRequest(vpcURL, projectToken)
-	if err != nil {
-		color.Red("✗ Request failed: %v", err)
-		return
+func (VPC) Columns(wide bool) []string {
+	if wide {
+		return []string{"Name", "ID", "CIDR", "Status", "Description"}
 	}
+	return []string{"Name", "ID", "CIDR", "Status"}
+}
 
-	if statusCode != 200 {
-		color.Red("✗ API error (status %d): %s", statusCode, string(body))
-		return
+func (v VPC) Cells(wide bool) []string {
+	if wide {
+		return []string{v.Name, v.ID, v.CIDR, v.Status, output.OrDash(v.Description)}
 	}
+	return []string{v.Name, v.ID, v.CIDR, v.Status}
+}
 
-	if raw {
-		var prettyJSON map[string]interface{}
-		json.Unmarshal(body, &prettyJSON)
-		formatted, _ := json.MarshalIndent(prettyJSON, "", "  ")
-		fmt.Println(string(formatted))
-		return
+// ListVPC lists all VPCs
+func ListVPC(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, options map[string]interface{}, quiet bool) (*output.List[VPC], error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, quiet)
+	if err != nil {
+		return nil, err
 	}
 
-	var result struct {
-		VPCs []struct {
-			ID     string `json:"id"`
-			Name   string `json:"name"`
-			CIDR   string `json:"cidr"`
-			Status string `json:"status"`
-		} `json:"vpcs"`
-	}
+	vpcURL := fmt.Sprintf("%s/v1/%s/vpcs", client.Endpoint("vpc", projectID), projectID)
 
-	json.Unmarshal(body, &result)
+	vpcs, streamed, err := listAll[VPC](client, vpcURL, projectToken, otc.ListOptions{ItemsKey: "vpcs"}, options, nil)
+	if err != nil {
+		return nil, err
+	}
 
-	headerFmt := color.New(color.FgCyan, color.Bold).SprintfFunc()
-	tbl := table.New("Name", "ID", "CIDR", "Status")
-	tbl.WithHeaderFormatter(headerFmt)
+	list := output.NewList(vpcs, "VPCs")
+	list.Streamed = streamed
+	list.Title = "Project: " + projectID
+	return list, nil
+}
 
-	for _, v := range result.VPCs {
-		tbl.AddRow(v.Name, v.ID, v.CIDR, v.Status)
+// ResolveVPC returns the ID of the VPC with the given name or ID
+func ResolveVPC(cfg *config.Config, client *otc.Client, unscopedToken, projectID, nameOrID string, quiet bool) (string, error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, quiet)
+	if err != nil {
+		return "", err
 	}
 
-	fmt.Printf("\n")
-	color.Cyan("Project: %s", projectID)
-	tbl.Print()
-	fmt.Printf("\nTotal: %d VPCs\n", len(result.VPCs))
+	vpcURL := fmt.Sprintf("%s/v1/%s/vpcs", client.Endpoint("vpc", projectID), projectID)
+	return resolveID(client, vpcURL, projectToken, "vpcs", "VPC", nameOrID)
 }
 
 // GetVPC gets a specific VPC
-func GetVPC(cfg *config.Config, client *otc.Client, unscopedToken, projectID, resourceID string, raw bool) {
-	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, raw)
+func GetVPC(cfg *config.Config, client *otc.Client, unscopedToken, projectID, resourceID string, quiet bool) (*VPC, error) {
+	projectID, projectToken, err := GetProjectToken(cfg, client, unscopedToken, projectID, quiet)
 	if err != nil {
-		color.Red("✗ %v", err)
-		return
+		return nil, err
 	}
 
-	vpcURL := fmt.Sprintf("https://vpc.%s.otc.t-systems.com/v1/%s/vpcs/%s", cfg.Region, projectID, resourceID)
+	vpcURL := fmt.Sprintf("%s/v1/%s/vpcs/%s", client.Endpoint("vpc", projectID), projectID, resourceID)
 
-	body, statusCode, err := MakeRequest(vpcURL, projectToken)
-	if err != nil {
-		color.Red("✗ Request failed: %v", err)
-		return
+	var result struct {
+		VPC VPC `json:"vpc"`
 	}
-
-	if statusCode != 200 {
-		color.Red("✗ API error (status %d): %s", statusCode, string(body))
-		return
+	if err := client.Get(vpcURL, projectToken, &result); err != nil {
+		return nil, err
 	}
 
-	var prettyJSON map[string]interface{}
-	json.Unmarshal(body, &prettyJSON)
-	formatted, _ := json.MarshalIndent(prettyJSON, "", "  ")
-	fmt.Println(string(formatted))
+	return &result.VPC, nil
 }
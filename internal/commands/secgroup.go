@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/abdo-farag/otc-cli/internal/commands/resource"
+	"github.com/abdo-farag/otc-cli/internal/config"
+	"github.com/abdo-farag/otc-cli/internal/otc"
+	"github.com/abdo-farag/otc-cli/internal/output"
+
+	"github.com/fatih/color"
+)
+
+// GetSecurityGroup prints a security group followed by its rules
+func GetSecurityGroup(cfg *config.Config, client *otc.Client, unscopedToken, projectID, nameOrID string, printer *output.Printer) error {
+	group, err := resource.GetSecurityGroup(cfg, client, unscopedToken, projectID, nameOrID, !printer.Human())
+	if err != nil {
+		return err
+	}
+	return printSecurityGroup(printer, group)
+}
+
+// CreateSecurityGroup creates a security group and prints it with its default rules
+func CreateSecurityGroup(cfg *config.Config, client *otc.Client, unscopedToken, projectID, name, vpc, description string, printer *output.Printer) error {
+	group, err := resource.CreateSecurityGroup(cfg, client, unscopedToken, projectID, name, vpc, description)
+	if err != nil {
+		return err
+	}
+
+	if printer.Human() {
+		color.Green("✓ Created security group %s (%s)", group.Name, group.ID)
+	}
+	return printSecurityGroup(printer, group)
+}
+
+// AddSecurityGroupRule adds a rule to a security group and prints it
+func AddSecurityGroupRule(cfg *config.Config, client *otc.Client, unscopedToken, projectID, group string, spec resource.RuleSpec, printer *output.Printer) error {
+	rule, err := resource.AddSecurityGroupRule(cfg, client, unscopedToken, projectID, group, spec)
+	if err != nil {
+		return err
+	}
+
+	if printer.Human() {
+		color.Green("✓ Added rule %s to %s", rule.ID, group)
+	}
+	return printResult(printer, rule, nil)
+}
+
+// DeleteSecurityGroupRules deletes rules by ID after asking for confirmation
+// unless yes is set
+func DeleteSecurityGroupRules(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, ruleIDs []string, yes bool) error {
+	if !yes {
+		if err := confirm(fmt.Sprintf("Delete %d security group rule(s)?", len(ruleIDs))); err != nil {
+			return err
+		}
+	}
+
+	for _, id := range ruleIDs {
+		if err := resource.DeleteSecurityGroupRule(cfg, client, unscopedToken, projectID, id); err != nil {
+			return fmt.Errorf("failed to delete rule %s: %w", id, err)
+		}
+		color.Green("✓ Deleted rule %s", id)
+	}
+	return nil
+}
+
+// AuditSecurityGroups prints the rules that open sensitive ports to the internet
+func AuditSecurityGroups(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, namesOrIDs []string, printer *output.Printer) error {
+	findings, err := resource.AuditSecurityGroups(cfg, client, unscopedToken, projectID, namesOrIDs, !printer.Human())
+	if err != nil {
+		return err
+	}
+
+	if printer.Human() && len(findings.Items) == 0 {
+		color.Green("✓ No sensitive ports open to the internet")
+		return nil
+	}
+	return printResult(printer, findings, nil)
+}
+
+// printSecurityGroup prints a group and, for people, its rules as a table
+func printSecurityGroup(printer *output.Printer, group *resource.SecurityGroup) error {
+	if err := printResult(printer, group, nil); err != nil {
+		return err
+	}
+
+	if printer.Human() && len(group.Rules) > 0 {
+		rules := output.NewList(group.Rules, "rules")
+		rules.Title = "Rules"
+		return printResult(printer, rules, nil)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/abdo-farag/otc-cli/internal/commands/resource"
+	"github.com/abdo-farag/otc-cli/internal/config"
+	"github.com/abdo-farag/otc-cli/internal/otc"
+	"github.com/abdo-farag/otc-cli/internal/output"
+
+	"github.com/fatih/color"
+)
+
+// CreateSnapshot snapshots a volume given by name or ID and waits until the
+// snapshot is available. In-use volumes get a crash-consistent snapshot.
+func CreateSnapshot(cfg *config.Config, client *otc.Client, unscopedToken, projectID, volumeNameOrID string, spec resource.SnapshotSpec, jobOpts JobOptions) error {
+	volume, err := resource.ResolveVolume(cfg, client, unscopedToken, projectID, volumeNameOrID, false)
+	if err != nil {
+		return err
+	}
+
+	if spec.Name == "" {
+		prefix := volume.Name
+		if prefix == "" {
+			prefix = "snapshot"
+		}
+		spec.Name = fmt.Sprintf("%s-%s", prefix, time.Now().UTC().Format("20060102-150405"))
+	}
+	if volume.Status == "in-use" {
+		spec.Force = true
+		color.Yellow("Volume %s is in use; flush or freeze its file system for a consistent snapshot", volume.Name)
+	}
+
+	snapshot, err := resource.CreateSnapshot(cfg, client, unscopedToken, projectID, volume.ID, spec)
+	if err != nil {
+		return err
+	}
+
+	if jobOpts.NoWait {
+		color.Cyan("✓ Snapshot %s (%s) is being created; follow it with: otc-cli wait snapshot %s --for status=available", snapshot.Name, snapshot.ID, snapshot.ID)
+		return nil
+	}
+
+	cond := WaitCondition{Status: "available"}
+	return WaitCommand(cfg, client, unscopedToken, "snapshot", snapshot.ID, projectID, cond, jobOpts.Timeout)
+}
+
+// RestoreSnapshot rolls a volume back to a snapshot after asking for
+// confirmation unless yes is set. The volume defaults to the snapshot's own.
+func RestoreSnapshot(cfg *config.Config, client *otc.Client, unscopedToken, projectID, snapshotNameOrID, volumeNameOrID string, yes bool) error {
+	snapshot, err := resource.GetSnapshot(cfg, client, unscopedToken, projectID, snapshotNameOrID, false)
+	if err != nil {
+		return err
+	}
+	if snapshot.Status != "available" {
+		return fmt.Errorf("snapshot %s is %s, only available snapshots can be restored", snapshot.Name, snapshot.Status)
+	}
+
+	if volumeNameOrID == "" {
+		volumeNameOrID = snapshot.VolumeID
+	}
+	volume, err := resource.ResolveVolume(cfg, client, unscopedToken, projectID, volumeNameOrID, true)
+	if err != nil {
+		return err
+	}
+	if len(volume.Attachments) > 0 {
+		return fmt.Errorf("volume %s is attached to server %s, detach it first", volume.Name, volume.Attachments[0].ServerID)
+	}
+
+	if !yes {
+		question := fmt.Sprintf("Overwrite volume %s with snapshot %s from %s?", volume.Name, snapshot.Name, snapshot.CreatedAt)
+		if err := confirm(question); err != nil {
+			return err
+		}
+	}
+
+	if err := resource.RestoreSnapshot(cfg, client, unscopedToken, projectID, snapshot.ID, volume.ID); err != nil {
+		return err
+	}
+
+	color.Green("✓ Restoring %s from %s; wait for it with: otc-cli wait volume %s --for status=available", volume.Name, snapshot.Name, volume.ID)
+	return nil
+}
+
+// DeleteSnapshots deletes snapshots given by name or ID after asking for
+// confirmation unless yes is set
+func DeleteSnapshots(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, namesOrIDs []string, yes bool) error {
+	var snapshots []resource.Snapshot
+	for _, nameOrID := range namesOrIDs {
+		snapshot, err := resource.GetSnapshot(cfg, client, unscopedToken, projectID, nameOrID, len(snapshots) > 0)
+		if err != nil {
+			return err
+		}
+		snapshots = append(snapshots, *snapshot)
+	}
+
+	return deleteSnapshots(cfg, client, unscopedToken, projectID, snapshots, yes, false)
+}
+
+// PruneOptions selects the snapshots PruneSnapshots deletes
+type PruneOptions struct {
+	resource.PrunePolicy
+	Volume string // Only snapshots of this volume (name or ID)
+	Tag    string // Only snapshots with this tag (key or key=value)
+	DryRun bool   // Only print what would be deleted
+}
+
+// PruneSnapshots deletes old snapshots while keeping the newest of each
+// volume. With DryRun it only prints exactly what would be deleted; in
+// machine-readable formats it prints what was deleted.
+func PruneSnapshots(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, opts PruneOptions, yes bool, printer *output.Printer) error {
+	options := map[string]interface{}{"volume": opts.Volume, "tag": opts.Tag}
+	all, err := resource.ListSnapshots(cfg, client, unscopedToken, projectID, options, !printer.Human())
+	if err != nil {
+		return err
+	}
+
+	candidates := resource.PruneCandidates(all.Items, opts.PrunePolicy, time.Now())
+	if len(candidates) == 0 && printer.Human() {
+		color.Green("✓ Nothing to prune (%d snapshots checked)", len(all.Items))
+		return nil
+	}
+
+	if opts.DryRun || len(candidates) == 0 {
+		list := output.NewList(candidates, "snapshots")
+		list.Title = "Nothing to prune"
+		if opts.DryRun {
+			list.Title = "Dry run: would delete"
+		}
+		list.Notes = all.Notes
+		return printResult(printer, list, nil)
+	}
+
+	if err := deleteSnapshots(cfg, client, unscopedToken, projectID, candidates, yes, !printer.Human()); err != nil {
+		return err
+	}
+	if printer.Human() {
+		return nil
+	}
+
+	list := output.NewList(candidates, "snapshots")
+	list.Title = "Deleted"
+	return printResult(printer, list, nil)
+}
+
+// deleteSnapshots deletes snapshots after asking for confirmation unless yes
+// is set. quiet suppresses the progress messages, but not the list of
+// snapshots a confirmation is asked for.
+func deleteSnapshots(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, snapshots []resource.Snapshot, yes, quiet bool) error {
+	if !quiet || !yes {
+		color.Cyan("Snapshots to delete:")
+		for _, s := range snapshots {
+			fmt.Printf("  %s (%s) of volume %s, %s\n", output.OrDash(s.Name), s.ID, s.VolumeID, s.CreatedAt)
+		}
+	}
+
+	if !yes {
+		if err := confirm(fmt.Sprintf("Permanently delete %d snapshot(s)?", len(snapshots))); err != nil {
+			return err
+		}
+	}
+
+	for _, s := range snapshots {
+		if err := resource.DeleteSnapshot(cfg, client, unscopedToken, projectID, s.ID); err != nil {
+			return fmt.Errorf("failed to delete snapshot %s: %w", s.ID, err)
+		}
+		if !quiet {
+			color.Green("✓ Deleted %s", output.OrDash(s.Name))
+		}
+	}
+	return nil
+}
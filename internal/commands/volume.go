@@ -0,0 +1,205 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/abdo-farag/otc-cli/internal/commands/resource"
+	"github.com/abdo-farag/otc-cli/internal/config"
+	"github.com/abdo-farag/otc-cli/internal/otc"
+	"github.com/abdo-farag/otc-cli/internal/otc/jobs"
+
+	"github.com/fatih/color"
+)
+
+// CreateVolume creates a volume and waits for the EVS job
+func CreateVolume(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, spec resource.VolumeSpec, jobOpts JobOptions) error {
+	color.Yellow("⏳ Creating %d GB %s volume %s in %s...", spec.Size, spec.Type, spec.Name, spec.AvailabilityZone)
+
+	jobID, volumeID, err := resource.CreateVolume(cfg, client, unscopedToken, projectID, spec)
+	if err != nil {
+		return err
+	}
+
+	job, err := followJob(cfg, client, unscopedToken, projectID, jobs.EVS, jobID, jobOpts)
+	if err != nil {
+		return err
+	}
+	if volumeID == "" && job != nil {
+		volumeID = job.Resource
+	}
+
+	if job != nil {
+		color.Green("✓ Volume %s created", spec.Name)
+	}
+	if volumeID != "" {
+		color.Cyan("  Volume ID: %s", volumeID)
+	}
+	return nil
+}
+
+// AttachVolume attaches a volume to a server, both given by name or ID, and
+// waits for the ECS job
+func AttachVolume(cfg *config.Config, client *otc.Client, unscopedToken, projectID, volumeNameOrID, serverNameOrID, device string, jobOpts JobOptions) error {
+	volume, err := resource.ResolveVolume(cfg, client, unscopedToken, projectID, volumeNameOrID, false)
+	if err != nil {
+		return err
+	}
+	servers, err := resource.ResolveServers(cfg, client, unscopedToken, projectID, []string{serverNameOrID}, "", true)
+	if err != nil {
+		return err
+	}
+	server := servers[0]
+
+	for _, a := range volume.Attachments {
+		if a.ServerID == server.ID {
+			color.Green("✓ Volume %s is already attached to %s as %s", volume.Name, server.Name, a.Device)
+			return nil
+		}
+	}
+	if volume.Status != "available" && !volume.Multiattach {
+		return fmt.Errorf("volume %s is %s, only available volumes can be attached", volume.Name, volume.Status)
+	}
+	if volume.AvailabilityZone != server.AvailabilityZone {
+		return fmt.Errorf("volume %s is in %s but server %s in %s", volume.Name, volume.AvailabilityZone, server.Name, server.AvailabilityZone)
+	}
+
+	jobID, err := resource.AttachVolume(cfg, client, unscopedToken, projectID, volume.ID, server.ID, device)
+	if err != nil {
+		return err
+	}
+
+	job, err := followJob(cfg, client, unscopedToken, projectID, jobs.ECS, jobID, jobOpts)
+	if err != nil || job == nil {
+		return err
+	}
+
+	color.Green("✓ Attached %s to %s", volume.Name, server.Name)
+	return nil
+}
+
+// DetachVolume detaches a volume from a server and waits for the ECS job.
+// serverNameOrID may be empty unless the volume is attached to several servers.
+func DetachVolume(cfg *config.Config, client *otc.Client, unscopedToken, projectID, volumeNameOrID, serverNameOrID string, yes bool, jobOpts JobOptions) error {
+	volume, err := resource.ResolveVolume(cfg, client, unscopedToken, projectID, volumeNameOrID, false)
+	if err != nil {
+		return err
+	}
+	if len(volume.Attachments) == 0 {
+		color.Green("✓ Volume %s is not attached", volume.Name)
+		return nil
+	}
+
+	attachment := volume.Attachments[0]
+	serverName := attachment.ServerID
+	if serverNameOrID != "" {
+		servers, err := resource.ResolveServers(cfg, client, unscopedToken, projectID, []string{serverNameOrID}, "", true)
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, a := range volume.Attachments {
+			if a.ServerID == servers[0].ID {
+				attachment, found = a, true
+			}
+		}
+		if !found {
+			return fmt.Errorf("volume %s is not attached to %s", volume.Name, serverNameOrID)
+		}
+		serverName = servers[0].Name
+	} else if len(volume.Attachments) > 1 {
+		return fmt.Errorf("volume %s is attached to %d servers, name the server to detach it from", volume.Name, len(volume.Attachments))
+	}
+
+	if !yes {
+		question := fmt.Sprintf("Detach %s (%s) from %s? Unmount it first.", volume.Name, attachment.Device, serverName)
+		if err := confirm(question); err != nil {
+			return err
+		}
+	}
+
+	jobID, err := resource.DetachVolume(cfg, client, unscopedToken, projectID, volume.ID, attachment.ServerID)
+	if err != nil {
+		return err
+	}
+
+	job, err := followJob(cfg, client, unscopedToken, projectID, jobs.ECS, jobID, jobOpts)
+	if err != nil || job == nil {
+		return err
+	}
+
+	color.Green("✓ Detached %s from %s", volume.Name, serverName)
+	return nil
+}
+
+// ExtendVolume grows a volume and waits for the EVS job. Volumes can't shrink.
+func ExtendVolume(cfg *config.Config, client *otc.Client, unscopedToken, projectID, volumeNameOrID string, size int, jobOpts JobOptions) error {
+	volume, err := resource.ResolveVolume(cfg, client, unscopedToken, projectID, volumeNameOrID, false)
+	if err != nil {
+		return err
+	}
+
+	grows, err := volume.GrowsTo(size)
+	if err != nil {
+		return err
+	}
+	if !grows {
+		color.Green("✓ Volume %s already has %d GB", volume.Name, size)
+		return nil
+	}
+
+	jobID, err := resource.ExtendVolume(cfg, client, unscopedToken, projectID, volume.ID, size)
+	if err != nil {
+		return err
+	}
+
+	job, err := followJob(cfg, client, unscopedToken, projectID, jobs.EVS, jobID, jobOpts)
+	if err != nil || job == nil {
+		return err
+	}
+
+	color.Green("✓ Extended %s from %d to %d GB; grow the file system to use the space", volume.Name, volume.Size, size)
+	return nil
+}
+
+// DeleteVolumes deletes volumes given by name or ID after asking for
+// confirmation unless yes is set, and waits for each EVS job
+func DeleteVolumes(cfg *config.Config, client *otc.Client, unscopedToken, projectID string, namesOrIDs []string, yes bool, jobOpts JobOptions) error {
+	var volumes []*resource.Volume
+	for _, nameOrID := range namesOrIDs {
+		volume, err := resource.ResolveVolume(cfg, client, unscopedToken, projectID, nameOrID, len(volumes) > 0)
+		if err != nil {
+			return err
+		}
+		if len(volume.Attachments) > 0 {
+			return fmt.Errorf("volume %s is attached to server %s, detach it first", volume.Name, volume.Attachments[0].ServerID)
+		}
+		volumes = append(volumes, volume)
+	}
+
+	color.Cyan("Volumes to delete:")
+	for _, v := range volumes {
+		fmt.Printf("  %s (%s) %d GB %s\n", v.Name, v.ID, v.Size, v.Status)
+	}
+
+	if !yes {
+		if err := confirm(fmt.Sprintf("Permanently delete %d volume(s)?", len(volumes))); err != nil {
+			return err
+		}
+	}
+
+	for _, v := range volumes {
+		jobID, err := resource.DeleteVolume(cfg, client, unscopedToken, projectID, v.ID)
+		if err != nil {
+			return fmt.Errorf("failed to delete %s: %w", v.Name, err)
+		}
+
+		job, err := followJob(cfg, client, unscopedToken, projectID, jobs.EVS, jobID, jobOpts)
+		if err != nil {
+			return err
+		}
+		if job != nil {
+			color.Green("✓ Deleted %s", v.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/abdo-farag/otc-cli/internal/commands/resource"
+	"github.com/abdo-farag/otc-cli/internal/config"
+	"github.com/abdo-farag/otc-cli/internal/otc"
+	"github.com/abdo-farag/otc-cli/internal/output"
+
+	"github.com/fatih/color"
+)
+
+// Polling defaults of WaitCommand
+const (
+	DefaultWaitTimeout = 10 * time.Minute
+	waitInterval       = 2 * time.Second
+	maxWaitInterval    = 30 * time.Second
+)
+
+// WaitCondition is the state WaitCommand waits for
+type WaitCondition struct {
+	Status string // Status to reach, compared case-insensitively
+	Delete bool   // Wait until the resource is gone instead
+}
+
+// ParseWaitCondition parses a --for value: status=VALUE or delete
+func ParseWaitCondition(s string) (WaitCondition, error) {
+	if strings.EqualFold(s, "delete") {
+		return WaitCondition{Delete: true}, nil
+	}
+	if key, value, ok := strings.Cut(s, "="); ok && strings.EqualFold(key, "status") && value != "" {
+		return WaitCondition{Status: value}, nil
+	}
+	return WaitCondition{}, fmt.Errorf("invalid condition %q: expected status=VALUE or delete", s)
+}
+
+func (c WaitCondition) String() string {
+	if c.Delete {
+		return "deletion"
+	}
+	return "status " + c.Status
+}
+
+// WaitCommand polls a resource through the get paths until it meets cond. It
+// fails when the timeout passes or the resource enters an error state.
+func WaitCommand(cfg *config.Config, client *otc.Client, unscopedToken, resourceType, resourceID, projectID string, cond WaitCondition, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = DefaultWaitTimeout
+	}
+
+	// Names are resolved once, so a 404 later on means the resource waited
+	// for is gone and not that the name never matched
+	resolved := false
+	if !resource.IsUUID(resourceID) {
+		id, err := resolveResourceID(cfg, client, unscopedToken, resourceType, resourceID, projectID)
+		if err != nil {
+			return err
+		}
+		resourceID, resolved = id, true
+	}
+
+	deadline := time.Now().Add(timeout)
+	interval := waitInterval
+
+	color.Yellow("⏳ Waiting for %s of %s %s...", cond, resourceType, resourceID)
+
+	status := ""
+	for polls := 0; ; polls++ {
+		result, err := getResource(cfg, client, unscopedToken, resourceType, resourceID, projectID, resolved || polls > 0)
+		switch {
+		case cond.Delete && errors.Is(err, otc.ErrNotFound):
+			color.Green("✓ %s %s deleted", resourceType, resourceID)
+			return nil
+		case err != nil:
+			return err
+		}
+
+		current := resourceStatus(result)
+		if current != status {
+			status = current
+			fmt.Printf("  %s %s: %s\n", resourceType, resourceID, output.OrDash(status))
+		}
+
+		// ECS keeps returning deleted servers with status DELETED for a while
+		if cond.Delete && strings.EqualFold(status, "DELETED") {
+			color.Green("✓ %s %s deleted", resourceType, resourceID)
+			return nil
+		}
+		if !cond.Delete && strings.EqualFold(status, cond.Status) {
+			color.Green("✓ %s %s is %s", resourceType, resourceID, status)
+			return nil
+		}
+		if isErrorStatus(status) && !strings.EqualFold(status, cond.Status) {
+			return fmt.Errorf("%s %s %w: status %s", resourceType, resourceID, otc.ErrFailed, status)
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("waiting for %s of %s %s %w after %s (status %s)", cond, resourceType, resourceID, otc.ErrTimeout, timeout, output.OrDash(status))
+		}
+		if err := client.Sleep(min(interval, time.Until(deadline)+time.Second)); err != nil {
+			return err
+		}
+		interval = min(interval*3/2, maxWaitInterval)
+	}
+}
+
+// resolveResourceID returns the ID of the resource with the given name or ID
+func resolveResourceID(cfg *config.Config, client *otc.Client, unscopedToken, resourceType, nameOrID, projectID string) (string, error) {
+	switch resourceType {
+	case "ecs", "server":
+		servers, err := resource.ResolveServers(cfg, client, unscopedToken, projectID, []string{nameOrID}, "", false)
+		if err != nil {
+			return "", err
+		}
+		return servers[0].ID, nil
+	case "vpc":
+		return resource.ResolveVPC(cfg, client, unscopedToken, projectID, nameOrID, false)
+	case "subnet":
+		subnet, err := resource.ResolveSubnet(cfg, client, unscopedToken, projectID, nameOrID, false)
+		if err != nil {
+			return "", err
+		}
+		return subnet.ID, nil
+	case "volume":
+		volume, err := resource.ResolveVolume(cfg, client, unscopedToken, projectID, nameOrID, false)
+		if err != nil {
+			return "", err
+		}
+		return volume.ID, nil
+	case "cce", "cluster":
+		cluster, err := resource.ResolveCluster(cfg, client, unscopedToken, projectID, nameOrID, false)
+		if err != nil {
+			return "", err
+		}
+		return cluster.Metadata.UID, nil
+	}
+
+	// EIPs (also by address) and snapshots are looked up by name when read
+	result, err := getResource(cfg, client, unscopedToken, resourceType, nameOrID, projectID, false)
+	if err != nil {
+		return "", err
+	}
+	switch r := result.(type) {
+	case *resource.EIP:
+		return r.ID, nil
+	case *resource.Snapshot:
+		return r.ID, nil
+	}
+	return nameOrID, nil
+}
+
+// resourceStatus returns the status of a resource returned by getResource
+func resourceStatus(result interface{}) string {
+	switch r := result.(type) {
+	case *resource.Server:
+		return r.Status
+	case *resource.VPC:
+		return r.Status
+	case *resource.Subnet:
+		return r.Status
+	case *resource.Volume:
+		return r.Status
+	case *resource.EIP:
+		return r.Status
+	case *resource.Snapshot:
+		return r.Status
+	case *resource.Cluster:
+		return r.Status.Phase
+	}
+	return ""
+}
+
+// isErrorStatus reports whether a resource is broken and won't reach the
+// status waited for: ECS ERROR, EVS error_*, EIP BIND_ERROR, CCE Error and Unavailable
+func isErrorStatus(status string) bool {
+	status = strings.ToLower(status)
+	return strings.HasPrefix(status, "error") || strings.HasSuffix(status, "_error") || status == "unavailable"
+}
@@ -8,6 +8,9 @@ import (
 )
 
 type Config struct {
+	Profile             string // Name of the profile the config was loaded from
+	Project             string // Default project ID or name
+	TokenStore          string // Secret store backend: file, encrypted-file or keyring
 	IdpURL              string
 	IdpClientID         string
 	IDPProviderName     string
@@ -18,28 +21,78 @@ type Config struct {
 	RedirectPort        int
 	OutputFile          string
 	NoBrowser           bool
-	CodeChallengeMethod string // "S256" (default) or "plain"
-	Scope               string // OIDC scopes (default: "openid email profile roles groups organization offline_access")
+	DeviceFlow          bool              // Use the OAuth 2.0 Device Authorization Grant instead of a local callback
+	CodeChallengeMethod string            // "S256" (default) or "plain"
+	Scope               string            // OIDC scopes (default: "openid email profile roles groups organization offline_access")
+	Endpoints           map[string]string // Service base URL overrides from endpoints.<service> or OTC_ENDPOINT_<SERVICE>
 }
 
+// New builds the config from environment variables and defaults only
 func New() *Config {
-	region := getEnv("OS_REGION", "eu-de")
+	return newConfig("", &Profile{})
+}
+
+// Load builds the config with precedence env > profile > default.
+// The profile is selected by name, OTC_PROFILE or the file's current_profile;
+// only an explicitly requested profile must exist.
+func Load(profileName string) (*Config, error) {
+	file, err := LoadFile()
+	if err != nil {
+		return nil, err
+	}
+
+	name := file.ResolveProfileName(profileName)
+	profile, ok := file.Profiles[name]
+	if !ok {
+		if name != DefaultProfileName {
+			return nil, fmt.Errorf("profile %q not found in %s", name, FilePath())
+		}
+		profile = &Profile{}
+	}
+
+	return newConfig(name, profile), nil
+}
+
+func newConfig(profileName string, profile *Profile) *Config {
+	region := getEnv("OS_REGION", orDefault(profile.Region, "eu-de"))
 	return &Config{
-		IdpURL:              getEnv("IDP_URL", ""),
-		IdpClientID:         getEnv("IDP_CLIENT_ID", ""),
-		IDPProviderName:     getEnv("IDP_PROVIDER_NAME", ""),
-		IdpProtocol:         getEnv("IDP_PROTOCOL", "oidc"),
-		DomainName:          getEnv("OS_DOMAIN_NAME", ""),
-		AUTHURL:             getEnv("OS_AUTH_URL", getIAMEndpoint(region)),
+		Profile:             profileName,
+		Project:             getEnv("OTC_PROJECT", profile.Project),
+		TokenStore:          getEnv("OTC_TOKEN_STORE", orDefault(profile.TokenStore, "file")),
+		IdpURL:              getEnv("IDP_URL", profile.IdpURL),
+		IdpClientID:         getEnv("IDP_CLIENT_ID", profile.IdpClientID),
+		IDPProviderName:     getEnv("IDP_PROVIDER_NAME", profile.IdpProvider),
+		IdpProtocol:         getEnv("IDP_PROTOCOL", orDefault(profile.IdpProtocol, "oidc")),
+		DomainName:          getEnv("OS_DOMAIN_NAME", profile.DomainName),
+		AUTHURL:             getEnv("OS_AUTH_URL", orDefault(profile.AuthURL, getIAMEndpoint(region))),
 		Region:              region,
 		RedirectPort:        getEnvInt("REDIRECT_PORT", 9197),
 		OutputFile:          getEnv("OUTPUT_FILE", "otc-credentials"),
 		NoBrowser:           getEnvBool("NO_BROWSER", false),
+		DeviceFlow:          getEnvBool("OIDC_DEVICE_FLOW", false),
 		CodeChallengeMethod: getEnv("CODE_CHALLENGE_METHOD", "S256"),
 		Scope:               getEnv("OIDC_SCOPE", ""),
+		Endpoints:           endpointOverrides(profile.Endpoints),
 	}
 }
 
+// endpointOverrides merges OTC_ENDPOINT_<SERVICE> variables over the profile's endpoints
+func endpointOverrides(profile map[string]string) map[string]string {
+	endpoints := map[string]string{}
+	for service, url := range profile {
+		endpoints[service] = url
+	}
+
+	for _, env := range os.Environ() {
+		key, value, _ := strings.Cut(env, "=")
+		if service, ok := strings.CutPrefix(key, "OTC_ENDPOINT_"); ok && service != "" && value != "" {
+			endpoints[strings.ToLower(service)] = strings.TrimSuffix(value, "/")
+		}
+	}
+
+	return endpoints
+}
+
 func getIAMEndpoint(region string) string {
 	switch region {
 	case "eu-ch2":
@@ -49,6 +102,13 @@ func getIAMEndpoint(region string) string {
 	}
 }
 
+func orDefault(val, defaultVal string) string {
+	if val != "" {
+		return val
+	}
+	return defaultVal
+}
+
 func getEnv(key, defaultVal string) string {
 	if val := os.Getenv(key); val != "" {
 		return val
@@ -0,0 +1,198 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultProfileName is used when no profile is selected and none is current
+const DefaultProfileName = "default"
+
+// Profile holds the persistent settings for one OTC domain / IdP combination
+type Profile struct {
+	IdpURL      string `yaml:"idp_url,omitempty"`
+	IdpClientID string `yaml:"idp_client_id,omitempty"`
+	IdpProvider string `yaml:"idp_provider,omitempty"`
+	IdpProtocol string `yaml:"protocol,omitempty"`
+	DomainName  string `yaml:"domain,omitempty"`
+	Region      string `yaml:"region,omitempty"`
+	AuthURL     string `yaml:"auth_url,omitempty"`
+	Project     string `yaml:"project,omitempty"`
+	TokenStore  string `yaml:"token_store,omitempty"`
+
+	// Endpoints overrides service base URLs, e.g. ecs: https://ecs.example.com
+	Endpoints map[string]string `yaml:"endpoints,omitempty"`
+}
+
+// ProfileKeys lists the keys accepted by Profile.Get and Profile.Set
+var ProfileKeys = []string{"idp_url", "idp_client_id", "idp_provider", "protocol", "domain", "region", "auth_url", "project", "token_store", "endpoints.<service>"}
+
+// endpointPrefix selects a per-service endpoint override: endpoints.ecs
+const endpointPrefix = "endpoints."
+
+// File is the on-disk layout of ~/.otc-cli/config.yaml
+type File struct {
+	CurrentProfile string              `yaml:"current_profile,omitempty"`
+	Profiles       map[string]*Profile `yaml:"profiles"`
+}
+
+// FilePath returns the config file location (OTC_CLI_CONFIG overrides the default)
+func FilePath() string {
+	if path := os.Getenv("OTC_CLI_CONFIG"); path != "" {
+		return path
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".otc-cli", "config.yaml")
+}
+
+// LoadFile reads the config file. A missing file yields an empty config.
+func LoadFile() (*File, error) {
+	file := &File{Profiles: map[string]*Profile{}}
+
+	data, err := os.ReadFile(FilePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return file, nil
+		}
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(data, file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", FilePath(), err)
+	}
+
+	if file.Profiles == nil {
+		file.Profiles = map[string]*Profile{}
+	}
+
+	return file, nil
+}
+
+// Save writes the config file, creating its directory if needed
+func (f *File) Save() error {
+	data, err := yaml.Marshal(f)
+	if err != nil {
+		return err
+	}
+
+	path := FilePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0600)
+}
+
+// ProfileNames returns all profile names, sorted
+func (f *File) ProfileNames() []string {
+	names := make([]string, 0, len(f.Profiles))
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ResolveProfileName picks the active profile: explicit name > OTC_PROFILE > current_profile > default
+func (f *File) ResolveProfileName(name string) string {
+	if name != "" {
+		return name
+	}
+	if env := os.Getenv("OTC_PROFILE"); env != "" {
+		return env
+	}
+	if f.CurrentProfile != "" {
+		return f.CurrentProfile
+	}
+	return DefaultProfileName
+}
+
+// Get returns the value stored under key
+func (p *Profile) Get(key string) (string, error) {
+	if service, ok := strings.CutPrefix(key, endpointPrefix); ok && service != "" {
+		return p.Endpoints[service], nil
+	}
+
+	switch key {
+	case "idp_url":
+		return p.IdpURL, nil
+	case "idp_client_id":
+		return p.IdpClientID, nil
+	case "idp_provider":
+		return p.IdpProvider, nil
+	case "protocol":
+		return p.IdpProtocol, nil
+	case "domain":
+		return p.DomainName, nil
+	case "region":
+		return p.Region, nil
+	case "auth_url":
+		return p.AuthURL, nil
+	case "project":
+		return p.Project, nil
+	case "token_store":
+		return p.TokenStore, nil
+	}
+	return "", fmt.Errorf("unknown config key: %s (valid keys: %v)", key, ProfileKeys)
+}
+
+// Set stores value under key. An empty endpoint removes the override.
+func (p *Profile) Set(key, value string) error {
+	if service, ok := strings.CutPrefix(key, endpointPrefix); ok && service != "" {
+		return p.setEndpoint(service, value)
+	}
+
+	switch key {
+	case "idp_url":
+		p.IdpURL = value
+	case "idp_client_id":
+		p.IdpClientID = value
+	case "idp_provider":
+		p.IdpProvider = value
+	case "protocol":
+		if value != "" && value != "oidc" && value != "saml" {
+			return fmt.Errorf("invalid protocol: %s (must be oidc or saml)", value)
+		}
+		p.IdpProtocol = value
+	case "domain":
+		p.DomainName = value
+	case "region":
+		p.Region = value
+	case "auth_url":
+		p.AuthURL = value
+	case "project":
+		p.Project = value
+	case "token_store":
+		if value != "" && value != "file" && value != "encrypted-file" && value != "keyring" {
+			return fmt.Errorf("invalid token_store: %s (must be file, encrypted-file or keyring)", value)
+		}
+		p.TokenStore = value
+	default:
+		return fmt.Errorf("unknown config key: %s (valid keys: %v)", key, ProfileKeys)
+	}
+	return nil
+}
+
+func (p *Profile) setEndpoint(service, value string) error {
+	if value == "" {
+		delete(p.Endpoints, service)
+		return nil
+	}
+
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("invalid endpoint for %s: %s (must be an http(s) URL)", service, value)
+	}
+
+	if p.Endpoints == nil {
+		p.Endpoints = map[string]string{}
+	}
+	p.Endpoints[service] = strings.TrimSuffix(value, "/")
+	return nil
+}
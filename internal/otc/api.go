@@ -0,0 +1,301 @@
+package otc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Retry policy for throttled and failed requests
+const (
+	maxRetries    = 3
+	retryBaseWait = 500 * time.Millisecond
+	retryMaxWait  = 30 * time.Second
+)
+
+// APIError is a non-2xx response from an OTC service
+type APIError struct {
+	StatusCode int
+	Code       string // Service error code, e.g. "VPC.0202" or "Ecs.0114"
+	Message    string
+	RequestID  string
+	Method     string
+	URL        string
+	Body       string // Raw response body, for envelopes that couldn't be parsed
+}
+
+// Errors an *APIError matches with errors.Is, by status code. Other failures
+// of the same kind (e.g. a cluster missing from a list) wrap them directly.
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("permission denied")
+	ErrNotFound     = errors.New("not found")
+)
+
+var (
+	// ErrFailed is wrapped by errors for failed jobs and resources in an error state
+	ErrFailed = errors.New("failed")
+	// ErrTimeout is wrapped by errors returned when waiting times out
+	ErrTimeout = errors.New("timed out")
+)
+
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	}
+	return false
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = e.Body
+	}
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+
+	s := fmt.Sprintf("API error (status %d", e.StatusCode)
+	if e.Code != "" {
+		s += ", " + e.Code
+	}
+	s += "): " + msg
+	if e.RequestID != "" {
+		s += " (request ID: " + e.RequestID + ")"
+	}
+	return s
+}
+
+// newAPIError parses the error envelopes used across OTC services:
+// {"error_code", "error_msg"}, {"error": {"code", "message"}},
+// {"NeutronError": {"type", "message"}}, Nova's {"itemNotFound": {"code", "message"}}
+// and CCE's Kubernetes-style {"kind": "Status", "reason", "message"}.
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Method:     resp.Request.Method,
+		URL:        resp.Request.URL.String(),
+		RequestID:  requestID(resp.Header),
+	}
+
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		apiErr.Body = string(bytes.TrimSpace(body))
+		return apiErr
+	}
+
+	var flat struct {
+		ErrorCode    string          `json:"error_code"`
+		ErrorMsg     string          `json:"error_msg"`
+		Code         json.RawMessage `json:"code"`
+		Message      string          `json:"message"`
+		Reason       string          `json:"reason"`
+		RequestID    string          `json:"request_id"`
+		ErrorCodeAlt string          `json:"errorCode"`
+		ErrorMsgAlt  string          `json:"errorMessage"`
+	}
+	json.Unmarshal(body, &flat)
+
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = flat.RequestID
+	}
+
+	switch {
+	case flat.ErrorCode != "" || flat.ErrorMsg != "":
+		apiErr.Code, apiErr.Message = flat.ErrorCode, flat.ErrorMsg
+	case flat.ErrorCodeAlt != "" || flat.ErrorMsgAlt != "":
+		apiErr.Code, apiErr.Message = flat.ErrorCodeAlt, flat.ErrorMsgAlt
+	case flat.Message != "":
+		apiErr.Code, apiErr.Message = flat.Reason, flat.Message
+	default:
+		// A single wrapper object: "error", "NeutronError", "itemNotFound", ...
+		for _, raw := range envelope {
+			var inner struct {
+				Code    json.RawMessage `json:"code"`
+				Type    string          `json:"type"`
+				Message string          `json:"message"`
+				Title   string          `json:"title"`
+			}
+			if json.Unmarshal(raw, &inner) != nil || (inner.Message == "" && inner.Title == "") {
+				continue
+			}
+			apiErr.Message = inner.Message
+			if apiErr.Message == "" {
+				apiErr.Message = inner.Title
+			}
+			apiErr.Code = inner.Type
+			if code := rawString(inner.Code); inner.Type == "" && code != strconv.Itoa(resp.StatusCode) {
+				apiErr.Code = code
+			}
+			break
+		}
+	}
+
+	if apiErr.Message == "" {
+		apiErr.Body = string(bytes.TrimSpace(body))
+	}
+	return apiErr
+}
+
+// rawString returns a JSON string or number as text
+func rawString(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var n json.Number
+	if json.Unmarshal(raw, &n) == nil {
+		return n.String()
+	}
+	return ""
+}
+
+func requestID(header http.Header) string {
+	for _, name := range []string{"X-Request-Id", "X-Openstack-Request-Id", "X-Compute-Request-Id"} {
+		if id := header.Get(name); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// SetContext sets the context that cancels in-flight requests and retries
+func (c *Client) SetContext(ctx context.Context) {
+	c.ctx = ctx
+}
+
+// Context returns the context set with SetContext, or context.Background()
+func (c *Client) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// Do sends an authenticated request with a JSON body (if payload is not nil)
+// and returns the response body. Non-2xx responses are returned as *APIError.
+// An empty token sends the request without X-Auth-Token, for public APIs.
+func (c *Client) Do(method, url, token string, payload interface{}) ([]byte, error) {
+	var data []byte
+	if payload != nil {
+		var err error
+		if data, err = json.Marshal(payload); err != nil {
+			return nil, err
+		}
+	}
+
+	headers := map[string]string{}
+	if token != "" {
+		headers["X-Auth-Token"] = token
+	}
+	_, body, err := c.send(method, url, headers, data)
+	return body, err
+}
+
+// Get sends an authenticated GET request and decodes the JSON response into v
+func (c *Client) Get(url, token string, v interface{}) error {
+	body, err := c.Do("GET", url, token, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// send performs a request, retrying throttled (429) and failed (5xx, network)
+// requests with exponential backoff and jitter. Only throttled requests are
+// retried for POST, since the service may already have acted on them.
+func (c *Client) send(method, url string, headers map[string]string, payload []byte) (*http.Response, []byte, error) {
+	ctx := c.Context()
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(payload))
+		if err != nil {
+			return nil, nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			if ctx.Err() != nil || attempt >= maxRetries || method == "POST" {
+				return nil, nil, fmt.Errorf("request failed: %w", err)
+			}
+			if err := sleep(ctx, backoff(attempt, "")); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read response: %w", err)
+		}
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return resp, body, nil
+		}
+
+		retryable := resp.StatusCode == http.StatusTooManyRequests ||
+			(resp.StatusCode >= 500 && method != "POST")
+		if !retryable || attempt >= maxRetries {
+			return resp, body, newAPIError(resp, body)
+		}
+
+		if err := sleep(ctx, backoff(attempt, resp.Header.Get("Retry-After"))); err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+// backoff returns the wait before the next attempt: Retry-After if the
+// service sent one, otherwise exponential backoff with full jitter
+func backoff(attempt int, retryAfter string) time.Duration {
+	if retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			return min(time.Duration(seconds)*time.Second, retryMaxWait)
+		}
+		if at, err := http.ParseTime(retryAfter); err == nil {
+			return min(max(time.Until(at), 0), retryMaxWait)
+		}
+	}
+
+	wait := min(retryBaseWait<<attempt, retryMaxWait)
+	return time.Duration(rand.Int63n(int64(wait)) + 1)
+}
+
+// Sleep waits for d, returning early with an error if the client's context is cancelled
+func (c *Client) Sleep(d time.Duration) error {
+	return sleep(c.Context(), d)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
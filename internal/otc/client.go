@@ -1,11 +1,12 @@
 package otc
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"github.com/abdo-farag/otc-cli/internal/cache"
 	"github.com/abdo-farag/otc-cli/internal/config"
 	"time"
 
@@ -15,6 +16,8 @@ import (
 type Client struct {
 	cfg        *config.Config
 	httpClient *http.Client
+	session    *cache.TokenCache
+	ctx        context.Context
 }
 
 type Project struct {
@@ -36,7 +39,30 @@ func NewClient(cfg *config.Config) *Client {
 	}
 }
 
-func (c *Client) GetUnscopedToken(idToken string, statusSetter ValidationStatusSetter) (string, error) {
+// UseSession makes the client reuse and store scoped tokens in the cached session,
+// so repeated commands don't re-scope the unscoped token every time
+func (c *Client) UseSession(session *cache.TokenCache) {
+	c.session = session
+}
+
+// sessionFor returns the cached session if it belongs to unscopedToken
+func (c *Client) sessionFor(unscopedToken string) *cache.TokenCache {
+	if c.session == nil || c.session.UnscopedToken != unscopedToken {
+		return nil
+	}
+	return c.session
+}
+
+// saveSession persists newly issued scoped tokens. Failing to cache is not fatal.
+func (c *Client) saveSession() {
+	if c.session != nil {
+		cache.SaveToken(c.session)
+	}
+}
+
+// GetUnscopedToken federates an IdP token with OTC. It returns the unscoped
+// token and when it expires (zero if the response doesn't say).
+func (c *Client) GetUnscopedToken(idToken string, statusSetter ValidationStatusSetter) (string, time.Time, error) {
 	// Determine protocol (default to oidc if not set)
 	protocol := c.cfg.IdpProtocol
 	if protocol == "" {
@@ -55,7 +81,7 @@ func (c *Client) GetUnscopedToken(idToken string, statusSetter ValidationStatusS
 
 	req, err := http.NewRequest("POST", url, nil)
 	if err != nil {
-		return "", err
+		return "", time.Time{}, err
 	}
 
 	// Set authentication header based on protocol
@@ -72,7 +98,7 @@ func (c *Client) GetUnscopedToken(idToken string, statusSetter ValidationStatusS
 		if statusSetter != nil {
 			statusSetter.SetValidationStatus("failed", "Network error")
 		}
-		return "", err
+		return "", time.Time{}, err
 	}
 	defer resp.Body.Close()
 
@@ -96,7 +122,7 @@ func (c *Client) GetUnscopedToken(idToken string, statusSetter ValidationStatusS
 		}
 		time.Sleep(2 * time.Second)
 
-		return "", fmt.Errorf("OTC authorization failed: %s", errMsg)
+		return "", time.Time{}, fmt.Errorf("OTC authorization failed: %s", errMsg)
 	}
 
 	if statusSetter != nil {
@@ -105,13 +131,24 @@ func (c *Client) GetUnscopedToken(idToken string, statusSetter ValidationStatusS
 
 	token := resp.Header.Get("X-Subject-Token")
 	if token == "" {
-		return "", fmt.Errorf("no X-Subject-Token in response")
+		return "", time.Time{}, fmt.Errorf("no X-Subject-Token in response")
+	}
+
+	var result struct {
+		Token struct {
+			ExpiresAt time.Time `json:"expires_at"`
+		} `json:"token"`
 	}
+	json.NewDecoder(resp.Body).Decode(&result)
 
-	return token, nil
+	return token, result.Token.ExpiresAt, nil
 }
 
 func (c *Client) GetDomainScopedToken(unscopedToken string) (string, error) {
+	if session := c.sessionFor(unscopedToken); session != nil && session.DomainToken.Valid() {
+		return session.DomainToken.Token, nil
+	}
+
 	url := fmt.Sprintf("%s/v3/auth/tokens", c.cfg.AUTHURL)
 
 	payload := map[string]interface{}{
@@ -135,37 +172,41 @@ func (c *Client) GetDomainScopedToken(unscopedToken string) (string, error) {
 		return "", fmt.Errorf("failed to get domain-scoped token: %w", err)
 	}
 
-	return token, nil
-}
-
-func (c *Client) ListProjects(domainToken string) ([]Project, error) {
-	url := fmt.Sprintf("%s/v3/auth/projects", c.cfg.AUTHURL)
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
+	if session := c.sessionFor(unscopedToken); session != nil {
+		session.DomainToken = token
+		session.Projects = nil
+		c.saveSession()
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Auth-Token", domainToken)
+	return token.Token, nil
+}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, err
+func (c *Client) ListProjects(domainToken string) ([]Project, error) {
+	// Projects are cached together with the domain token they were listed with
+	if c.session != nil && c.session.DomainToken != nil && c.session.DomainToken.Token == domainToken && c.session.Projects != nil {
+		projects := make([]Project, len(c.session.Projects))
+		for i, p := range c.session.Projects {
+			projects[i] = Project{ID: p.ID, Name: p.Name}
+		}
+		return projects, nil
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("failed to list projects: %s", string(body))
-	}
+	url := fmt.Sprintf("%s/v3/auth/projects", c.cfg.AUTHURL)
 
 	var result struct {
 		Projects []Project `json:"projects"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
+	if err := c.Get(url, domainToken, &result); err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	if c.session != nil && c.session.DomainToken != nil && c.session.DomainToken.Token == domainToken {
+		c.session.Projects = make([]cache.CachedProject, len(result.Projects))
+		for i, p := range result.Projects {
+			c.session.Projects[i] = cache.CachedProject{ID: p.ID, Name: p.Name}
+		}
+		c.saveSession()
 	}
 
 	return result.Projects, nil
@@ -183,6 +224,10 @@ func (c *Client) GetProjects(unscopedToken string) ([]Project, error) {
 }
 
 func (c *Client) GetProjectScopedToken(unscopedToken, projectID string) (string, error) {
+	if session := c.sessionFor(unscopedToken); session != nil && session.ProjectTokens[projectID].Valid() {
+		return session.ProjectTokens[projectID].Token, nil
+	}
+
 	url := fmt.Sprintf("%s/v3/auth/tokens", c.cfg.AUTHURL)
 
 	payload := map[string]interface{}{
@@ -206,37 +251,47 @@ func (c *Client) GetProjectScopedToken(unscopedToken, projectID string) (string,
 		return "", fmt.Errorf("failed to get project-scoped token: %w", err)
 	}
 
-	return token, nil
+	if session := c.sessionFor(unscopedToken); session != nil {
+		if session.ProjectTokens == nil {
+			session.ProjectTokens = map[string]*cache.ScopedToken{}
+		}
+		session.ProjectTokens[projectID] = token
+		c.saveSession()
+	}
+
+	return token.Token, nil
 }
 
-func (c *Client) scopedTokenRequest(url string, payload map[string]interface{}) (string, error) {
+// scopedTokenRequest issues a scoped token and reads its expiry from the response body
+func (c *Client) scopedTokenRequest(url string, payload map[string]interface{}) (*cache.ScopedToken, error) {
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	resp, data, err := c.send("POST", url, nil, jsonData)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return "", err
+	token := resp.Header.Get("X-Subject-Token")
+	if token == "" {
+		return nil, fmt.Errorf("no X-Subject-Token in response")
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("request failed: %s", string(body))
+	var body struct {
+		Token struct {
+			ExpiresAt time.Time        `json:"expires_at"`
+			Catalog   []catalogService `json:"catalog"`
+		} `json:"token"`
 	}
-
-	token := resp.Header.Get("X-Subject-Token")
-	if token == "" {
-		return "", fmt.Errorf("no X-Subject-Token in response")
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil, fmt.Errorf("failed to parse token response: %w", err)
 	}
 
-	return token, nil
+	return &cache.ScopedToken{
+		Token:     token,
+		ExpiresAt: body.Token.ExpiresAt,
+		Catalog:   parseCatalog(body.Token.Catalog, c.cfg.Region),
+	}, nil
 }
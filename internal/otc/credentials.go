@@ -1,11 +1,8 @@
 package otc
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"time"
 )
@@ -31,41 +28,27 @@ func (c *Client) CreateTemporaryCredentials(projectToken string, durationSeconds
 		},
 	}
 
-	jsonData, err := json.Marshal(payload)
+	body, err := c.Do("POST", url, projectToken, payload)
 	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Auth-Token", projectToken)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("failed to create credentials: %s", string(body))
+		return nil, fmt.Errorf("failed to create credentials: %w", err)
 	}
 
 	var result struct {
 		Credential Credentials `json:"credential"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	if err := json.Unmarshal(body, &result); err != nil {
 		return nil, err
 	}
 
 	return &result.Credential, nil
 }
 
+// Expiry parses the expiry timestamp returned by IAM
+func (c *Credentials) Expiry() (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, c.ExpiresAt)
+}
+
 func (c *Credentials) SaveShellScript(filename, region string) error {
 	script := fmt.Sprintf(`#!/bin/bash
 # CloudAstro SSO - OTC Temporary Credentials
@@ -96,7 +79,11 @@ echo "  Region: %s"
 		region,
 	)
 
-	if err := os.WriteFile(filename, []byte(script), 0755); err != nil {
+	// The script is sourced, not executed, and contains secrets: owner read/write only
+	if err := os.WriteFile(filename, []byte(script), 0600); err != nil {
+		return err
+	}
+	if err := os.Chmod(filename, 0600); err != nil {
 		return err
 	}
 
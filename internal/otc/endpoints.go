@@ -0,0 +1,129 @@
+package otc
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// serviceTypes maps otc-cli service names to their types in the IAM catalog,
+// in order of preference
+var serviceTypes = map[string][]string{
+	"ecs": {"ecs", "compute"},
+	"vpc": {"vpc", "network"},
+	"evs": {"evs", "volumev2", "volumev3", "volume"},
+	"cce": {"ccev2.0", "cce"},
+	"ims": {"ims", "image"},
+}
+
+// catalogService is one entry of the catalog in a token response
+type catalogService struct {
+	Type      string `json:"type"`
+	Endpoints []struct {
+		Interface string `json:"interface"`
+		Region    string `json:"region"`
+		RegionID  string `json:"region_id"`
+		URL       string `json:"url"`
+	} `json:"endpoints"`
+}
+
+// parseCatalog returns the base URL of the public endpoint of every service
+// in region. Global services without a region are kept too.
+func parseCatalog(services []catalogService, region string) map[string]string {
+	catalog := map[string]string{}
+
+	for _, service := range services {
+		for _, ep := range service.Endpoints {
+			if ep.Interface != "public" {
+				continue
+			}
+			if ep.Region != region && ep.RegionID != region && (ep.Region != "" || ep.RegionID != "") {
+				continue
+			}
+
+			if base := baseURL(ep.URL); base != "" {
+				catalog[service.Type] = base
+				break
+			}
+		}
+	}
+
+	return catalog
+}
+
+// baseURL cuts a catalog URL such as https://host/prefix/v2/$(tenant_id)s
+// before the API version, since the callers add the version and project
+// themselves. A path prefix in front of it, as used by gateways, is kept.
+func baseURL(raw string) string {
+	// Placeholders such as %(project_id)s aren't valid URL escapes
+	if i := strings.IndexAny(raw, "$%{"); i >= 0 {
+		raw = raw[:i]
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+
+	var prefix []string
+	for _, segment := range strings.Split(strings.Trim(u.Path, "/"), "/") {
+		if segment == "" || isAPIVersion(segment) || isProjectID(segment) {
+			break
+		}
+		prefix = append(prefix, segment)
+	}
+	// CCE's paths start with /api/v3
+	if n := len(prefix); n > 0 && prefix[n-1] == "api" {
+		prefix = prefix[:n-1]
+	}
+
+	base := u.Scheme + "://" + u.Host
+	if len(prefix) > 0 {
+		base += "/" + strings.Join(prefix, "/")
+	}
+	return base
+}
+
+// isProjectID reports whether a path segment is an OTC project ID (32 hex digits)
+func isProjectID(segment string) bool {
+	return len(segment) == 32 && strings.Trim(segment, "0123456789abcdef") == ""
+}
+
+// isAPIVersion reports whether a path segment is a version such as v2 or v2.1
+func isAPIVersion(segment string) bool {
+	if len(segment) < 2 || segment[0] != 'v' {
+		return false
+	}
+	return strings.Trim(segment[1:], "0123456789.") == ""
+}
+
+// Endpoint returns the base URL of service ("ecs", "vpc", "evs", "cce", "ims")
+// for a project. Overrides from the config win over the catalog of the cached
+// project token, which wins over the public default for the region.
+func (c *Client) Endpoint(service, projectID string) string {
+	if url := c.cfg.Endpoints[service]; url != "" {
+		return url
+	}
+
+	if c.session != nil {
+		if token := c.session.ProjectTokens[projectID]; token != nil {
+			for _, serviceType := range serviceTypes[service] {
+				if url := token.Catalog[serviceType]; url != "" {
+					return url
+				}
+			}
+		}
+	}
+
+	return DefaultEndpoint(service, c.cfg.Region)
+}
+
+// DefaultEndpoint returns the public endpoint of service in region. The Swiss
+// cloud (eu-ch2) lives under sc.otc.t-systems.com.
+func DefaultEndpoint(service, region string) string {
+	domain := "otc.t-systems.com"
+	if strings.HasPrefix(region, "eu-ch2") {
+		domain = "sc.otc.t-systems.com"
+	}
+	return fmt.Sprintf("https://%s.%s.%s", service, region, domain)
+}
@@ -0,0 +1,92 @@
+package otc
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/abdo-farag/otc-cli/internal/cache"
+	"github.com/abdo-farag/otc-cli/internal/config"
+)
+
+const tokenCatalog = `[
+	{"type": "compute", "endpoints": [
+		{"interface": "internal", "region": "eu-de", "url": "https://ecs.internal.example/v2.1/$(tenant_id)s"},
+		{"interface": "public", "region": "eu-nl", "url": "https://ecs.eu-nl.otc.t-systems.com/v2.1/$(tenant_id)s"},
+		{"interface": "public", "region": "eu-de", "url": "https://ecs.eu-de.otc.t-systems.com/v2.1/$(tenant_id)s"}
+	]},
+	{"type": "ecs", "endpoints": [
+		{"interface": "public", "region_id": "eu-de", "url": "https://gw.example.com/ecs/v1/0123456789abcdef0123456789abcdef"}
+	]},
+	{"type": "volumev2", "endpoints": [
+		{"interface": "public", "region": "eu-de", "url": "https://evs.eu-de.otc.t-systems.com/v2/%(tenant_id)s"}
+	]},
+	{"type": "ccev2.0", "endpoints": [
+		{"interface": "public", "region": "eu-de", "url": "https://cce.eu-de.otc.t-systems.com/api/v3/projects/%(project_id)s"}
+	]},
+	{"type": "vpc", "endpoints": [
+		{"interface": "public", "region": "eu-de", "url": "https://vpc.eu-de.otc.t-systems.com/"}
+	]},
+	{"type": "identity", "endpoints": [
+		{"interface": "public", "url": "https://iam.eu-de.otc.t-systems.com/v3"}
+	]},
+	{"type": "image", "endpoints": [
+		{"interface": "internal", "region": "eu-de", "url": "https://ims.internal.example"},
+		{"interface": "public", "region": "eu-nl", "url": "https://ims.eu-nl.otc.t-systems.com"}
+	]}
+]`
+
+func TestParseCatalog(t *testing.T) {
+	var services []catalogService
+	if err := json.Unmarshal([]byte(tokenCatalog), &services); err != nil {
+		t.Fatal(err)
+	}
+
+	got := parseCatalog(services, "eu-de")
+
+	want := map[string]string{
+		"compute":  "https://ecs.eu-de.otc.t-systems.com", // only the public eu-de endpoint
+		"ecs":      "https://gw.example.com/ecs",          // path prefix kept, version and project cut
+		"volumev2": "https://evs.eu-de.otc.t-systems.com",
+		"ccev2.0":  "https://cce.eu-de.otc.t-systems.com",
+		"vpc":      "https://vpc.eu-de.otc.t-systems.com",
+		"identity": "https://iam.eu-de.otc.t-systems.com", // global
+		// image has no public endpoint in eu-de
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseCatalog() =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestEndpoint(t *testing.T) {
+	session := &cache.TokenCache{
+		UnscopedToken: "unscoped",
+		ProjectTokens: map[string]*cache.ScopedToken{
+			"p1": {Token: "t", Catalog: map[string]string{"compute": "https://compute.example", "ecs": "https://gw.example.com/ecs"}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		cfg       config.Config
+		service   string
+		projectID string
+		want      string
+	}{
+		{"override wins", config.Config{Region: "eu-de", Endpoints: map[string]string{"ecs": "http://localhost:8080"}}, "ecs", "p1", "http://localhost:8080"},
+		{"catalog in order of preference", config.Config{Region: "eu-de"}, "ecs", "p1", "https://gw.example.com/ecs"},
+		{"default without catalog entry", config.Config{Region: "eu-de"}, "vpc", "p1", "https://vpc.eu-de.otc.t-systems.com"},
+		{"default for another project", config.Config{Region: "eu-nl"}, "ecs", "p2", "https://ecs.eu-nl.otc.t-systems.com"},
+		{"default in the Swiss cloud", config.Config{Region: "eu-ch2"}, "cce", "p2", "https://cce.eu-ch2.sc.otc.t-systems.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(&tt.cfg)
+			client.UseSession(session)
+			if got := client.Endpoint(tt.service, tt.projectID); got != tt.want {
+				t.Errorf("Endpoint(%q, %q) = %q, want %q", tt.service, tt.projectID, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,323 @@
+// Package jobs follows the asynchronous jobs returned by OTC mutation APIs.
+package jobs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abdo-farag/otc-cli/internal/otc"
+	"github.com/abdo-farag/otc-cli/internal/output"
+)
+
+// Service is the API a job belongs to
+type Service string
+
+const (
+	ECS Service = "ecs"
+	EVS Service = "evs"
+	CCE Service = "cce"
+)
+
+// Services lists the accepted job services
+var Services = []Service{ECS, EVS, CCE}
+
+// Job statuses, as used by ECS and EVS. CCE phases are mapped onto them.
+const (
+	StatusInit    = "INIT"
+	StatusRunning = "RUNNING"
+	StatusSuccess = "SUCCESS"
+	StatusFailed  = "FAIL"
+)
+
+// Polling defaults
+const (
+	DefaultTimeout  = 30 * time.Minute
+	DefaultInterval = 3 * time.Second
+	maxInterval     = 15 * time.Second
+)
+
+// Job is an asynchronous job of any service. Batch jobs have one sub-job per resource.
+type Job struct {
+	ID        string  `json:"id"`
+	Service   Service `json:"service"`
+	Type      string  `json:"type"`
+	Status    string  `json:"status"`
+	Resource  string  `json:"resource,omitempty"` // ID or name of the resource the job acts on
+	Reason    string  `json:"reason,omitempty"`   // Why the job failed
+	BeginTime string  `json:"begin_time,omitempty"`
+	EndTime   string  `json:"end_time,omitempty"`
+	SubJobs   []Job   `json:"sub_jobs,omitempty"`
+}
+
+func (Job) Columns(wide bool) []string {
+	columns := []string{"ID", "Type", "Status", "Resource", "Sub-jobs"}
+	if wide {
+		columns = append(columns, "Begin", "End", "Reason")
+	}
+	return columns
+}
+
+func (j Job) Cells(wide bool) []string {
+	subJobs := "-"
+	if len(j.SubJobs) > 0 {
+		subJobs = fmt.Sprintf("%d/%d", j.finished(), len(j.SubJobs))
+	}
+
+	cells := []string{j.ID, j.Type, j.Status, output.OrDash(j.Resource), subJobs}
+	if wide {
+		cells = append(cells, output.OrDash(j.BeginTime), output.OrDash(j.EndTime), output.OrDash(j.Reason))
+	}
+	return cells
+}
+
+// Done reports whether the job finished, successfully or not
+func (j *Job) Done() bool {
+	return j.Status == StatusSuccess || j.Status == StatusFailed
+}
+
+// Err returns an error wrapping otc.ErrFailed if the job failed
+func (j *Job) Err() error {
+	if j.Status != StatusFailed {
+		return nil
+	}
+
+	var reasons []string
+	for _, sub := range j.SubJobs {
+		if sub.Status == StatusFailed {
+			reasons = append(reasons, fmt.Sprintf("%s: %s", output.OrDash(sub.Resource), sub.reason()))
+		}
+	}
+	if len(reasons) == 0 {
+		reasons = []string{j.reason()}
+	}
+
+	return fmt.Errorf("job %s %w: %s", j.ID, otc.ErrFailed, strings.Join(reasons, "; "))
+}
+
+func (j *Job) reason() string {
+	if j.Reason == "" {
+		return "unknown reason"
+	}
+	return j.Reason
+}
+
+func (j *Job) finished() int {
+	n := 0
+	for _, sub := range j.SubJobs {
+		if sub.Done() {
+			n++
+		}
+	}
+	return n
+}
+
+// ParseService parses a service name
+func ParseService(name string) (Service, error) {
+	for _, s := range Services {
+		if strings.EqualFold(name, string(s)) {
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("unknown job service %q (valid: ecs, evs, cce)", name)
+}
+
+// Get fetches the current state of a job
+func Get(client *otc.Client, service Service, projectID, token, jobID string) (*Job, error) {
+	switch service {
+	case ECS, EVS:
+		return getECSJob(client, service, projectID, token, jobID)
+	case CCE:
+		return getCCEJob(client, projectID, token, jobID)
+	}
+	return nil, fmt.Errorf("unknown job service %q", service)
+}
+
+// WaitOptions tunes Wait
+type WaitOptions struct {
+	Timeout  time.Duration  // Default DefaultTimeout
+	Interval time.Duration  // First poll interval, growing up to 15s. Default DefaultInterval
+	Progress func(job *Job) // Called after every poll
+}
+
+// Wait polls a job until it finishes. It returns the last state of the job
+// and an error if the job failed, the timeout passed or the context was cancelled.
+func Wait(client *otc.Client, service Service, projectID, token, jobID string, opts WaitOptions) (*Job, error) {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	deadline := time.Now().Add(timeout)
+
+	for {
+		job, err := Get(client, service, projectID, token, jobID)
+		if err != nil {
+			return nil, err
+		}
+
+		if opts.Progress != nil {
+			opts.Progress(job)
+		}
+		if job.Done() {
+			return job, job.Err()
+		}
+
+		if time.Now().After(deadline) {
+			return job, fmt.Errorf("job %s %w after %s (status %s)", jobID, otc.ErrTimeout, timeout, job.Status)
+		}
+		if err := client.Sleep(min(interval, time.Until(deadline)+time.Second)); err != nil {
+			return job, err
+		}
+		interval = min(interval*3/2, maxInterval)
+	}
+}
+
+// ecsJob is the job format shared by ECS and EVS
+type ecsJob struct {
+	JobID      string `json:"job_id"`
+	JobType    string `json:"job_type"`
+	Status     string `json:"status"`
+	BeginTime  string `json:"begin_time"`
+	EndTime    string `json:"end_time"`
+	ErrorCode  string `json:"error_code"`
+	FailReason string `json:"fail_reason"`
+	Entities   struct {
+		ServerID string   `json:"server_id"`
+		VolumeID string   `json:"volume_id"`
+		SubJobs  []ecsJob `json:"sub_jobs"`
+	} `json:"entities"`
+}
+
+func getECSJob(client *otc.Client, service Service, projectID, token, jobID string) (*Job, error) {
+	jobURL := fmt.Sprintf("%s/v1/%s/jobs/%s", client.Endpoint(string(service), projectID), projectID, jobID)
+
+	var raw ecsJob
+	if err := client.Get(jobURL, token, &raw); err != nil {
+		return nil, err
+	}
+
+	job := raw.toJob(service)
+	if job.ID == "" {
+		job.ID = jobID
+	}
+	return &job, nil
+}
+
+func (r ecsJob) toJob(service Service) Job {
+	job := Job{
+		ID:        r.JobID,
+		Service:   service,
+		Type:      r.JobType,
+		Status:    r.Status,
+		Resource:  r.Entities.ServerID,
+		BeginTime: r.BeginTime,
+		EndTime:   r.EndTime,
+	}
+	if job.Resource == "" {
+		job.Resource = r.Entities.VolumeID
+	}
+
+	switch {
+	case r.FailReason != "" && r.ErrorCode != "":
+		job.Reason = fmt.Sprintf("%s (%s)", r.FailReason, r.ErrorCode)
+	case r.FailReason != "":
+		job.Reason = r.FailReason
+	default:
+		job.Reason = r.ErrorCode
+	}
+
+	for _, sub := range r.Entities.SubJobs {
+		job.SubJobs = append(job.SubJobs, sub.toJob(service))
+	}
+	return job
+}
+
+// cceJob is the Kubernetes-style job format of CCE
+type cceJob struct {
+	Metadata struct {
+		UID               string `json:"uid"`
+		CreationTimestamp string `json:"creationTimestamp"`
+		UpdateTimestamp   string `json:"updateTimestamp"`
+	} `json:"metadata"`
+	Spec struct {
+		Type         string   `json:"type"`
+		ResourceID   string   `json:"resourceID"`
+		ResourceName string   `json:"resourceName"`
+		SubJobs      []cceJob `json:"subJobs"`
+	} `json:"spec"`
+	Status struct {
+		Phase  string `json:"phase"`
+		Reason string `json:"reason"`
+	} `json:"status"`
+}
+
+func getCCEJob(client *otc.Client, projectID, token, jobID string) (*Job, error) {
+	jobURL := fmt.Sprintf("%s/api/v3/projects/%s/jobs/%s", client.Endpoint("cce", projectID), projectID, jobID)
+
+	var raw cceJob
+	if err := client.Get(jobURL, token, &raw); err != nil {
+		return nil, err
+	}
+
+	job := raw.toJob()
+	if job.ID == "" {
+		job.ID = jobID
+	}
+	return &job, nil
+}
+
+func (r cceJob) toJob() Job {
+	job := Job{
+		ID:        r.Metadata.UID,
+		Service:   CCE,
+		Type:      r.Spec.Type,
+		Status:    cceStatus(r.Status.Phase),
+		Resource:  r.Spec.ResourceName,
+		Reason:    r.Status.Reason,
+		BeginTime: r.Metadata.CreationTimestamp,
+	}
+	if job.Resource == "" {
+		job.Resource = r.Spec.ResourceID
+	}
+	if job.Done() {
+		job.EndTime = r.Metadata.UpdateTimestamp
+	}
+
+	for _, sub := range r.Spec.SubJobs {
+		job.SubJobs = append(job.SubJobs, sub.toJob())
+	}
+	return job
+}
+
+// cceStatus maps CCE job phases onto the ECS statuses
+func cceStatus(phase string) string {
+	switch strings.ToLower(phase) {
+	case "success":
+		return StatusSuccess
+	case "failed":
+		return StatusFailed
+	case "running":
+		return StatusRunning
+	case "initializing", "":
+		return StatusInit
+	}
+	return strings.ToUpper(phase)
+}
+
+// Summary describes the progress of a job in one line
+func (j *Job) Summary() string {
+	s := fmt.Sprintf("Job %s", j.ID)
+	if j.Type != "" {
+		s += " (" + j.Type + ")"
+	}
+	s += ": " + j.Status
+	if len(j.SubJobs) > 0 {
+		s += ", " + strconv.Itoa(j.finished()) + "/" + strconv.Itoa(len(j.SubJobs)) + " sub-jobs done"
+	}
+	return s
+}
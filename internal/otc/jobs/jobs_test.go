@@ -0,0 +1,198 @@
+package jobs
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abdo-farag/otc-cli/internal/config"
+	"github.com/abdo-farag/otc-cli/internal/otc"
+)
+
+// jobServer serves the given bodies by path, after the service's endpoint
+func jobServer(t *testing.T, bodies map[string]string) *otc.Client {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := bodies[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(srv.Close)
+
+	return otc.NewClient(&config.Config{Endpoints: map[string]string{"ecs": srv.URL, "evs": srv.URL, "cce": srv.URL}})
+}
+
+func TestGetECSJob(t *testing.T) {
+	client := jobServer(t, map[string]string{
+		"/v1/p1/jobs/j1": `{"job_id":"j1","job_type":"batchStopServer","status":"FAIL","begin_time":"2026-01-01T10:00:00Z",
+			"entities":{"sub_jobs":[
+				{"job_id":"s1","status":"SUCCESS","entities":{"server_id":"srv-1"}},
+				{"job_id":"s2","status":"FAIL","fail_reason":"server is locked","error_code":"Ecs.0100","entities":{"server_id":"srv-2"}},
+				{"job_id":"s3","status":"FAIL","entities":{"server_id":"srv-3"}}
+			]}}`,
+	})
+
+	job, err := Get(client, ECS, "p1", "token", "j1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if job.ID != "j1" || job.Service != ECS || job.Type != "batchStopServer" || job.Status != StatusFailed {
+		t.Errorf("got job %+v", job)
+	}
+	if len(job.SubJobs) != 3 || job.SubJobs[1].Resource != "srv-2" || job.SubJobs[1].Reason != "server is locked (Ecs.0100)" {
+		t.Errorf("got sub-jobs %+v", job.SubJobs)
+	}
+	if got, want := job.Summary(), "Job j1 (batchStopServer): FAIL, 3/3 sub-jobs done"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+
+	err = job.Err()
+	if !errors.Is(err, otc.ErrFailed) {
+		t.Fatalf("Err() = %v, want otc.ErrFailed", err)
+	}
+	if want := "srv-2: server is locked (Ecs.0100); srv-3: unknown reason"; !strings.Contains(err.Error(), want) {
+		t.Errorf("Err() = %q, want the failed sub-jobs %q", err, want)
+	}
+}
+
+func TestGetEVSJob(t *testing.T) {
+	client := jobServer(t, map[string]string{
+		"/v1/p1/jobs/j2": `{"job_id":"j2","job_type":"createVolume","status":"SUCCESS","entities":{"volume_id":"vol-1"}}`,
+		"/v1/p1/jobs/j3": `{"status":"FAIL","error_code":"EVS.2024"}`,
+	})
+
+	job, err := Get(client, EVS, "p1", "token", "j2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Resource != "vol-1" || !job.Done() || job.Err() != nil {
+		t.Errorf("got job %+v, err %v; want a finished job on vol-1", job, job.Err())
+	}
+
+	// A job without sub-jobs reports its own reason, and keeps the ID asked for
+	job, err = Get(client, EVS, "p1", "token", "j3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.ID != "j3" {
+		t.Errorf("ID = %q, want j3", job.ID)
+	}
+	if err := job.Err(); err == nil || !strings.HasSuffix(err.Error(), ": EVS.2024") {
+		t.Errorf("Err() = %v, want the error code", err)
+	}
+}
+
+func TestGetCCEJob(t *testing.T) {
+	client := jobServer(t, map[string]string{
+		"/api/v3/projects/p1/jobs/c1": `{
+			"metadata":{"uid":"c1","creationTimestamp":"2026-01-01 10:00:00","updateTimestamp":"2026-01-01 10:20:00"},
+			"spec":{"type":"CreateCluster","resourceID":"cluster-id","resourceName":"dev","subJobs":[
+				{"metadata":{"uid":"c1-1"},"spec":{"type":"CreateMaster","resourceID":"m1"},"status":{"phase":"Success"}},
+				{"metadata":{"uid":"c1-2"},"spec":{"type":"InstallAddons"},"status":{"phase":"Failed","reason":"addon timeout"}}
+			]},
+			"status":{"phase":"Failed","reason":"subjob failed"}}`,
+	})
+
+	job, err := Get(client, CCE, "p1", "token", "c1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if job.Service != CCE || job.Type != "CreateCluster" || job.Resource != "dev" || job.Status != StatusFailed {
+		t.Errorf("got job %+v", job)
+	}
+	if job.EndTime != "2026-01-01 10:20:00" {
+		t.Errorf("EndTime = %q, want the update time of the finished job", job.EndTime)
+	}
+	if len(job.SubJobs) != 2 || job.SubJobs[0].Resource != "m1" || job.SubJobs[0].Status != StatusSuccess {
+		t.Errorf("got sub-jobs %+v", job.SubJobs)
+	}
+	if err := job.Err(); err == nil || !strings.Contains(err.Error(), "-: addon timeout") {
+		t.Errorf("Err() = %v, want the failed sub-job's reason", err)
+	}
+}
+
+func TestCCEStatus(t *testing.T) {
+	tests := map[string]string{
+		"Success":      StatusSuccess,
+		"success":      StatusSuccess,
+		"Failed":       StatusFailed,
+		"Running":      StatusRunning,
+		"Initializing": StatusInit,
+		"":             StatusInit,
+		"Pending":      "PENDING",
+	}
+	for phase, want := range tests {
+		if got := cceStatus(phase); got != want {
+			t.Errorf("cceStatus(%q) = %q, want %q", phase, got, want)
+		}
+	}
+}
+
+func TestWaitSucceeds(t *testing.T) {
+	polls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		status := "RUNNING"
+		if polls == 3 {
+			status = "SUCCESS"
+		}
+		io.WriteString(w, `{"job_id":"j1","status":"`+status+`"}`)
+	}))
+	defer srv.Close()
+	client := otc.NewClient(&config.Config{Endpoints: map[string]string{"ecs": srv.URL}})
+
+	var seen []string
+	job, err := Wait(client, ECS, "p1", "token", "j1", WaitOptions{
+		Interval: time.Millisecond,
+		Progress: func(job *Job) { seen = append(seen, job.Status) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != StatusSuccess || strings.Join(seen, ",") != "RUNNING,RUNNING,SUCCESS" {
+		t.Errorf("got %s after progress %v", job.Status, seen)
+	}
+}
+
+func TestWaitFails(t *testing.T) {
+	client := jobServer(t, map[string]string{
+		"/v1/p1/jobs/j1": `{"job_id":"j1","status":"FAIL","fail_reason":"quota exceeded"}`,
+	})
+
+	job, err := Wait(client, ECS, "p1", "token", "j1", WaitOptions{Interval: time.Millisecond})
+	if !errors.Is(err, otc.ErrFailed) || job == nil || job.Status != StatusFailed {
+		t.Errorf("got job %+v, err %v; want the failed job and otc.ErrFailed", job, err)
+	}
+}
+
+func TestWaitTimeout(t *testing.T) {
+	client := jobServer(t, map[string]string{
+		"/v1/p1/jobs/j1": `{"job_id":"j1","status":"RUNNING"}`,
+	})
+
+	job, err := Wait(client, ECS, "p1", "token", "j1", WaitOptions{Timeout: time.Nanosecond, Interval: time.Millisecond})
+	if !errors.Is(err, otc.ErrTimeout) {
+		t.Fatalf("err = %v, want otc.ErrTimeout", err)
+	}
+	if job == nil || job.Status != StatusRunning || !strings.Contains(err.Error(), "status RUNNING") {
+		t.Errorf("got job %+v, err %q; want the last state", job, err)
+	}
+}
+
+func TestWaitNotFound(t *testing.T) {
+	client := jobServer(t, nil)
+
+	if _, err := Wait(client, ECS, "p1", "token", "missing", WaitOptions{Interval: time.Millisecond}); !errors.Is(err, otc.ErrNotFound) {
+		t.Errorf("err = %v, want otc.ErrNotFound", err)
+	}
+}
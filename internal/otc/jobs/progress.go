@@ -0,0 +1,31 @@
+package jobs
+
+import (
+	"github.com/abdo-farag/otc-cli/internal/output"
+
+	"github.com/fatih/color"
+)
+
+// Progress returns a WaitOptions.Progress callback that prints the status of
+// the job whenever it changes, and the outcome of every sub-job at the end
+func Progress() func(job *Job) {
+	last := ""
+	return func(job *Job) {
+		if !job.Done() {
+			if summary := job.Summary(); summary != last {
+				color.Yellow("⏳ %s", summary)
+				last = summary
+			}
+			return
+		}
+
+		for _, sub := range job.SubJobs {
+			switch sub.Status {
+			case StatusSuccess:
+				color.Green("  ✓ %s %s", sub.Type, output.OrDash(sub.Resource))
+			case StatusFailed:
+				color.Red("  ✗ %s %s: %s", sub.Type, output.OrDash(sub.Resource), sub.reason())
+			}
+		}
+	}
+}
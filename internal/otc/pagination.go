@@ -0,0 +1,186 @@
+package otc
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+)
+
+// DefaultPageSize is the number of items requested per page
+const DefaultPageSize = 100
+
+// PageStyle is how a list endpoint pages through its results
+type PageStyle int
+
+const (
+	PageMarker PageStyle = iota // limit and marker=<ID of the last item> (OpenStack style)
+	PageNumber                  // limit and offset=<page number>, starting at 1 (ECS)
+	PageOffset                  // limit and offset=<number of items to skip>
+)
+
+// ListOptions describes a paginated list endpoint
+type ListOptions struct {
+	ItemsKey  string // JSON key of the item array, e.g. "servers"
+	Style     PageStyle
+	PageSize  int    // Items per request (default DefaultPageSize)
+	MarkerKey string // Item field used as marker (default "id")
+}
+
+// ErrStopPaging is returned by a page callback to stop reading further pages
+var ErrStopPaging = errors.New("stop paging")
+
+// List reads a paginated list endpoint and calls onPage with the raw items of
+// every page. Next links (links.next, <items>_links or next) are followed when
+// the service returns them; otherwise the next page is requested in opts.Style
+// until a short or empty page comes back.
+func (c *Client) List(rawURL, token string, opts ListOptions, onPage func(items []json.RawMessage) error) error {
+	pageSize := opts.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	markerKey := opts.MarkerKey
+	if markerKey == "" {
+		markerKey = "id"
+	}
+
+	params := map[string]string{"limit": strconv.Itoa(pageSize)}
+	if opts.Style == PageNumber {
+		params["offset"] = "1"
+	}
+	next, err := withQuery(rawURL, params)
+	if err != nil {
+		return err
+	}
+
+	page, offset, lastMarker := 1, 0, ""
+	for {
+		body, err := c.Do("GET", next, token, nil)
+		if err != nil {
+			return err
+		}
+
+		var envelope map[string]json.RawMessage
+		if err := json.Unmarshal(body, &envelope); err != nil {
+			return err
+		}
+
+		var items []json.RawMessage
+		if raw, ok := envelope[opts.ItemsKey]; ok {
+			if err := json.Unmarshal(raw, &items); err != nil {
+				return err
+			}
+		}
+
+		if len(items) == 0 {
+			return nil
+		}
+
+		if err := onPage(items); err != nil {
+			if errors.Is(err, ErrStopPaging) {
+				return nil
+			}
+			return err
+		}
+
+		if link := nextLink(envelope, opts.ItemsKey); link != "" {
+			resolved, err := resolveLink(next, link)
+			if err != nil || resolved == next {
+				return err
+			}
+			next = resolved
+			continue
+		}
+
+		if len(items) < pageSize {
+			return nil
+		}
+
+		switch opts.Style {
+		case PageMarker:
+			var last map[string]interface{}
+			json.Unmarshal(items[len(items)-1], &last)
+			marker, _ := last[markerKey].(string)
+			if marker == "" || marker == lastMarker {
+				return nil
+			}
+			lastMarker = marker
+			params["marker"] = marker
+		case PageNumber:
+			page++
+			params["offset"] = strconv.Itoa(page)
+		case PageOffset:
+			offset += len(items)
+			params["offset"] = strconv.Itoa(offset)
+		}
+
+		if next, err = withQuery(rawURL, params); err != nil {
+			return err
+		}
+	}
+}
+
+// nextLink finds the next page link in the envelope styles used by OTC services
+func nextLink(envelope map[string]json.RawMessage, itemsKey string) string {
+	type link struct {
+		Rel  string `json:"rel"`
+		Href string `json:"href"`
+	}
+
+	for _, key := range []string{itemsKey + "_links", "links"} {
+		raw, ok := envelope[key]
+		if !ok {
+			continue
+		}
+
+		var links []link
+		if json.Unmarshal(raw, &links) == nil {
+			for _, l := range links {
+				if l.Rel == "next" && l.Href != "" {
+					return l.Href
+				}
+			}
+			continue
+		}
+
+		var object struct {
+			Next string `json:"next"`
+		}
+		if json.Unmarshal(raw, &object) == nil && object.Next != "" {
+			return object.Next
+		}
+	}
+
+	var next string
+	if raw, ok := envelope["next"]; ok && json.Unmarshal(raw, &next) == nil {
+		return next
+	}
+	return ""
+}
+
+func resolveLink(current, link string) (string, error) {
+	base, err := url.Parse(current)
+	if err != nil {
+		return "", err
+	}
+	ref, err := url.Parse(link)
+	if err != nil {
+		return "", err
+	}
+	return base.ResolveReference(ref).String(), nil
+}
+
+// withQuery sets query parameters on rawURL, keeping the ones it already has
+func withQuery(rawURL string, params map[string]string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	q := u.Query()
+	for key, value := range params {
+		q.Set(key, value)
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
@@ -0,0 +1,191 @@
+package otc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/abdo-farag/otc-cli/internal/config"
+)
+
+// pagedServer serves total items with IDs i0, i1, ... under "items". start
+// maps the query of a request to the index of the first item to return.
+func pagedServer(t *testing.T, total int, start func(q url.Values) int) (*httptest.Server, *[]url.Values) {
+	t.Helper()
+
+	var queries []url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		queries = append(queries, q)
+
+		limit, _ := strconv.Atoi(q.Get("limit"))
+		from := min(start(q), total)
+		to := min(from+limit, total)
+
+		items := []map[string]string{}
+		for i := from; i < to; i++ {
+			items = append(items, map[string]string{"id": fmt.Sprintf("i%d", i)})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"items": items})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &queries
+}
+
+// collectIDs lists url and returns the IDs of every page
+func collectIDs(t *testing.T, rawURL string, opts ListOptions) [][]string {
+	t.Helper()
+
+	var pages [][]string
+	err := NewClient(&config.Config{}).List(rawURL, "token", opts, func(items []json.RawMessage) error {
+		var ids []string
+		for _, data := range items {
+			var item struct {
+				ID string `json:"id"`
+			}
+			if err := json.Unmarshal(data, &item); err != nil {
+				return err
+			}
+			ids = append(ids, item.ID)
+		}
+		pages = append(pages, ids)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pages
+}
+
+func TestListMarker(t *testing.T) {
+	srv, queries := pagedServer(t, 5, func(q url.Values) int {
+		if marker := q.Get("marker"); marker != "" {
+			n, _ := strconv.Atoi(marker[1:])
+			return n + 1
+		}
+		return 0
+	})
+
+	pages := collectIDs(t, srv.URL+"/items?name=web", ListOptions{ItemsKey: "items", PageSize: 2})
+
+	want := [][]string{{"i0", "i1"}, {"i2", "i3"}, {"i4"}}
+	if !reflect.DeepEqual(pages, want) {
+		t.Errorf("pages = %v, want %v", pages, want)
+	}
+	var markers []string
+	for _, q := range *queries {
+		markers = append(markers, q.Get("marker"))
+		if q.Get("limit") != "2" || q.Get("name") != "web" {
+			t.Errorf("query %v lacks limit=2 or the caller's name=web", q)
+		}
+	}
+	if want := []string{"", "i1", "i3"}; !reflect.DeepEqual(markers, want) {
+		t.Errorf("markers = %q, want %q", markers, want)
+	}
+}
+
+func TestListPageNumber(t *testing.T) {
+	srv, queries := pagedServer(t, 4, func(q url.Values) int {
+		page, _ := strconv.Atoi(q.Get("offset"))
+		limit, _ := strconv.Atoi(q.Get("limit"))
+		return (page - 1) * limit
+	})
+
+	pages := collectIDs(t, srv.URL+"/items", ListOptions{ItemsKey: "items", Style: PageNumber, PageSize: 2})
+
+	// The last page is full, so it takes an empty page to find the end
+	want := [][]string{{"i0", "i1"}, {"i2", "i3"}}
+	if !reflect.DeepEqual(pages, want) {
+		t.Errorf("pages = %v, want %v", pages, want)
+	}
+	var offsets []string
+	for _, q := range *queries {
+		offsets = append(offsets, q.Get("offset"))
+	}
+	if want := []string{"1", "2", "3"}; !reflect.DeepEqual(offsets, want) {
+		t.Errorf("offsets = %q, want page numbers %q", offsets, want)
+	}
+}
+
+func TestListOffset(t *testing.T) {
+	srv, queries := pagedServer(t, 5, func(q url.Values) int {
+		offset, _ := strconv.Atoi(q.Get("offset"))
+		return offset
+	})
+
+	pages := collectIDs(t, srv.URL+"/items", ListOptions{ItemsKey: "items", Style: PageOffset, PageSize: 2})
+
+	want := [][]string{{"i0", "i1"}, {"i2", "i3"}, {"i4"}}
+	if !reflect.DeepEqual(pages, want) {
+		t.Errorf("pages = %v, want %v", pages, want)
+	}
+	var offsets []string
+	for _, q := range *queries {
+		offsets = append(offsets, q.Get("offset"))
+	}
+	if want := []string{"", "2", "4"}; !reflect.DeepEqual(offsets, want) {
+		t.Errorf("offsets = %q, want %q", offsets, want)
+	}
+}
+
+func TestListFollowsNextLinks(t *testing.T) {
+	tests := []struct {
+		name string
+		link func(next string) string
+	}{
+		{"links array", func(next string) string { return `"links":[{"rel":"next","href":"` + next + `"}]` }},
+		{"items_links array", func(next string) string { return `"items_links":[{"rel":"next","href":"` + next + `"}]` }},
+		{"links object", func(next string) string { return `"links":{"next":"` + next + `"}` }},
+		{"next field", func(next string) string { return `"next":"` + next + `"` }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var paths []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				paths = append(paths, r.URL.RequestURI())
+				switch r.URL.Path {
+				case "/items":
+					// A short page: only the link says there is more
+					io.WriteString(w, `{"items":[{"id":"i0"}],`+tt.link("/items/page2?token=abc")+`}`)
+				case "/items/page2":
+					io.WriteString(w, `{"items":[{"id":"i1"}]}`)
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer srv.Close()
+
+			pages := collectIDs(t, srv.URL+"/items", ListOptions{ItemsKey: "items", PageSize: 2})
+
+			if want := [][]string{{"i0"}, {"i1"}}; !reflect.DeepEqual(pages, want) {
+				t.Errorf("pages = %v, want %v", pages, want)
+			}
+			if len(paths) != 2 || paths[1] != "/items/page2?token=abc" {
+				t.Errorf("requests = %q, want the next link as the second", paths)
+			}
+		})
+	}
+}
+
+func TestListStopPaging(t *testing.T) {
+	srv, queries := pagedServer(t, 10, func(url.Values) int { return 0 })
+
+	pages := 0
+	err := NewClient(&config.Config{}).List(srv.URL, "token", ListOptions{ItemsKey: "items", PageSize: 2}, func([]json.RawMessage) error {
+		pages++
+		return ErrStopPaging
+	})
+	if err != nil {
+		t.Fatalf("ErrStopPaging leaked out of List: %v", err)
+	}
+	if pages != 1 || len(*queries) != 1 {
+		t.Errorf("read %d pages in %d requests after ErrStopPaging, want 1", pages, len(*queries))
+	}
+}
@@ -0,0 +1,596 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONPath is a parsed kubectl-style JSONPath template such as
+// "{range [*]}{.name}{'\t'}{.status}{'\n'}{end}". Supported are fields,
+// indexes, slices, wildcards, recursive descent (..name), filters
+// ([?(@.status=="ACTIVE")]), @ for the current element, range/end and
+// quoted literals.
+type JSONPath struct {
+	nodes []tplNode
+}
+
+type nodeKind int
+
+const (
+	nodeText nodeKind = iota
+	nodePath
+	nodeRange
+	nodeEnd
+)
+
+type tplNode struct {
+	kind  nodeKind
+	text  string
+	steps []step
+	root  bool // path starts at $ instead of the current element
+}
+
+type stepKind int
+
+const (
+	stepField stepKind = iota
+	stepIndex
+	stepSlice
+	stepWildcard
+	stepRecursive
+	stepFilter
+)
+
+type step struct {
+	kind     stepKind
+	name     string
+	index    int
+	start    *int
+	end      *int
+	filter   []step // path relative to @
+	op       string // empty: the filter path only has to exist
+	operand  interface{}
+	children []step
+}
+
+// ParseJSONPath parses a JSONPath template. A bare expression without braces
+// is accepted as a single path.
+func ParseJSONPath(template string) (*JSONPath, error) {
+	if !strings.Contains(template, "{") {
+		template = "{" + template + "}"
+	}
+
+	var nodes []tplNode
+	for len(template) > 0 {
+		open := strings.IndexByte(template, '{')
+		if open < 0 {
+			nodes = append(nodes, tplNode{kind: nodeText, text: template})
+			break
+		}
+		if open > 0 {
+			nodes = append(nodes, tplNode{kind: nodeText, text: template[:open]})
+		}
+
+		end := matchingClose(template, open, '{', '}')
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed '{' in jsonpath template")
+		}
+
+		node, err := parseAction(strings.TrimSpace(template[open+1 : end]))
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+		template = template[end+1:]
+	}
+
+	depth := 0
+	for _, n := range nodes {
+		switch n.kind {
+		case nodeRange:
+			depth++
+		case nodeEnd:
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("{end} without {range} in jsonpath template")
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("{range} without {end} in jsonpath template")
+	}
+
+	return &JSONPath{nodes: nodes}, nil
+}
+
+func parseAction(action string) (tplNode, error) {
+	switch {
+	case action == "end":
+		return tplNode{kind: nodeEnd}, nil
+	case strings.HasPrefix(action, "range "):
+		node, err := parsePathNode(strings.TrimSpace(strings.TrimPrefix(action, "range ")))
+		node.kind = nodeRange
+		return node, err
+	case strings.HasPrefix(action, `"`) || strings.HasPrefix(action, "'"):
+		text, err := unquote(action)
+		if err != nil {
+			return tplNode{}, fmt.Errorf("invalid literal %s: %w", action, err)
+		}
+		return tplNode{kind: nodeText, text: text}, nil
+	}
+	return parsePathNode(action)
+}
+
+func parsePathNode(path string) (tplNode, error) {
+	node := tplNode{kind: nodePath}
+	switch {
+	case strings.HasPrefix(path, "$"):
+		node.root = true
+		path = path[1:]
+	case strings.HasPrefix(path, "@"):
+		// @ is the current element, which paths start at anyway
+		path = path[1:]
+	}
+
+	steps, err := parseSteps(path)
+	if err != nil {
+		return node, err
+	}
+	node.steps = steps
+	return node, nil
+}
+
+// parseSteps parses a path such as .a.b[0][*]..c relative to some element
+func parseSteps(path string) ([]step, error) {
+	var steps []step
+
+	for i := 0; i < len(path); {
+		switch path[i] {
+		case '.':
+			i++
+			if i < len(path) && path[i] == '.' {
+				i++
+				name, n := readName(path[i:])
+				if name == "" {
+					return nil, fmt.Errorf("missing field name after '..' in %q", path)
+				}
+				steps = append(steps, step{kind: stepRecursive, name: name})
+				i += n
+				continue
+			}
+			if i < len(path) && path[i] == '*' {
+				steps = append(steps, step{kind: stepWildcard})
+				i++
+				continue
+			}
+			name, n := readName(path[i:])
+			if name != "" {
+				steps = append(steps, step{kind: stepField, name: name})
+			}
+			i += n
+		case '[':
+			end := matchingClose(path, i, '[', ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed '[' in %q", path)
+			}
+			s, err := parseBracket(strings.TrimSpace(path[i+1 : end]))
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, s)
+			i = end + 1
+		default:
+			name, n := readName(path[i:])
+			if name == "" {
+				return nil, fmt.Errorf("unexpected %q in %q", path[i], path)
+			}
+			steps = append(steps, step{kind: stepField, name: name})
+			i += n
+		}
+	}
+
+	return steps, nil
+}
+
+func readName(s string) (string, int) {
+	n := 0
+	for n < len(s) && s[n] != '.' && s[n] != '[' && s[n] != ' ' {
+		n++
+	}
+	return s[:n], n
+}
+
+func parseBracket(content string) (step, error) {
+	switch {
+	case content == "*":
+		return step{kind: stepWildcard}, nil
+	case strings.HasPrefix(content, "'") || strings.HasPrefix(content, `"`):
+		name, err := unquote(content)
+		if err != nil {
+			return step{}, fmt.Errorf("invalid field name %s: %w", content, err)
+		}
+		return step{kind: stepField, name: name}, nil
+	case strings.HasPrefix(content, "?(") && strings.HasSuffix(content, ")"):
+		return parseFilter(strings.TrimSpace(content[2 : len(content)-1]))
+	case strings.Contains(content, ":"):
+		parts := strings.SplitN(content, ":", 2)
+		s := step{kind: stepSlice}
+		for i, part := range parts {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			v, err := strconv.Atoi(part)
+			if err != nil {
+				return step{}, fmt.Errorf("invalid slice [%s]", content)
+			}
+			if i == 0 {
+				s.start = &v
+			} else {
+				s.end = &v
+			}
+		}
+		return s, nil
+	}
+
+	index, err := strconv.Atoi(content)
+	if err != nil {
+		return step{}, fmt.Errorf("invalid index [%s]", content)
+	}
+	return step{kind: stepIndex, index: index}, nil
+}
+
+var filterOps = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+func parseFilter(expr string) (step, error) {
+	s := step{kind: stepFilter}
+
+	left := expr
+	if i, op := findOperator(expr); op != "" {
+		left = strings.TrimSpace(expr[:i])
+		s.op = op
+
+		operand := strings.TrimSpace(expr[i+len(op):])
+		value, err := parseOperand(operand)
+		if err != nil {
+			return s, err
+		}
+		s.operand = value
+	}
+
+	if !strings.HasPrefix(left, "@") {
+		return s, fmt.Errorf("filter must start with @: %q", expr)
+	}
+
+	steps, err := parseSteps(left[1:])
+	if err != nil {
+		return s, err
+	}
+	s.filter = steps
+	return s, nil
+}
+
+// findOperator returns the first comparison operator in expr outside quotes
+// and its position, or "" if there is none
+func findOperator(expr string) (int, string) {
+	var quote byte
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		default:
+			for _, op := range filterOps {
+				if strings.HasPrefix(expr[i:], op) {
+					return i, op
+				}
+			}
+		}
+	}
+	return -1, ""
+}
+
+func parseOperand(operand string) (interface{}, error) {
+	switch {
+	case strings.HasPrefix(operand, "'") || strings.HasPrefix(operand, `"`):
+		return unquote(operand)
+	case operand == "true":
+		return true, nil
+	case operand == "false":
+		return false, nil
+	case operand == "null":
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(operand, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter value %q", operand)
+	}
+	return f, nil
+}
+
+// unquote accepts single- and double-quoted strings with Go escapes
+func unquote(s string) (string, error) {
+	if strings.HasPrefix(s, "'") && strings.HasSuffix(s, "'") && len(s) >= 2 {
+		s = `"` + strings.ReplaceAll(s[1:len(s)-1], `"`, `\"`) + `"`
+	}
+	return strconv.Unquote(s)
+}
+
+// matchingClose returns the index of the bracket closing the one at open,
+// skipping quoted strings and nested brackets
+func matchingClose(s string, open int, openCh, closeCh byte) int {
+	depth := 0
+	var quote byte
+	for i := open; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == openCh:
+			depth++
+		case c == closeCh:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// Execute writes the template evaluated against data
+func (j *JSONPath) Execute(w io.Writer, data interface{}) error {
+	_, err := j.execute(w, j.nodes, data, data)
+	return err
+}
+
+// execute runs nodes until a matching {end} and returns how many it consumed
+func (j *JSONPath) execute(w io.Writer, nodes []tplNode, root, current interface{}) (int, error) {
+	for i := 0; i < len(nodes); i++ {
+		node := nodes[i]
+		switch node.kind {
+		case nodeText:
+			if _, err := io.WriteString(w, node.text); err != nil {
+				return 0, err
+			}
+		case nodePath:
+			results := node.eval(root, current)
+			values := make([]string, 0, len(results))
+			for _, r := range results {
+				values = append(values, formatValue(r))
+			}
+			if _, err := io.WriteString(w, strings.Join(values, " ")); err != nil {
+				return 0, err
+			}
+		case nodeRange:
+			body := nodes[i+1:]
+			results := node.eval(root, current)
+
+			// Skip the body once even if there is nothing to range over
+			consumed, err := j.execute(io.Discard, body, root, nil)
+			if err != nil {
+				return 0, err
+			}
+			for _, r := range results {
+				if _, err := j.execute(w, body, root, r); err != nil {
+					return 0, err
+				}
+			}
+			i += consumed + 1
+		case nodeEnd:
+			return i, nil
+		}
+	}
+	return len(nodes), nil
+}
+
+func (n tplNode) eval(root, current interface{}) []interface{} {
+	start := current
+	if n.root {
+		start = root
+	}
+	return evalSteps(n.steps, []interface{}{start})
+}
+
+func evalSteps(steps []step, values []interface{}) []interface{} {
+	for _, s := range steps {
+		var next []interface{}
+		for _, v := range values {
+			next = append(next, s.apply(v)...)
+		}
+		values = next
+	}
+	return values
+}
+
+func (s step) apply(v interface{}) []interface{} {
+	switch s.kind {
+	case stepField:
+		if m, ok := v.(map[string]interface{}); ok {
+			if child, ok := m[s.name]; ok {
+				return []interface{}{child}
+			}
+		}
+	case stepIndex:
+		if a, ok := v.([]interface{}); ok {
+			i := s.index
+			if i < 0 {
+				i += len(a)
+			}
+			if i >= 0 && i < len(a) {
+				return []interface{}{a[i]}
+			}
+		}
+	case stepSlice:
+		if a, ok := v.([]interface{}); ok {
+			start, end := 0, len(a)
+			if s.start != nil {
+				start = clampIndex(*s.start, len(a))
+			}
+			if s.end != nil {
+				end = clampIndex(*s.end, len(a))
+			}
+			if start < end {
+				return a[start:end]
+			}
+		}
+	case stepWildcard:
+		return children(v)
+	case stepRecursive:
+		var found []interface{}
+		walk(v, func(node interface{}) {
+			if m, ok := node.(map[string]interface{}); ok {
+				if child, ok := m[s.name]; ok {
+					found = append(found, child)
+				}
+			}
+		})
+		return found
+	case stepFilter:
+		var matched []interface{}
+		for _, child := range children(v) {
+			if s.matches(child) {
+				matched = append(matched, child)
+			}
+		}
+		return matched
+	}
+	return nil
+}
+
+func (s step) matches(v interface{}) bool {
+	results := evalSteps(s.filter, []interface{}{v})
+	if s.op == "" {
+		return len(results) > 0
+	}
+	for _, r := range results {
+		if compare(r, s.op, s.operand) {
+			return true
+		}
+	}
+	return false
+}
+
+func compare(left interface{}, op string, right interface{}) bool {
+	lf, lok := toFloat(left)
+	rf, rok := toFloat(right)
+	if lok && rok {
+		switch op {
+		case "==":
+			return lf == rf
+		case "!=":
+			return lf != rf
+		case "<":
+			return lf < rf
+		case ">":
+			return lf > rf
+		case "<=":
+			return lf <= rf
+		case ">=":
+			return lf >= rf
+		}
+	}
+
+	ls, rs := formatValue(left), formatValue(right)
+	switch op {
+	case "==":
+		return ls == rs
+	case "!=":
+		return ls != rs
+	case "<":
+		return ls < rs
+	case ">":
+		return ls > rs
+	case "<=":
+		return ls <= rs
+	case ">=":
+		return ls >= rs
+	}
+	return false
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func clampIndex(i, n int) int {
+	if i < 0 {
+		i += n
+	}
+	if i < 0 {
+		return 0
+	}
+	if i > n {
+		return n
+	}
+	return i
+}
+
+// children returns the elements of an array or the values of an object in key order
+func children(v interface{}) []interface{} {
+	switch c := v.(type) {
+	case []interface{}:
+		return c
+	case map[string]interface{}:
+		keys := make([]string, 0, len(c))
+		for k := range c {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		values := make([]interface{}, 0, len(c))
+		for _, k := range keys {
+			values = append(values, c[k])
+		}
+		return values
+	}
+	return nil
+}
+
+func walk(v interface{}, fn func(interface{})) {
+	fn(v)
+	for _, child := range children(v) {
+		walk(child, fn)
+	}
+}
+
+// formatValue prints scalars as text and objects and arrays as compact JSON
+func formatValue(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case json.Number:
+		return value.String()
+	case bool, float64:
+		return fmt.Sprint(value)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
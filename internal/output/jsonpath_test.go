@@ -0,0 +1,115 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const jsonPathDoc = `{
+	"kind": "List",
+	"items": [
+		{"name": "web-1", "status": "ACTIVE", "vcpus": 2, "ready": true, "tags": ["a", "b"], "addresses": {"private": [{"addr": "10.0.0.1"}]}},
+		{"name": "web-2", "status": "SHUTOFF", "vcpus": 4, "ready": false, "tags": [], "addresses": {"private": [{"addr": "10.0.0.2"}]}},
+		{"name": "db-1", "status": "ACTIVE", "vcpus": 8, "ready": true, "note": "a<\"==\"", "addresses": {}}
+	]
+}`
+
+func jsonPathData(t *testing.T) interface{} {
+	t.Helper()
+	dec := json.NewDecoder(strings.NewReader(jsonPathDoc))
+	dec.UseNumber()
+	var data interface{}
+	if err := dec.Decode(&data); err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestJSONPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{"field", "{.kind}", "List"},
+		{"bare expression", ".kind", "List"},
+		{"root", "{$.kind}", "List"},
+		{"nested field", "{.items[0].addresses.private[0].addr}", "10.0.0.1"},
+		{"quoted field", "{.items[0]['name']}", "web-1"},
+		{"index", "{.items[1].name}", "web-2"},
+		{"negative index", "{.items[-1].name}", "db-1"},
+		{"index out of range", "{.items[5].name}", ""},
+		{"slice", "{.items[0:2].name}", "web-1 web-2"},
+		{"negative slice start", "{.items[-2:].name}", "web-2 db-1"},
+		{"negative slice end", "{.items[:-1].name}", "web-1 web-2"},
+		{"wildcard", "{.items[*].vcpus}", "2 4 8"},
+		{"dot wildcard", "{.items[0].tags.*}", "a b"},
+		{"recursive descent", "{..addr}", "10.0.0.1 10.0.0.2"},
+		{"object as JSON", "{.items[0].tags}", `["a","b"]`},
+		{"bool", "{.items[1].ready}", "false"},
+		{"filter ==", `{.items[?(@.status=="ACTIVE")].name}`, "web-1 db-1"},
+		{"filter !=", `{.items[?(@.status!='ACTIVE')].name}`, "web-2"},
+		{"filter <", "{.items[?(@.vcpus<4)].name}", "web-1"},
+		{"filter <=", "{.items[?(@.vcpus<=4)].name}", "web-1 web-2"},
+		{"filter >", "{.items[?(@.vcpus>4)].name}", "db-1"},
+		{"filter >=", "{.items[?(@.vcpus >= 4)].name}", "web-2 db-1"},
+		{"filter bool", "{.items[?(@.ready==false)].name}", "web-2"},
+		{"filter exists", "{.items[?(@.note)].name}", "db-1"},
+		{"filter operator in quotes", `{.items[?(@.note=="a<\"==\"")].name}`, "db-1"},
+		{"filter comparing to an operator", `{.items[?(@.status>"==")].name}`, "web-1 web-2 db-1"},
+		{"range", `{range .items[*]}{.name}{"\n"}{end}`, "web-1\nweb-2\ndb-1\n"},
+		{"range with @", `{range .items[*]}{@.name}{"\t"}{@.vcpus}{"\n"}{end}`, "web-1\t2\nweb-2\t4\ndb-1\t8\n"},
+		{"@ alone", `{range .items[0].tags[*]}[{@}]{end}`, "[a][b]"},
+		{"nested range", `{range .items[?(@.status=="ACTIVE")]}{.name}:{range .tags[*]}{@},{end};{end}`, "web-1:a,b,;db-1:;"},
+		{"range over nothing", "{range .missing[*]}x{end}done", "done"},
+		{"root inside range", "{range .items[0:1]}{$.kind}{end}", "List"},
+		{"literals", `{.kind}{'\t'}{"x"}{"\n"}`, "List\tx\n"},
+		{"text around actions", "kind={.kind}!", "kind=List!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jp, err := ParseJSONPath(tt.template)
+			if err != nil {
+				t.Fatalf("ParseJSONPath(%q): %v", tt.template, err)
+			}
+			var buf bytes.Buffer
+			if err := jp.Execute(&buf, jsonPathData(t)); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("%s = %q, want %q", tt.template, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONPathErrors(t *testing.T) {
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"{.kind", "unclosed '{'"},
+		{"{.items[0}", "unclosed '['"},
+		{"{range .items[*]}{.name}", "{range} without {end}"},
+		{"{.name}{end}", "{end} without {range}"},
+		{"{.items[x]}", "invalid index"},
+		{"{.items[a:2]}", "invalid slice"},
+		{"{.items[?(.status=='A')]}", "filter must start with @"},
+		{"{.items[?(@.vcpus>four)]}", "invalid filter value"},
+		{"{.items[?(@.status=='A)]}", "unclosed '{'"},
+		{"{..}", "missing field name after '..'"},
+		{`{"bad \q escape"}`, "invalid literal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			_, err := ParseJSONPath(tt.template)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseJSONPath(%q) error = %v, want %q", tt.template, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package output
+
+import "encoding/json"
+
+// Decoration is printed around the table in table and wide format only
+type Decoration struct {
+	Title  string   // e.g. "Project: <id>"
+	Notes  []string // e.g. active filters
+	Kind   string   // plural noun for the total line, e.g. "VPCs"
+	Footer []string
+}
+
+// List is the result of a list command. Only the items are serialized.
+type List[T Row] struct {
+	Items []T
+	Decoration
+
+	// Streamed is set when the items were already printed page by page
+	Streamed bool
+}
+
+// NewList returns a list of items of the given kind
+func NewList[T Row](items []T, kind string) *List[T] {
+	return &List[T]{Items: items, Decoration: Decoration{Kind: kind}}
+}
+
+func (l *List[T]) Header(wide bool) []string {
+	var zero T
+	return zero.Columns(wide)
+}
+
+func (l *List[T]) Rows(wide bool) [][]string {
+	rows := make([][]string, 0, len(l.Items))
+	for _, item := range l.Items {
+		rows = append(rows, item.Cells(wide))
+	}
+	return rows
+}
+
+func (l *List[T]) MarshalJSON() ([]byte, error) {
+	if l.Items == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(l.Items)
+}
+
+func (l *List[T]) decoration() *Decoration {
+	return &l.Decoration
+}
+
+func (l *List[T]) streamed() bool {
+	return l.Streamed
+}
+
+// OrDash returns s, or "-" if it is empty
+func OrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,344 @@
+// Package output renders command results as tables or machine-readable documents.
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/fatih/color"
+	"github.com/rodaine/table"
+	"gopkg.in/yaml.v2"
+)
+
+// Output formats
+const (
+	FormatTable = "table"
+	FormatWide  = "wide"
+	FormatJSON  = "json"
+	FormatYAML  = "yaml"
+	FormatCSV   = "csv"
+	FormatTSV   = "tsv"
+	FormatName  = "name"
+
+	// Formats taking an argument: -o jsonpath={.name}
+	FormatJSONPath       = "jsonpath"
+	FormatGoTemplate     = "go-template"
+	FormatGoTemplateFile = "go-template-file"
+	FormatCustomColumns  = "custom-columns"
+)
+
+// Formats lists the accepted values of -o/--output
+var Formats = []string{
+	FormatTable, FormatWide, FormatJSON, FormatYAML, FormatCSV, FormatTSV, FormatName,
+	FormatJSONPath + "=...", FormatGoTemplate + "=...", FormatGoTemplateFile + "=...", FormatCustomColumns + "=...",
+}
+
+var simpleFormats = []string{FormatTable, FormatWide, FormatJSON, FormatYAML, FormatCSV, FormatTSV, FormatName}
+
+// Row is implemented by every resource type that can be printed as a table row.
+// Columns must not depend on the receiver's value.
+type Row interface {
+	Columns(wide bool) []string
+	Cells(wide bool) []string
+}
+
+// Tabular is a result that can be rendered as a table
+type Tabular interface {
+	Header(wide bool) []string
+	Rows(wide bool) [][]string
+}
+
+// Printer writes results in one of the supported formats
+type Printer struct {
+	Format string
+	Out    io.Writer
+
+	jsonPath *JSONPath
+	template *template.Template
+	columns  []Column
+}
+
+// New returns a printer for format writing to stdout. Templates are parsed
+// here so mistakes are reported before any API call is made.
+func New(format string) (*Printer, error) {
+	if format == "" {
+		format = FormatTable
+	}
+
+	p := &Printer{Format: format, Out: os.Stdout}
+
+	name, arg, hasArg := strings.Cut(format, "=")
+	if !hasArg {
+		for _, f := range simpleFormats {
+			if format == f {
+				return p, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown output format %q (valid: %s)", format, strings.Join(Formats, ", "))
+	}
+
+	p.Format = name
+
+	var err error
+	switch name {
+	case FormatJSONPath:
+		p.jsonPath, err = ParseJSONPath(arg)
+	case FormatGoTemplate:
+		p.template, err = parseGoTemplate(arg, false)
+	case FormatGoTemplateFile:
+		p.template, err = parseGoTemplate(arg, true)
+	case FormatCustomColumns:
+		p.columns, err = ParseCustomColumns(arg)
+	default:
+		return nil, fmt.Errorf("unknown output format %q (valid: %s)", name, strings.Join(Formats, ", "))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Human reports whether the printer renders for people rather than scripts.
+// Status messages should only be printed for human formats.
+func (p *Printer) Human() bool {
+	return p.Format == FormatTable || p.Format == FormatWide
+}
+
+// Wide reports whether rows are printed with their wide cells, which csv and
+// tsv always are
+func (p *Printer) Wide() bool {
+	return p.Format == FormatWide || p.Format == FormatCSV || p.Format == FormatTSV
+}
+
+// Stream returns a callback that prints the pages of a list as they arrive,
+// for formats that don't need the whole result first (csv, tsv and name).
+// It returns nil for every other format.
+func (p *Printer) Stream() func(page Tabular) error {
+	switch p.Format {
+	case FormatCSV, FormatTSV:
+		header := true
+		return func(page Tabular) error {
+			err := p.printCSV(page, header)
+			header = false
+			return err
+		}
+	case FormatName:
+		return p.printNames
+	}
+	return nil
+}
+
+// Print renders v, which is a *List or a single Row
+func (p *Printer) Print(v interface{}) error {
+	if list, ok := v.(interface{ streamed() bool }); ok && list.streamed() {
+		return nil
+	}
+
+	switch p.Format {
+	case FormatJSON:
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(p.Out, string(data))
+		return err
+	case FormatYAML:
+		data, err := toYAML(v)
+		if err != nil {
+			return err
+		}
+		_, err = p.Out.Write(data)
+		return err
+	case FormatJSONPath, FormatGoTemplate, FormatGoTemplateFile, FormatCustomColumns:
+		return p.printTemplate(v)
+	}
+
+	tab, ok := v.(Tabular)
+	if !ok {
+		row, isRow := v.(Row)
+		if !isRow {
+			return fmt.Errorf("output format %q is not supported for this command", p.Format)
+		}
+		tab = single{row}
+	}
+
+	switch p.Format {
+	case FormatCSV, FormatTSV:
+		return p.printCSV(tab, true)
+	case FormatName:
+		return p.printNames(tab)
+	}
+
+	wide := p.Format == FormatWide
+	list, isList := v.(interface{ decoration() *Decoration })
+
+	if isList {
+		d := list.decoration()
+		if len(tab.Rows(wide)) == 0 && d.Kind != "" {
+			fmt.Fprintln(p.Out, color.YellowString("No %s found", d.Kind))
+			return nil
+		}
+		fmt.Fprintf(p.Out, "\n")
+		if d.Title != "" {
+			fmt.Fprintln(p.Out, color.CyanString(d.Title))
+		}
+		for _, note := range d.Notes {
+			fmt.Fprintln(p.Out, color.YellowString(note))
+		}
+	}
+
+	p.printTable(tab, wide)
+
+	if isList {
+		d := list.decoration()
+		if d.Kind != "" {
+			fmt.Fprintf(p.Out, "\nTotal: %d %s\n", len(tab.Rows(wide)), d.Kind)
+		}
+		for _, line := range d.Footer {
+			fmt.Fprintln(p.Out, line)
+		}
+	}
+
+	return nil
+}
+
+func (p *Printer) printTable(tab Tabular, wide bool) {
+	header := tab.Header(wide)
+	columns := make([]interface{}, len(header))
+	for i, h := range header {
+		columns[i] = h
+	}
+
+	headerFmt := color.New(color.FgCyan, color.Bold).SprintfFunc()
+	tbl := table.New(columns...)
+	tbl.WithHeaderFormatter(headerFmt)
+	tbl.WithWriter(p.Out)
+
+	for _, row := range tab.Rows(wide) {
+		cells := make([]interface{}, len(row))
+		for i, c := range row {
+			cells[i] = c
+		}
+		tbl.AddRow(cells...)
+	}
+
+	tbl.Print()
+}
+
+// printTemplate evaluates jsonpath, go-template and custom-columns against
+// the JSON document of v
+func (p *Printer) printTemplate(v interface{}) error {
+	data, err := toData(v)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case p.jsonPath != nil:
+		var buf bytes.Buffer
+		if err := p.jsonPath.Execute(&buf, data); err != nil {
+			return err
+		}
+		// End with a newline unless the template already does
+		if buf.Len() > 0 && !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+		_, err = p.Out.Write(buf.Bytes())
+		return err
+	case p.template != nil:
+		return p.template.Execute(p.Out, data)
+	}
+	return printCustomColumns(p.Out, p.columns, data)
+}
+
+// printCSV writes all wide columns, preceded by a header row if header is set
+func (p *Printer) printCSV(tab Tabular, header bool) error {
+	w := csv.NewWriter(p.Out)
+	if p.Format == FormatTSV {
+		w.Comma = '\t'
+	}
+
+	if header {
+		w.Write(tab.Header(true))
+	}
+	for _, row := range tab.Rows(true) {
+		w.Write(row)
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// printNames writes the Name column, or the first column if there is none
+func (p *Printer) printNames(tab Tabular) error {
+	header := tab.Header(false)
+	col := 0
+	for i, h := range header {
+		if h == "Name" {
+			col = i
+			break
+		}
+	}
+
+	for _, row := range tab.Rows(false) {
+		if _, err := fmt.Fprintln(p.Out, row[col]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// toYAML converts v through its JSON form so json tags and custom marshalers
+// apply, keeping the field order of the JSON document
+func toYAML(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	// JSON is valid YAML; decoding into MapSlice preserves key order
+	var doc interface{}
+	switch bytes.TrimSpace(data)[0] {
+	case '{':
+		var m yaml.MapSlice
+		err = yaml.Unmarshal(data, &m)
+		doc = m
+	case '[':
+		var items []interface{}
+		var maps []yaml.MapSlice
+		if err = yaml.Unmarshal(data, &maps); err == nil {
+			for _, m := range maps {
+				items = append(items, m)
+			}
+		} else {
+			err = yaml.Unmarshal(data, &items)
+		}
+		doc = items
+	default:
+		err = yaml.Unmarshal(data, &doc)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(doc)
+}
+
+type single struct {
+	row Row
+}
+
+func (s single) Header(wide bool) []string {
+	return s.row.Columns(wide)
+}
+
+func (s single) Rows(wide bool) [][]string {
+	return [][]string{s.row.Cells(wide)}
+}
@@ -0,0 +1,113 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+)
+
+// Column is one column of -o custom-columns
+type Column struct {
+	Header string
+	Path   *JSONPath
+}
+
+// ParseCustomColumns parses a spec such as "NAME:.name,IP:.addresses"
+func ParseCustomColumns(spec string) ([]Column, error) {
+	var columns []Column
+	for _, part := range strings.Split(spec, ",") {
+		header, path, ok := strings.Cut(part, ":")
+		if !ok || header == "" || path == "" {
+			return nil, fmt.Errorf("invalid custom column %q: expected HEADER:.path", part)
+		}
+
+		parsed, err := ParseJSONPath(strings.TrimSuffix(strings.TrimPrefix(path, "{"), "}"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid custom column %q: %w", part, err)
+		}
+		columns = append(columns, Column{Header: header, Path: parsed})
+	}
+
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("custom-columns requires at least one column")
+	}
+	return columns, nil
+}
+
+// printCustomColumns writes one row per list item, or a single row for an object
+func printCustomColumns(w io.Writer, columns []Column, data interface{}) error {
+	items, ok := data.([]interface{})
+	if !ok {
+		items = []interface{}{data}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+
+	headers := make([]string, len(columns))
+	for i, c := range columns {
+		headers[i] = c.Header
+	}
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+
+	for _, item := range items {
+		cells := make([]string, len(columns))
+		for i, c := range columns {
+			var buf bytes.Buffer
+			if err := c.Path.Execute(&buf, item); err != nil {
+				return err
+			}
+			cells[i] = buf.String()
+			if cells[i] == "" {
+				cells[i] = "<none>"
+			}
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+// parseGoTemplate parses a text/template, from a file if fromFile is set
+func parseGoTemplate(text string, fromFile bool) (*template.Template, error) {
+	if fromFile {
+		data, err := os.ReadFile(text)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template: %w", err)
+		}
+		text = string(data)
+	}
+
+	tpl, err := template.New("output").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid go-template: %w", err)
+	}
+	return tpl, nil
+}
+
+// toData converts v to the generic form of its JSON document, the same data
+// -o json prints. Numbers are kept as json.Number to print them unchanged.
+func toData(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var out interface{}
+	if err := dec.Decode(&out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}